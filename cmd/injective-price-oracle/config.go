@@ -0,0 +1,533 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	log "github.com/InjectiveLabs/suplog"
+	cli "github.com/jawher/mow.cli"
+	"github.com/pelletier/go-toml/v2"
+
+	"github.com/InjectiveLabs/injective-price-oracle/oracle"
+)
+
+const redactedValue = "<redacted>"
+
+// printConfigCmd action prints the effective config of the start command,
+// resolved from CLI flags, env vars and feed configs, with secrets redacted.
+//
+// $ injective-price-oracle print-effective-config --format json
+func printConfigCmd(cmd *cli.Cmd) {
+	var (
+		// Cosmos params
+		cosmosChainID    *string
+		cosmosGRPC       *string
+		cosmosStreamGRPC *string
+		tendermintRPC    *string
+		cosmosGasPrices  *string
+		networkNode      *string
+
+		// Cosmos Key Management
+		cosmosKeyringDir     *string
+		cosmosKeyringAppName *string
+		cosmosKeyringBackend *string
+
+		cosmosKeyFrom       *string
+		cosmosKeyPassphrase *string
+		cosmosPrivKey       *string
+		cosmosUseLedger     *bool
+
+		// External Feeds params
+		feedsDir             *string
+		feedsInline          *string
+		binanceBaseURL       *string
+		binanceRegion        *string
+		defaultPullIntervals *[]string
+		allowedTaskTypes     *[]string
+		httpUserAgent        *string
+		httpDefaultHeaders   *[]string
+		httpDenyPrivateIPs   *bool
+		httpAllow            *[]string
+		httpDeny             *[]string
+
+		// Service params
+		maxConcurrentPulls      *int
+		maxConcurrentBroadcasts *int
+		pullJitter              *string
+		startDelay              *string
+		startDelayWindow        *string
+		onlyFeedTickers         *[]string
+		stateFile               *string
+		auditLog                *string
+		commitStuckThreshold    *string
+		strictFeeds             *bool
+		selfTest                *bool
+		heightLagInterval       *string
+
+		// Alerts
+		alertWebhookURL       *string
+		alertFailureThreshold *int
+		alertCooldown         *string
+
+		// Fee bumps
+		feeBumpFactor     *string
+		feeBumpMaxRetries *int
+
+		// Price transform
+		priceTransform         *string
+		priceTransformFraction *string
+
+		// Mirror network
+		mirrorName           *string
+		mirrorNetworkNode    *string
+		mirrorGRPC           *string
+		mirrorStreamGRPC     *string
+		mirrorTendermintRPC  *string
+		mirrorGasPrices      *string
+		mirrorKeyringDir     *string
+		mirrorKeyringAppName *string
+		mirrorKeyringBackend *string
+		mirrorKeyFrom        *string
+		mirrorKeyPassphrase  *string
+		mirrorPrivKey        *string
+
+		// Metrics
+		statsdPrefix   *string
+		statsdAddr     *string
+		statsdAgent    *string
+		statsdStuckDur *string
+		statsdMocking  *string
+		statsdDisabled *string
+
+		// Stork Oracle websocket params
+		websocketUrl                *string
+		websocketHeader             *string
+		websocketExtraHeaders       *[]string
+		websocketSubscribeMessage   *string
+		websocketReadTimeout        *string
+		websocketCompression        *bool
+		websocketMaxInvalidMessages *int
+		websocketDialTimeout        *string
+		websocketReadLimit          *int
+
+		format *string
+	)
+
+	initCosmosOptions(
+		cmd,
+		&cosmosChainID,
+		&cosmosGRPC,
+		&cosmosStreamGRPC,
+		&tendermintRPC,
+		&cosmosGasPrices,
+		&networkNode,
+	)
+
+	initCosmosKeyOptions(
+		cmd,
+		&cosmosKeyringDir,
+		&cosmosKeyringAppName,
+		&cosmosKeyringBackend,
+		&cosmosKeyFrom,
+		&cosmosKeyPassphrase,
+		&cosmosPrivKey,
+		&cosmosUseLedger,
+	)
+
+	initExternalFeedsOptions(
+		cmd,
+		&binanceBaseURL,
+		&binanceRegion,
+		&feedsDir,
+		&feedsInline,
+		&defaultPullIntervals,
+		&allowedTaskTypes,
+	)
+
+	initHTTPTaskOptions(
+		cmd,
+		&httpUserAgent,
+		&httpDefaultHeaders,
+		&httpDenyPrivateIPs,
+		&httpAllow,
+		&httpDeny,
+	)
+
+	initServiceOptions(
+		cmd,
+		&maxConcurrentPulls,
+		&maxConcurrentBroadcasts,
+		&pullJitter,
+		&startDelay,
+		&startDelayWindow,
+		&onlyFeedTickers,
+		&auditLog,
+		&commitStuckThreshold,
+		&strictFeeds,
+		&selfTest,
+		&heightLagInterval,
+	)
+
+	initStateFileOption(
+		cmd,
+		&stateFile,
+	)
+
+	initAlertOptions(
+		cmd,
+		&alertWebhookURL,
+		&alertFailureThreshold,
+		&alertCooldown,
+	)
+
+	initFeeBumpOptions(
+		cmd,
+		&feeBumpFactor,
+		&feeBumpMaxRetries,
+	)
+
+	initPriceTransformOptions(
+		cmd,
+		&priceTransform,
+		&priceTransformFraction,
+	)
+
+	initMirrorNetworkOptions(
+		cmd,
+		&mirrorName,
+		&mirrorNetworkNode,
+		&mirrorGRPC,
+		&mirrorStreamGRPC,
+		&mirrorTendermintRPC,
+		&mirrorGasPrices,
+		&mirrorKeyringDir,
+		&mirrorKeyringAppName,
+		&mirrorKeyringBackend,
+		&mirrorKeyFrom,
+		&mirrorKeyPassphrase,
+		&mirrorPrivKey,
+	)
+
+	initStatsdOptions(
+		cmd,
+		&statsdPrefix,
+		&statsdAddr,
+		&statsdAgent,
+		&statsdStuckDur,
+		&statsdMocking,
+		&statsdDisabled,
+	)
+
+	initStorkOracleWebSocket(
+		cmd,
+		&websocketUrl,
+		&websocketHeader,
+		&websocketExtraHeaders,
+		&websocketSubscribeMessage,
+		&websocketReadTimeout,
+		&websocketCompression,
+		&websocketMaxInvalidMessages,
+		&websocketDialTimeout,
+		&websocketReadLimit,
+	)
+
+	format = cmd.String(cli.StringOpt{
+		Name:  "format",
+		Desc:  "Output format: json or toml",
+		Value: "json",
+	})
+
+	cmd.Action = func() {
+		setAllowedTaskTypes(*allowedTaskTypes)
+		setHTTPDefaultHeaders(*httpUserAgent, *httpDefaultHeaders)
+		setHTTPAccessPolicy(*httpDenyPrivateIPs, *httpAllow, *httpDeny)
+
+		jitterFraction, _ := strconv.ParseFloat(*pullJitter, 64)
+		feeBumpMultiplier, _ := strconv.ParseFloat(*feeBumpFactor, 64)
+		transformFraction, _ := strconv.ParseFloat(*priceTransformFraction, 64)
+
+		cfg := effectiveConfig{
+			Global: globalConfig{
+				Env:            *envName,
+				LogLevel:       *appLogLevel,
+				SvcWaitTimeout: *svcWaitTimeout,
+			},
+			Cosmos: cosmosConfig{
+				ChainID:       *cosmosChainID,
+				GRPC:          *cosmosGRPC,
+				StreamGRPC:    *cosmosStreamGRPC,
+				TendermintRPC: *tendermintRPC,
+				GasPrices:     *cosmosGasPrices,
+				NetworkNode:   *networkNode,
+			},
+			Keys: keysConfig{
+				KeyringDir:     *cosmosKeyringDir,
+				KeyringAppName: *cosmosKeyringAppName,
+				KeyringBackend: *cosmosKeyringBackend,
+				From:           *cosmosKeyFrom,
+				Passphrase:     redact(*cosmosKeyPassphrase),
+				PrivKey:        redact(*cosmosPrivKey),
+				UseLedger:      *cosmosUseLedger,
+			},
+			Service: serviceConfig{
+				FeedsDir:                *feedsDir,
+				FeedsInline:             redact(*feedsInline),
+				BinanceBaseURL:          *binanceBaseURL,
+				BinanceRegion:           *binanceRegion,
+				DefaultPullIntervals:    parseDefaultPullIntervals(*defaultPullIntervals),
+				AllowedTaskTypes:        *allowedTaskTypes,
+				HTTPUserAgent:           *httpUserAgent,
+				HTTPDefaultHeaders:      redactHeaders(*httpDefaultHeaders),
+				HTTPDenyPrivateIPs:      *httpDenyPrivateIPs,
+				HTTPAllow:               *httpAllow,
+				HTTPDeny:                *httpDeny,
+				MaxConcurrentPulls:      *maxConcurrentPulls,
+				MaxConcurrentBroadcasts: *maxConcurrentBroadcasts,
+				PullJitter:              jitterFraction,
+				StartDelay:              duration(*startDelay, 5*time.Second).String(),
+				StartDelayWindow:        duration(*startDelayWindow, 0).String(),
+				OnlyFeeds:               *onlyFeedTickers,
+				StateFile:               *stateFile,
+				AuditLog:                *auditLog,
+				CommitStuckThreshold:    duration(*commitStuckThreshold, 5*time.Minute).String(),
+				StrictFeeds:             *strictFeeds,
+				SelfTest:                *selfTest,
+				HeightLagInterval:       duration(*heightLagInterval, 0).String(),
+				PriceTransform:          *priceTransform,
+				PriceTransformFraction:  transformFraction,
+			},
+			Alert: alertConfig{
+				WebhookURL:       redact(*alertWebhookURL),
+				FailureThreshold: *alertFailureThreshold,
+				Cooldown:         duration(*alertCooldown, 15*time.Minute).String(),
+			},
+			FeeBump: feeBumpConfig{
+				Factor:     feeBumpMultiplier,
+				MaxRetries: *feeBumpMaxRetries,
+			},
+			Mirror: mirrorConfig{
+				Name:          *mirrorName,
+				NetworkNode:   *mirrorNetworkNode,
+				GRPC:          *mirrorGRPC,
+				StreamGRPC:    *mirrorStreamGRPC,
+				TendermintRPC: *mirrorTendermintRPC,
+				GasPrices:     *mirrorGasPrices,
+				Keys: keysConfig{
+					KeyringDir:     *mirrorKeyringDir,
+					KeyringAppName: *mirrorKeyringAppName,
+					KeyringBackend: *mirrorKeyringBackend,
+					From:           *mirrorKeyFrom,
+					Passphrase:     redact(*mirrorKeyPassphrase),
+					PrivKey:        redact(*mirrorPrivKey),
+				},
+			},
+			Statsd: statsdConfig{
+				Prefix:   *statsdPrefix,
+				Addr:     *statsdAddr,
+				Agent:    *statsdAgent,
+				StuckDur: *statsdStuckDur,
+				Mocking:  toBool(*statsdMocking),
+				Disabled: toBool(*statsdDisabled),
+			},
+			Stork: storkConfig{
+				WebsocketURL:                *websocketUrl,
+				WebsocketHeader:             redact(*websocketHeader),
+				WebsocketExtraHeaders:       redactHeaders(*websocketExtraHeaders),
+				WebsocketSubscribeMessage:   *websocketSubscribeMessage,
+				WebsocketReadTimeout:        duration(*websocketReadTimeout, 0).String(),
+				WebsocketCompression:        *websocketCompression,
+				WebsocketMaxInvalidMessages: *websocketMaxInvalidMessages,
+				WebsocketDialTimeout:        duration(*websocketDialTimeout, 0).String(),
+				WebsocketReadLimit:          *websocketReadLimit,
+			},
+			Feeds: []feedConfig{},
+		}
+
+		if len(*feedsDir) > 0 || len(*feedsInline) > 0 {
+			err := walkFeedConfigs(*feedsDir, *feedsInline, parseDefaultPullIntervals(*defaultPullIntervals), defaultRegions(*binanceRegion), func(name string, feedCfg *oracle.FeedConfig, err error) {
+				feed := feedConfig{
+					File: name,
+				}
+
+				if err == nil {
+					var pricePuller oracle.PricePuller
+					if pricePuller, err = oracle.NewPricePuller(feedCfg, nil); err == nil {
+						feed.Ticker = feedCfg.Ticker
+						feed.Provider = pricePuller.ProviderName()
+						feed.OracleType = pricePuller.OracleType().String()
+						feed.PullInterval = pricePuller.Interval().String()
+					}
+				}
+
+				if err != nil {
+					feed.Error = err.Error()
+				}
+
+				cfg.Feeds = append(cfg.Feeds, feed)
+			})
+			if err != nil {
+				log.WithError(err).Fatalln("failed to read feeds dir")
+			}
+		}
+
+		var (
+			out []byte
+			err error
+		)
+
+		switch *format {
+		case "json":
+			out, err = json.MarshalIndent(cfg, "", "  ")
+		case "toml":
+			out, err = toml.Marshal(cfg)
+		default:
+			log.Fatalf("unsupported format: %s (expected json or toml)", *format)
+		}
+
+		if err != nil {
+			log.WithError(err).Fatalln("failed to marshal effective config")
+		}
+
+		fmt.Println(string(out))
+	}
+}
+
+type effectiveConfig struct {
+	Global  globalConfig  `json:"global" toml:"global"`
+	Cosmos  cosmosConfig  `json:"cosmos" toml:"cosmos"`
+	Keys    keysConfig    `json:"keys" toml:"keys"`
+	Service serviceConfig `json:"service" toml:"service"`
+	Alert   alertConfig   `json:"alert" toml:"alert"`
+	FeeBump feeBumpConfig `json:"feeBump" toml:"feeBump"`
+	Mirror  mirrorConfig  `json:"mirror" toml:"mirror"`
+	Statsd  statsdConfig  `json:"statsd" toml:"statsd"`
+	Stork   storkConfig   `json:"stork" toml:"stork"`
+	Feeds   []feedConfig  `json:"feeds" toml:"feeds"`
+}
+
+type globalConfig struct {
+	Env            string `json:"env" toml:"env"`
+	LogLevel       string `json:"logLevel" toml:"logLevel"`
+	SvcWaitTimeout string `json:"svcWaitTimeout" toml:"svcWaitTimeout"`
+}
+
+type cosmosConfig struct {
+	ChainID       string `json:"chainId" toml:"chainId"`
+	GRPC          string `json:"grpc" toml:"grpc"`
+	StreamGRPC    string `json:"streamGrpc" toml:"streamGrpc"`
+	TendermintRPC string `json:"tendermintRpc" toml:"tendermintRpc"`
+	GasPrices     string `json:"gasPrices" toml:"gasPrices"`
+	NetworkNode   string `json:"networkNode" toml:"networkNode"`
+}
+
+type keysConfig struct {
+	KeyringDir     string `json:"keyringDir" toml:"keyringDir"`
+	KeyringAppName string `json:"keyringAppName" toml:"keyringAppName"`
+	KeyringBackend string `json:"keyringBackend" toml:"keyringBackend"`
+	From           string `json:"from" toml:"from"`
+	Passphrase     string `json:"passphrase" toml:"passphrase"`
+	PrivKey        string `json:"privKey" toml:"privKey"`
+	UseLedger      bool   `json:"useLedger" toml:"useLedger"`
+}
+
+type serviceConfig struct {
+	FeedsDir                string            `json:"feedsDir" toml:"feedsDir"`
+	FeedsInline             string            `json:"feedsInline,omitempty" toml:"feedsInline,omitempty"`
+	BinanceBaseURL          string            `json:"binanceBaseUrl" toml:"binanceBaseUrl"`
+	BinanceRegion           string            `json:"binanceRegion" toml:"binanceRegion"`
+	DefaultPullIntervals    map[string]string `json:"defaultPullIntervals" toml:"defaultPullIntervals"`
+	AllowedTaskTypes        []string          `json:"allowedTaskTypes" toml:"allowedTaskTypes"`
+	HTTPUserAgent           string            `json:"httpUserAgent" toml:"httpUserAgent"`
+	HTTPDefaultHeaders      []string          `json:"httpDefaultHeaders" toml:"httpDefaultHeaders"`
+	HTTPDenyPrivateIPs      bool              `json:"httpDenyPrivateIPs" toml:"httpDenyPrivateIPs"`
+	HTTPAllow               []string          `json:"httpAllow" toml:"httpAllow"`
+	HTTPDeny                []string          `json:"httpDeny" toml:"httpDeny"`
+	MaxConcurrentPulls      int               `json:"maxConcurrentPulls" toml:"maxConcurrentPulls"`
+	MaxConcurrentBroadcasts int               `json:"maxConcurrentBroadcasts" toml:"maxConcurrentBroadcasts"`
+	PullJitter              float64           `json:"pullJitter" toml:"pullJitter"`
+	StartDelay              string            `json:"startDelay" toml:"startDelay"`
+	StartDelayWindow        string            `json:"startDelayWindow" toml:"startDelayWindow"`
+	OnlyFeeds               []string          `json:"onlyFeeds" toml:"onlyFeeds"`
+	StateFile               string            `json:"stateFile" toml:"stateFile"`
+	AuditLog                string            `json:"auditLog" toml:"auditLog"`
+	CommitStuckThreshold    string            `json:"commitStuckThreshold" toml:"commitStuckThreshold"`
+	StrictFeeds             bool              `json:"strictFeeds" toml:"strictFeeds"`
+	SelfTest                bool              `json:"selfTest" toml:"selfTest"`
+	HeightLagInterval       string            `json:"heightLagInterval" toml:"heightLagInterval"`
+	PriceTransform          string            `json:"priceTransform" toml:"priceTransform"`
+	PriceTransformFraction  float64           `json:"priceTransformFraction" toml:"priceTransformFraction"`
+}
+
+type alertConfig struct {
+	WebhookURL       string `json:"webhookUrl" toml:"webhookUrl"`
+	FailureThreshold int    `json:"failureThreshold" toml:"failureThreshold"`
+	Cooldown         string `json:"cooldown" toml:"cooldown"`
+}
+
+type feeBumpConfig struct {
+	Factor     float64 `json:"factor" toml:"factor"`
+	MaxRetries int     `json:"maxRetries" toml:"maxRetries"`
+}
+
+type mirrorConfig struct {
+	Name          string     `json:"name" toml:"name"`
+	NetworkNode   string     `json:"networkNode" toml:"networkNode"`
+	GRPC          string     `json:"grpc" toml:"grpc"`
+	StreamGRPC    string     `json:"streamGrpc" toml:"streamGrpc"`
+	TendermintRPC string     `json:"tendermintRpc" toml:"tendermintRpc"`
+	GasPrices     string     `json:"gasPrices" toml:"gasPrices"`
+	Keys          keysConfig `json:"keys" toml:"keys"`
+}
+
+type statsdConfig struct {
+	Prefix   string `json:"prefix" toml:"prefix"`
+	Addr     string `json:"addr" toml:"addr"`
+	Agent    string `json:"agent" toml:"agent"`
+	StuckDur string `json:"stuckDur" toml:"stuckDur"`
+	Mocking  bool   `json:"mocking" toml:"mocking"`
+	Disabled bool   `json:"disabled" toml:"disabled"`
+}
+
+type storkConfig struct {
+	WebsocketURL                string   `json:"websocketUrl" toml:"websocketUrl"`
+	WebsocketHeader             string   `json:"websocketHeader" toml:"websocketHeader"`
+	WebsocketExtraHeaders       []string `json:"websocketExtraHeaders" toml:"websocketExtraHeaders"`
+	WebsocketSubscribeMessage   string   `json:"websocketSubscribeMessage" toml:"websocketSubscribeMessage"`
+	WebsocketReadTimeout        string   `json:"websocketReadTimeout" toml:"websocketReadTimeout"`
+	WebsocketCompression        bool     `json:"websocketCompression" toml:"websocketCompression"`
+	WebsocketMaxInvalidMessages int      `json:"websocketMaxInvalidMessages" toml:"websocketMaxInvalidMessages"`
+	WebsocketDialTimeout        string   `json:"websocketDialTimeout" toml:"websocketDialTimeout"`
+	WebsocketReadLimit          int      `json:"websocketReadLimit" toml:"websocketReadLimit"`
+}
+
+type feedConfig struct {
+	File         string `json:"file" toml:"file"`
+	Ticker       string `json:"ticker,omitempty" toml:"ticker,omitempty"`
+	Provider     string `json:"provider,omitempty" toml:"provider,omitempty"`
+	OracleType   string `json:"oracleType,omitempty" toml:"oracleType,omitempty"`
+	PullInterval string `json:"pullInterval,omitempty" toml:"pullInterval,omitempty"`
+	Error        string `json:"error,omitempty" toml:"error,omitempty"`
+}
+
+// redact hides a secret value, keeping only the fact that it has been set.
+func redact(s string) string {
+	if len(s) == 0 {
+		return ""
+	}
+
+	return redactedValue
+}
+
+// redactHeaders hides values of "Key: Value" header pairs, since those usually carry API keys.
+func redactHeaders(pairs []string) []string {
+	redacted := make([]string, 0, len(pairs))
+	for _, pair := range pairs {
+		key, _, _ := strings.Cut(pair, ":")
+		redacted = append(redacted, strings.TrimSpace(key)+": "+redactedValue)
+	}
+
+	return redacted
+}
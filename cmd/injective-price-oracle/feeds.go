@@ -0,0 +1,224 @@
+package main
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"text/tabwriter"
+	"time"
+
+	log "github.com/InjectiveLabs/suplog"
+	cli "github.com/jawher/mow.cli"
+	"github.com/pkg/errors"
+
+	"github.com/InjectiveLabs/injective-price-oracle/oracle"
+)
+
+// feedsCmd action prints a table of all feeds discovered in the feeds dir and inline feed configs.
+//
+// $ injective-price-oracle feeds [--feeds-dir <DIR>] [--feeds-inline <TOML>] [--state-file <FILE>]
+func feedsCmd(cmd *cli.Cmd) {
+	var (
+		feedsDir             *string
+		feedsInline          *string
+		binanceBaseURL       *string
+		binanceRegion        *string
+		defaultPullIntervals *[]string
+		allowedTaskTypes     *[]string
+		httpUserAgent        *string
+		httpDefaultHeaders   *[]string
+		httpDenyPrivateIPs   *bool
+		httpAllow            *[]string
+		httpDeny             *[]string
+		stateFile            *string
+	)
+
+	initExternalFeedsOptions(
+		cmd,
+		&binanceBaseURL,
+		&binanceRegion,
+		&feedsDir,
+		&feedsInline,
+		&defaultPullIntervals,
+		&allowedTaskTypes,
+	)
+
+	initHTTPTaskOptions(
+		cmd,
+		&httpUserAgent,
+		&httpDefaultHeaders,
+		&httpDenyPrivateIPs,
+		&httpAllow,
+		&httpDeny,
+	)
+
+	initStateFileOption(
+		cmd,
+		&stateFile,
+	)
+
+	cmd.Action = func() {
+		setAllowedTaskTypes(*allowedTaskTypes)
+		setHTTPDefaultHeaders(*httpUserAgent, *httpDefaultHeaders)
+		setHTTPAccessPolicy(*httpDenyPrivateIPs, *httpAllow, *httpDeny)
+
+		if len(*feedsDir) == 0 && len(*feedsInline) == 0 {
+			log.Fatalln("feeds must be specified with --feeds-dir or --feeds-inline")
+		}
+
+		lastSubmitted := map[string]oracle.SubmittedPrice{}
+		if len(*stateFile) > 0 {
+			var err error
+			if lastSubmitted, err = oracle.LoadSubmittedPrices(*stateFile); err != nil {
+				log.WithError(err).Fatalln("failed to load state file")
+			}
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		_, _ = fmt.Fprintln(w, "FILE\tTICKER\tPROVIDER\tORACLE TYPE\tPULL INTERVAL\tLAST SUBMITTED\tSTATUS")
+
+		var invalid int
+		err := walkFeedConfigs(*feedsDir, *feedsInline, parseDefaultPullIntervals(*defaultPullIntervals), defaultRegions(*binanceRegion), func(filename string, feedCfg *oracle.FeedConfig, err error) {
+			if err != nil {
+				invalid++
+				_, _ = fmt.Fprintf(w, "%s\t-\t-\t-\t-\t-\tINVALID: %v\n", filename, err)
+				return
+			}
+
+			pricePuller, err := oracle.NewPricePuller(feedCfg, nil)
+			if err != nil {
+				invalid++
+				_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t-\t-\t-\tINVALID: %v\n", filename, feedCfg.Ticker, feedCfg.ProviderName, err)
+				return
+			}
+
+			submitted := "-"
+			if last, ok := lastSubmitted[feedCfg.Ticker]; ok {
+				submitted = fmt.Sprintf("%s @ %s", last.Price, last.Timestamp.UTC().Format(time.RFC3339))
+			}
+
+			_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\tOK\n",
+				filename,
+				feedCfg.Ticker,
+				pricePuller.ProviderName(),
+				pricePuller.OracleType().String(),
+				pricePuller.Interval(),
+				submitted,
+			)
+		})
+		if err != nil {
+			log.WithError(err).Fatalln("failed to read feeds dir")
+		}
+
+		_ = w.Flush()
+
+		if invalid > 0 {
+			log.Warningf("found %d invalid feed configs", invalid)
+		}
+	}
+}
+
+// inlineFeedsName names feed configs of --feeds-inline, in place of the file name.
+const inlineFeedsName = "inline"
+
+// walkFeedConfigs walks the feeds dir, then the inline feed configs, and calls fn for every feed config found.
+// The name is the file name (or "inline"), suffixed with the feed index for files declaring many feeds.
+// Defaults of the feeds dir are merged into every feed config. If a file can't be parsed, fn is called once
+// with a nil config and the parse error.
+func walkFeedConfigs(
+	feedsDir, feedsInline string,
+	defaultPullIntervals, defaultRegions map[string]string,
+	fn func(name string, feedCfg *oracle.FeedConfig, err error),
+) error {
+	defaults, err := loadFeedDefaults(feedsDir)
+	if err != nil {
+		return err
+	}
+
+	if len(feedsDir) > 0 {
+		defaultsPath := filepath.Join(feedsDir, oracle.FeedDefaultsFile)
+
+		err := filepath.WalkDir(feedsDir, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			} else if d.IsDir() || path == defaultsPath {
+				return nil
+			}
+
+			format := oracle.FeedConfigFormat(path)
+			if len(format) == 0 {
+				return nil
+			}
+
+			cfgBody, err := os.ReadFile(path)
+			if err != nil {
+				err = errors.Wrapf(err, "failed to read dynamic feed config")
+				return err
+			}
+
+			walkFeedConfigBody(filepath.Base(path), cfgBody, format, defaults, defaultPullIntervals, defaultRegions, fn)
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(feedsInline) > 0 {
+		walkFeedConfigBody(inlineFeedsName, []byte(feedsInline), oracle.FeedConfigFormatTOML, defaults, defaultPullIntervals, defaultRegions, fn)
+	}
+
+	return nil
+}
+
+// walkFeedConfigBody parses a single feed config file body and calls fn for every feed config declared in it.
+func walkFeedConfigBody(
+	filename string,
+	cfgBody []byte,
+	format string,
+	defaults *oracle.FeedConfig,
+	defaultPullIntervals, defaultRegions map[string]string,
+	fn func(name string, feedCfg *oracle.FeedConfig, err error),
+) {
+	feedCfgs, err := oracle.ParseFeedConfigsWithDefaults(cfgBody, format, defaults)
+	if err != nil {
+		fn(filename, nil, err)
+		return
+	}
+
+	for _, feedCfg := range feedCfgs {
+		feedCfg.SetDefaultPullInterval(defaultPullIntervals)
+		feedCfg.SetDefaultRegion(defaultRegions)
+	}
+
+	if len(feedCfgs) == 1 {
+		fn(filename, feedCfgs[0], nil)
+		return
+	}
+
+	for i, feedCfg := range feedCfgs {
+		fn(fmt.Sprintf("%s[%d]", filename, i), feedCfg, nil)
+	}
+}
+
+// loadFeedDefaults parses the defaults file of the feeds dir, if there's one.
+func loadFeedDefaults(feedsDir string) (*oracle.FeedConfig, error) {
+	if len(feedsDir) == 0 {
+		return nil, nil
+	}
+
+	cfgBody, err := os.ReadFile(filepath.Join(feedsDir, oracle.FeedDefaultsFile))
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.Wrap(err, "failed to read feed defaults")
+	}
+
+	defaults, err := oracle.ParseFeedDefaults(cfgBody, oracle.FeedConfigFormatTOML)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to parse feed defaults %s", oracle.FeedDefaultsFile)
+	}
+
+	return defaults, nil
+}
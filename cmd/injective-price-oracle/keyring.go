@@ -0,0 +1,210 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	cryptocodec "github.com/InjectiveLabs/sdk-go/chain/crypto/codec"
+	"github.com/InjectiveLabs/sdk-go/chain/crypto/hd"
+	chainclient "github.com/InjectiveLabs/sdk-go/client/chain"
+	log "github.com/InjectiveLabs/suplog"
+	"github.com/cosmos/cosmos-sdk/codec"
+	"github.com/cosmos/cosmos-sdk/crypto/keyring"
+	cli "github.com/jawher/mow.cli"
+	"github.com/pkg/errors"
+)
+
+// keyringFileDirName is the dir of the file keyring backend inside the keyring dir.
+const keyringFileDirName = "keyring-file"
+
+// keyringCmd groups commands managing the Cosmos keyring of the oracle.
+func keyringCmd(cmd *cli.Cmd) {
+	cmd.Command("rotate-passphrase", "Re-encrypts the file keyring with a new passphrase, backing up the old keyring.", keyringRotatePassphraseCmd)
+}
+
+// keyringRotatePassphraseCmd action decrypts all keys of the file keyring with the current passphrase and
+// re-encrypts them with the new one. The old keyring is kept next to the new one as a backup.
+//
+// $ injective-price-oracle keyring rotate-passphrase --cosmos-keyring-dir <DIR> --cosmos-from-passphrase <OLD> --new-passphrase <NEW>
+func keyringRotatePassphraseCmd(cmd *cli.Cmd) {
+	var (
+		// Cosmos Key Management
+		cosmosKeyringDir     *string
+		cosmosKeyringAppName *string
+		cosmosKeyringBackend *string
+
+		cosmosKeyFrom       *string
+		cosmosKeyPassphrase *string
+		cosmosPrivKey       *string
+		cosmosUseLedger     *bool
+
+		newPassphrase *string
+		yes           *bool
+	)
+
+	initCosmosKeyOptions(
+		cmd,
+		&cosmosKeyringDir,
+		&cosmosKeyringAppName,
+		&cosmosKeyringBackend,
+		&cosmosKeyFrom,
+		&cosmosKeyPassphrase,
+		&cosmosPrivKey,
+		&cosmosUseLedger,
+	)
+
+	newPassphrase = cmd.String(cli.StringOpt{
+		Name:   "new-passphrase",
+		Desc:   "Specify the new keyring passphrase.",
+		EnvVar: "ORACLE_COSMOS_NEW_PASSPHRASE",
+	})
+
+	yes = cmd.Bool(cli.BoolOpt{
+		Name:  "y yes",
+		Desc:  "Skip the confirmation prompt.",
+		Value: false,
+	})
+
+	cmd.Action = func() {
+		switch {
+		case *cosmosKeyringBackend != "file":
+			log.Fatalln("passphrase rotation is supported only for the file keyring backend")
+		case len(*cosmosPrivKey) > 0 || *cosmosUseLedger:
+			log.Fatalln("passphrase rotation is not supported for private key and ledger options")
+		case len(*cosmosKeyPassphrase) == 0:
+			log.Fatalln("current passphrase must be specified with --cosmos-from-passphrase")
+		case len(*newPassphrase) == 0:
+			log.Fatalln("new passphrase must be specified with --new-passphrase")
+		case *newPassphrase == *cosmosKeyPassphrase:
+			log.Fatalln("new passphrase must differ from the current one")
+		}
+
+		keyringDir, err := filepath.Abs(*cosmosKeyringDir)
+		if err != nil {
+			log.WithError(err).Fatalln("failed to resolve keyring dir")
+		}
+
+		// opening the keyring with the from key checks the current passphrase before anything is changed
+		_, oldKeyring, err := chainclient.InitCosmosKeyring(
+			keyringDir,
+			*cosmosKeyringAppName,
+			*cosmosKeyringBackend,
+			*cosmosKeyFrom,
+			*cosmosKeyPassphrase,
+			"",
+			false,
+		)
+		if err != nil {
+			log.WithError(err).Fatalln("failed to open keyring with the current passphrase")
+		}
+
+		records, err := oldKeyring.List()
+		if err != nil {
+			log.WithError(err).Fatalln("failed to list keyring keys")
+		}
+
+		fileDir := filepath.Join(keyringDir, keyringFileDirName)
+		backupDir := fmt.Sprintf("%s.bak-%d", fileDir, time.Now().Unix())
+
+		if !*yes && !stdinConfirm(fmt.Sprintf(
+			"Re-encrypt %d keys of %s with the new passphrase, backing up the old keyring to %s? [y/N]: ",
+			len(records), fileDir, backupDir,
+		)) {
+			log.Infoln("passphrase rotation cancelled")
+			return
+		}
+
+		// new keyring is written aside and swapped in only once all keys are re-encrypted
+		tmpDir, err := os.MkdirTemp(keyringDir, ".keyring-rotate-")
+		if err != nil {
+			log.WithError(err).Fatalln("failed to create temp keyring dir")
+		}
+		defer os.RemoveAll(tmpDir)
+
+		if err := reencryptKeyring(oldKeyring, records, *cosmosKeyringAppName, tmpDir, *newPassphrase); err != nil {
+			log.WithError(err).Fatalln("failed to re-encrypt keyring, the old keyring is left intact")
+		}
+
+		if err := os.Rename(fileDir, backupDir); err != nil {
+			log.WithError(err).Fatalln("failed to back up the old keyring")
+		}
+
+		if err := os.Rename(filepath.Join(tmpDir, keyringFileDirName), fileDir); err != nil {
+			log.WithError(err).WithField("backup", backupDir).Fatalln("failed to move the new keyring in place, restore the old one from backup")
+		}
+
+		if _, _, err := chainclient.InitCosmosKeyring(
+			keyringDir,
+			*cosmosKeyringAppName,
+			*cosmosKeyringBackend,
+			*cosmosKeyFrom,
+			*newPassphrase,
+			"",
+			false,
+		); err != nil {
+			log.WithError(err).WithField("backup", backupDir).Fatalln("failed to open the new keyring, restore the old one from backup")
+		}
+
+		log.WithFields(log.Fields{
+			"keys":   len(records),
+			"backup": backupDir,
+		}).Infoln("keyring passphrase rotated")
+	}
+}
+
+// reencryptKeyring copies all keys of the keyring into a new file keyring in the given dir,
+// encrypted with the new passphrase.
+func reencryptKeyring(kb keyring.Keyring, records []*keyring.Record, appName, dir, newPassphrase string) error {
+	registry := chainclient.NewInterfaceRegistry()
+	cryptocodec.RegisterInterfaces(registry)
+
+	newKeyring, err := keyring.New(
+		appName,
+		keyring.BackendFile,
+		dir,
+		newPassReader(newPassphrase),
+		codec.NewProtoCodec(registry),
+		hd.EthSecp256k1Option(),
+	)
+	if err != nil {
+		return errors.Wrap(err, "failed to init new keyring")
+	}
+
+	// armor passphrase only lives in memory while the key is moved between keyrings
+	armorPassphrase := randPhrase(64)
+
+	for _, record := range records {
+		switch record.GetType() {
+		case keyring.TypeLocal:
+			armor, err := kb.ExportPrivKeyArmor(record.Name, armorPassphrase)
+			if err != nil {
+				return errors.Wrapf(err, "failed to export key '%s'", record.Name)
+			}
+
+			if err := newKeyring.ImportPrivKey(record.Name, armor, armorPassphrase); err != nil {
+				return errors.Wrapf(err, "failed to import key '%s'", record.Name)
+			}
+		case keyring.TypeOffline, keyring.TypeMulti:
+			pubKey, err := record.GetPubKey()
+			if err != nil {
+				return errors.Wrapf(err, "failed to get public key of '%s'", record.Name)
+			}
+
+			if record.GetType() == keyring.TypeMulti {
+				_, err = newKeyring.SaveMultisig(record.Name, pubKey)
+			} else {
+				_, err = newKeyring.SaveOfflineKey(record.Name, pubKey)
+			}
+
+			if err != nil {
+				return errors.Wrapf(err, "failed to save key '%s'", record.Name)
+			}
+		default:
+			return errors.Errorf("'%s' key has unsupported type: %s", record.Name, record.GetType())
+		}
+	}
+
+	return nil
+}
@@ -31,7 +31,14 @@ func main() {
 	}
 
 	app.Command("start", "Starts the oracle main loop.", oracleCmd)
+	app.Command("feeds", "Lists all feeds found in the feeds dir, flagging invalid ones.", feedsCmd)
+	app.Command("validate", "Validates feed config files without pulling prices, printing all problems found.", validateCmd)
+	app.Command("print-effective-config", "Prints the resolved config of the start command, with secrets redacted.", printConfigCmd)
 	app.Command("probe", "Validates target TOML file spec and runs it once, printing the result.", probeCmd)
+	app.Command("batch-probe", "Validates all feeds in the feeds dir and pulls each of them once, printing a summary.", batchProbeCmd)
+	app.Command("simulate-broadcast", "Pulls all feed prices once and simulates the relay Tx on chain, without broadcasting.", simulateCmd)
+	app.Command("submit", "Relays a single price of a ticker once and exits.", submitCmd)
+	app.Command("keyring", "Manages the Cosmos keyring of the oracle.", keyringCmd)
 	app.Command("version", "Print the version information and exit.", versionCmd)
 
 	_ = app.Run(os.Args)
@@ -11,6 +11,9 @@ import (
 	// _ "net/http/pprof"
 
 	"github.com/InjectiveLabs/metrics"
+
+	"github.com/InjectiveLabs/injective-price-oracle/oracle"
+	"github.com/InjectiveLabs/injective-price-oracle/pipeline"
 )
 
 // startMetricsGathering initializes metric reporting client,
@@ -29,6 +32,8 @@ func startMetricsGathering(
 		return
 	}
 
+	pipeline.SetHTTPMetricsReporter(oracle.HTTPTaskMetrics{})
+
 	go func() {
 		for {
 			hostname, _ := os.Hostname()
@@ -1,6 +1,11 @@
 package main
 
-import cli "github.com/jawher/mow.cli"
+import (
+	cli "github.com/jawher/mow.cli"
+
+	"github.com/InjectiveLabs/injective-price-oracle/pipeline"
+	"github.com/InjectiveLabs/injective-price-oracle/version"
+)
 
 // initGlobalOptions defines some global CLI options, that are useful for most parts of the app.
 // Before adding option to there, consider moving it into the actual Cmd.
@@ -143,7 +148,11 @@ func initCosmosKeyOptions(
 func initExternalFeedsOptions(
 	cmd *cli.Cmd,
 	binanceBaseURL **string,
+	binanceRegion **string,
 	feedsDir **string,
+	feedsInline **string,
+	defaultPullIntervals **[]string,
+	allowedTaskTypes **[]string,
 ) {
 	*binanceBaseURL = cmd.String(cli.StringOpt{
 		Name:   "binance-url",
@@ -151,11 +160,352 @@ func initExternalFeedsOptions(
 		EnvVar: "ORACLE_BINANCE_URL",
 	})
 
+	*binanceRegion = cmd.String(cli.StringOpt{
+		Name:   "binance-region",
+		Desc:   "Default region of binance feeds that don't set region: global or us (Binance.US)",
+		EnvVar: "ORACLE_BINANCE_REGION",
+	})
+
 	*feedsDir = cmd.String(cli.StringOpt{
 		Name:   "feeds-dir",
-		Desc:   "Path to feeds configuration files in TOML format",
+		Desc:   "Path to feeds configuration files in TOML, YAML or JSON format",
 		EnvVar: "ORACLE_FEEDS_DIR",
 	})
+
+	*feedsInline = cmd.String(cli.StringOpt{
+		Name:   "feeds-inline",
+		Desc:   "Inline feed configs in TOML format, loaded along with the feeds dir",
+		EnvVar: "ORACLE_FEEDS_INLINE",
+	})
+
+	*defaultPullIntervals = cmd.Strings(cli.StringsOpt{
+		Name:   "default-pull-interval",
+		Desc:   "Default pull interval of feeds of a provider that don't set pullInterval, as provider=interval (e.g. stork=10s), can be repeated",
+		EnvVar: "ORACLE_DEFAULT_PULL_INTERVALS",
+		Value:  []string{},
+	})
+
+	*allowedTaskTypes = cmd.Strings(cli.StringsOpt{
+		Name:   "allowed-task-type",
+		Desc:   "Only allow dynamic feed pipelines to use the specified task types (e.g. jsonparse), can be repeated. All task types are allowed if not set.",
+		EnvVar: "ORACLE_ALLOWED_TASK_TYPES",
+		Value:  []string{},
+	})
+}
+
+// initHTTPTaskOptions sets options of requests made by the http task of dynamic feeds,
+// restricting where it may connect to.
+func initHTTPTaskOptions(
+	cmd *cli.Cmd,
+	httpUserAgent **string,
+	httpDefaultHeaders **[]string,
+	httpDenyPrivateIPs **bool,
+	httpAllow **[]string,
+	httpDeny **[]string,
+) {
+	*httpUserAgent = cmd.String(cli.StringOpt{
+		Name:   "http-user-agent",
+		Desc:   "User-Agent header of http task requests (empty = Go's default)",
+		EnvVar: "ORACLE_HTTP_USER_AGENT",
+		Value:  "injective-price-oracle/" + version.AppVersion,
+	})
+
+	*httpDefaultHeaders = cmd.Strings(cli.StringsOpt{
+		Name:   "http-default-header",
+		Desc:   "Header in \"Key: Value\" format sent with every http task request, unless set by the task's headerMap, can be repeated",
+		EnvVar: "ORACLE_HTTP_DEFAULT_HEADERS",
+		Value:  []string{},
+	})
+
+	*httpDenyPrivateIPs = cmd.Bool(cli.BoolOpt{
+		Name:   "http-deny-private-ips",
+		Desc:   "Reject http task requests to loopback, private, link-local (e.g. cloud metadata) and multicast addresses",
+		EnvVar: "ORACLE_HTTP_DENY_PRIVATE_IPS",
+		Value:  false,
+	})
+
+	*httpAllow = cmd.Strings(cli.StringsOpt{
+		Name:   "http-allow",
+		Desc:   "Only allow http task requests to the specified hosts (e.g. *.binance.com) or IP ranges (e.g. 10.1.0.0/16), can be repeated. All are allowed if not set.",
+		EnvVar: "ORACLE_HTTP_ALLOW",
+		Value:  []string{},
+	})
+
+	*httpDeny = cmd.Strings(cli.StringsOpt{
+		Name:   "http-deny",
+		Desc:   "Reject http task requests to the specified hosts or IP ranges, can be repeated. Takes precedence over --http-allow.",
+		EnvVar: "ORACLE_HTTP_DENY",
+		Value:  []string{},
+	})
+}
+
+// initServiceOptions sets options for the oracle service main loop.
+func initServiceOptions(
+	cmd *cli.Cmd,
+	maxConcurrentPulls **int,
+	maxConcurrentBroadcasts **int,
+	pullJitter **string,
+	startDelay **string,
+	startDelayWindow **string,
+	onlyFeedTickers **[]string,
+	auditLog **string,
+	commitStuckThreshold **string,
+	strictFeeds **bool,
+	selfTest **bool,
+	heightLagInterval **string,
+) {
+	*maxConcurrentPulls = cmd.Int(cli.IntOpt{
+		Name:   "max-concurrent-pulls",
+		Desc:   "Maximum number of price pulls running at once across all feeds (0 = unlimited)",
+		EnvVar: "ORACLE_MAX_CONCURRENT_PULLS",
+		Value:  0,
+	})
+
+	*maxConcurrentBroadcasts = cmd.Int(cli.IntOpt{
+		Name:   "max-concurrent-broadcasts",
+		Desc:   "Maximum number of price batches broadcast at once, so a slow broadcast doesn't stall batching",
+		EnvVar: "ORACLE_MAX_CONCURRENT_BROADCASTS",
+		Value:  1,
+	})
+
+	*pullJitter = cmd.String(cli.StringOpt{
+		Name:   "pull-jitter",
+		Desc:   "Fraction of the feed pull interval used to randomly spread pulls over time, between 0 and 1 (0 = disabled)",
+		EnvVar: "ORACLE_PULL_JITTER",
+		Value:  "0",
+	})
+
+	*startDelay = cmd.String(cli.StringOpt{
+		Name:   "start-delay",
+		Desc:   "Delay of the first pull of each feed after start",
+		EnvVar: "ORACLE_START_DELAY",
+		Value:  "5s",
+	})
+
+	*startDelayWindow = cmd.String(cli.StringOpt{
+		Name:   "start-delay-window",
+		Desc:   "Window over which the first pulls of feeds are randomly spread after the start delay, capped at the feed pull interval (0 = spread by pull jitter)",
+		EnvVar: "ORACLE_START_DELAY_WINDOW",
+		Value:  "10s",
+	})
+
+	*onlyFeedTickers = cmd.Strings(cli.StringsOpt{
+		Name:   "only-feed",
+		Desc:   "Only start pullers for the specified tickers (e.g. INJ/USDT), can be repeated. All loaded feeds are started if not set.",
+		EnvVar: "ORACLE_ONLY_FEEDS",
+		Value:  []string{},
+	})
+
+	*auditLog = cmd.String(cli.StringOpt{
+		Name:   "audit-log",
+		Desc:   "Append-only JSON lines file recording every broadcast price batch (empty = disabled)",
+		EnvVar: "ORACLE_AUDIT_LOG",
+		Value:  "",
+	})
+
+	*commitStuckThreshold = cmd.String(cli.StringOpt{
+		Name:   "commit-stuck-threshold",
+		Desc:   "Report the commit loop as stuck when it hasn't processed a price, or a broadcast hasn't returned, within this duration (0 = disabled)",
+		EnvVar: "ORACLE_COMMIT_STUCK_THRESHOLD",
+		Value:  "5m",
+	})
+
+	*strictFeeds = cmd.Bool(cli.BoolOpt{
+		Name:   "strict-feeds",
+		Desc:   "Exit with an error if no price feeds are loaded, or the relayer is authorized in none of the loaded PriceFeed feeds, instead of running idle",
+		EnvVar: "ORACLE_STRICT_FEEDS",
+		Value:  false,
+	})
+
+	*selfTest = cmd.Bool(cli.BoolOpt{
+		Name:   "self-test",
+		Desc:   "Before starting, check the relayer key can sign and is authorized in at least one loaded price feed, exiting with an error otherwise",
+		EnvVar: "ORACLE_SELF_TEST",
+		Value:  false,
+	})
+
+	*heightLagInterval = cmd.String(cli.StringOpt{
+		Name:   "height-lag-interval",
+		Desc:   "How often to report the lag between the latest chain height and the height of the latest successful submission (0 = disabled)",
+		EnvVar: "ORACLE_HEIGHT_LAG_INTERVAL",
+		Value:  "0",
+	})
+}
+
+// initStateFileOption sets the option of the file persisting the last submitted prices.
+func initStateFileOption(
+	cmd *cli.Cmd,
+	stateFile **string,
+) {
+	*stateFile = cmd.String(cli.StringOpt{
+		Name:   "state-file",
+		Desc:   "JSON file persisting the last submitted price per ticker across restarts (empty = disabled)",
+		EnvVar: "ORACLE_STATE_FILE",
+		Value:  "",
+	})
+}
+
+// initAlertOptions sets options for webhook alerts on repeated failures.
+func initAlertOptions(
+	cmd *cli.Cmd,
+	alertWebhookURL **string,
+	alertFailureThreshold **int,
+	alertCooldown **string,
+) {
+	*alertWebhookURL = cmd.String(cli.StringOpt{
+		Name:   "alert-webhook-url",
+		Desc:   "Webhook URL (Slack, Discord or generic JSON) to POST alerts on repeated feed or broadcast failures (empty = disabled)",
+		EnvVar: "ORACLE_ALERT_WEBHOOK_URL",
+		Value:  "",
+	})
+
+	*alertFailureThreshold = cmd.Int(cli.IntOpt{
+		Name:   "alert-failure-threshold",
+		Desc:   "Number of consecutive failures of a feed or broadcast that raises an alert",
+		EnvVar: "ORACLE_ALERT_FAILURE_THRESHOLD",
+		Value:  5,
+	})
+
+	*alertCooldown = cmd.String(cli.StringOpt{
+		Name:   "alert-cooldown",
+		Desc:   "Minimum time between alerts of the same feed or broadcast",
+		EnvVar: "ORACLE_ALERT_COOLDOWN",
+		Value:  "15m",
+	})
+}
+
+// initFeeBumpOptions sets options for rebroadcasts of Txs rejected for insufficient fees.
+func initFeeBumpOptions(
+	cmd *cli.Cmd,
+	feeBumpFactor **string,
+	feeBumpMaxRetries **int,
+) {
+	*feeBumpFactor = cmd.String(cli.StringOpt{
+		Name:   "fee-bump-factor",
+		Desc:   "Factor raising the gas prices on every retry of a Tx rejected for insufficient fees, e.g. 1.5 = +50% (1 = disabled)",
+		EnvVar: "ORACLE_FEE_BUMP_FACTOR",
+		Value:  "1.5",
+	})
+
+	*feeBumpMaxRetries = cmd.Int(cli.IntOpt{
+		Name:   "fee-bump-max-retries",
+		Desc:   "Max number of retries with bumped gas prices of a Tx rejected for insufficient fees (0 = disabled)",
+		EnvVar: "ORACLE_FEE_BUMP_MAX_RETRIES",
+		Value:  3,
+	})
+}
+
+// initPriceTransformOptions sets options of the transform applied to every pulled price before it's batched.
+func initPriceTransformOptions(
+	cmd *cli.Cmd,
+	priceTransform **string,
+	priceTransformFraction **string,
+) {
+	*priceTransform = cmd.String(cli.StringOpt{
+		Name:   "price-transform",
+		Desc:   "Transform applied to every pulled price before it's submitted: identity, haircut or clamp-to-band",
+		EnvVar: "ORACLE_PRICE_TRANSFORM",
+		Value:  "identity",
+	})
+
+	*priceTransformFraction = cmd.String(cli.StringOpt{
+		Name:   "price-transform-fraction",
+		Desc:   "Fraction of the haircut, or half-width of the band around the last submitted price to clamp to, e.g. 0.001 = 0.1%",
+		EnvVar: "ORACLE_PRICE_TRANSFORM_FRACTION",
+		Value:  "0",
+	})
+}
+
+// initMirrorNetworkOptions sets options for a mirror network, with its own node and key,
+// every price batch is submitted to along with the main network.
+func initMirrorNetworkOptions(
+	cmd *cli.Cmd,
+	mirrorName **string,
+	mirrorNetworkNode **string,
+	mirrorGRPC **string,
+	mirrorStreamGRPC **string,
+	mirrorTendermintRPC **string,
+	mirrorGasPrices **string,
+	mirrorKeyringDir **string,
+	mirrorKeyringAppName **string,
+	mirrorKeyringBackend **string,
+	mirrorKeyFrom **string,
+	mirrorKeyPassphrase **string,
+	mirrorPrivKey **string,
+) {
+	*mirrorNetworkNode = cmd.String(cli.StringOpt{
+		Name:   "mirror-network-node",
+		Desc:   "Specify network and node of a mirror network every price batch is submitted to as well (e.g testnet,lb). Disabled if not set.",
+		EnvVar: "ORACLE_MIRROR_NETWORK_NODE",
+	})
+
+	*mirrorName = cmd.String(cli.StringOpt{
+		Name:   "mirror-name",
+		Desc:   "Name of the mirror network in logs and metrics, the network of --mirror-network-node if not set",
+		EnvVar: "ORACLE_MIRROR_NAME",
+	})
+
+	*mirrorGRPC = cmd.String(cli.StringOpt{
+		Name:   "mirror-cosmos-grpc",
+		Desc:   "Cosmos GRPC querying endpoint of the mirror network, the one of the network node if not set",
+		EnvVar: "ORACLE_MIRROR_COSMOS_GRPC",
+	})
+
+	*mirrorStreamGRPC = cmd.String(cli.StringOpt{
+		Name:   "mirror-cosmos-stream-grpc",
+		Desc:   "Cosmos Stream GRPC querying endpoint of the mirror network, the one of the network node if not set",
+		EnvVar: "ORACLE_MIRROR_COSMOS_STREAM_GRPC",
+	})
+
+	*mirrorTendermintRPC = cmd.String(cli.StringOpt{
+		Name:   "mirror-tendermint-rpc",
+		Desc:   "Tendermint RPC endpoint of the mirror network, the one of the network node if not set",
+		EnvVar: "ORACLE_MIRROR_TENDERMINT_RPC",
+	})
+
+	*mirrorGasPrices = cmd.String(cli.StringOpt{
+		Name:   "mirror-cosmos-gas-prices",
+		Desc:   "Specify mirror network transaction fees as sdk.Coins gas prices",
+		EnvVar: "ORACLE_MIRROR_COSMOS_GAS_PRICES",
+	})
+
+	*mirrorKeyringBackend = cmd.String(cli.StringOpt{
+		Name:   "mirror-cosmos-keyring",
+		Desc:   "Specify Cosmos keyring backend of the mirror network key (os|file|kwallet|pass|test)",
+		EnvVar: "ORACLE_MIRROR_COSMOS_KEYRING",
+		Value:  "file",
+	})
+
+	*mirrorKeyringDir = cmd.String(cli.StringOpt{
+		Name:   "mirror-cosmos-keyring-dir",
+		Desc:   "Specify Cosmos keyring dir of the mirror network key, if using file keyring.",
+		EnvVar: "ORACLE_MIRROR_COSMOS_KEYRING_DIR",
+	})
+
+	*mirrorKeyringAppName = cmd.String(cli.StringOpt{
+		Name:   "mirror-cosmos-keyring-app",
+		Desc:   "Specify Cosmos keyring app name of the mirror network key.",
+		EnvVar: "ORACLE_MIRROR_COSMOS_KEYRING_APP",
+		Value:  "injectived",
+	})
+
+	*mirrorKeyFrom = cmd.String(cli.StringOpt{
+		Name:   "mirror-cosmos-from",
+		Desc:   "Specify the key name or address of the mirror network relayer. If specified, must exist in keyring or match the privkey.",
+		EnvVar: "ORACLE_MIRROR_COSMOS_FROM",
+	})
+
+	*mirrorKeyPassphrase = cmd.String(cli.StringOpt{
+		Name:   "mirror-cosmos-from-passphrase",
+		Desc:   "Specify keyring passphrase of the mirror network key, otherwise Stdin will be used.",
+		EnvVar: "ORACLE_MIRROR_COSMOS_FROM_PASSPHRASE",
+	})
+
+	*mirrorPrivKey = cmd.String(cli.StringOpt{
+		Name:   "mirror-cosmos-pk",
+		Desc:   "Provide a raw Cosmos account private key of the mirror network relayer in hex. USE FOR TESTING ONLY!",
+		EnvVar: "ORACLE_MIRROR_COSMOS_PK",
+	})
 }
 
 // initStatsdOptions sets options for StatsD metrics.
@@ -215,7 +565,13 @@ func initStorkOracleWebSocket(
 	cmd *cli.Cmd,
 	websocketUrl **string,
 	websocketHeader **string,
+	websocketExtraHeaders **[]string,
 	websocketSubscribeMessage **string,
+	websocketReadTimeout **string,
+	websocketCompression **bool,
+	websocketMaxInvalidMessages **int,
+	websocketDialTimeout **string,
+	websocketReadLimit **int,
 ) {
 	*websocketUrl = cmd.String(cli.StringOpt{
 		Name:   "websocket-url",
@@ -227,9 +583,45 @@ func initStorkOracleWebSocket(
 		Desc:   "Stork websocket header",
 		EnvVar: "STORK_WEBSOCKET_HEADER",
 	})
+	*websocketExtraHeaders = cmd.Strings(cli.StringsOpt{
+		Name:   "websocket-extra-header",
+		Desc:   "Additional Stork websocket header in \"Key: Value\" format (e.g. an API key), can be repeated",
+		EnvVar: "STORK_WEBSOCKET_EXTRA_HEADERS",
+		Value:  []string{},
+	})
 	*websocketSubscribeMessage = cmd.String(cli.StringOpt{
 		Name:   "websocket-subscribe-message",
 		Desc:   "Stork websocket subscribe message",
 		EnvVar: "STORK_WEBSOCKET_SUBSCRIBE_MESSAGE",
 	})
+	*websocketReadTimeout = cmd.String(cli.StringOpt{
+		Name:   "websocket-read-timeout",
+		Desc:   "Stork websocket read timeout, after which the connection is considered dead and re-established (0 = disabled)",
+		EnvVar: "STORK_WEBSOCKET_READ_TIMEOUT",
+		Value:  "1m",
+	})
+	*websocketCompression = cmd.Bool(cli.BoolOpt{
+		Name:   "websocket-compression",
+		Desc:   "Negotiate permessage-deflate compression with the Stork websocket server",
+		EnvVar: "STORK_WEBSOCKET_COMPRESSION",
+		Value:  true,
+	})
+	*websocketMaxInvalidMessages = cmd.Int(cli.IntOpt{
+		Name:   "websocket-max-invalid-messages",
+		Desc:   "Number of consecutive invalid Stork websocket messages tolerated before reconnecting (0 = reconnect on the first one)",
+		EnvVar: "STORK_WEBSOCKET_MAX_INVALID_MESSAGES",
+		Value:  3,
+	})
+	*websocketDialTimeout = cmd.String(cli.StringOpt{
+		Name:   "websocket-dial-timeout",
+		Desc:   "Timeout of a single Stork websocket connection attempt, including the TLS and websocket handshakes",
+		EnvVar: "STORK_WEBSOCKET_DIAL_TIMEOUT",
+		Value:  "10s",
+	})
+	*websocketReadLimit = cmd.Int(cli.IntOpt{
+		Name:   "websocket-read-limit",
+		Desc:   "Maximum size in bytes of a single Stork websocket message, larger messages drop the connection, which is re-established",
+		EnvVar: "STORK_WEBSOCKET_READ_LIMIT",
+		Value:  int(pipeline.DefaultWebSocketReadLimit),
+	})
 }
@@ -3,9 +3,9 @@ package main
 import (
 	"context"
 	"fmt"
-	"io/fs"
+	"net/http"
 	"os"
-	"path/filepath"
+	"strconv"
 	"strings"
 	"time"
 
@@ -18,6 +18,7 @@ import (
 	cli "github.com/jawher/mow.cli"
 	"github.com/pkg/errors"
 	"github.com/xlab/closer"
+	"google.golang.org/grpc"
 
 	"github.com/InjectiveLabs/injective-price-oracle/oracle"
 	"github.com/InjectiveLabs/injective-price-oracle/pipeline"
@@ -47,8 +48,58 @@ func oracleCmd(cmd *cli.Cmd) {
 		cosmosUseLedger     *bool
 
 		// External Feeds params
-		feedsDir       *string
-		binanceBaseURL *string
+		feedsDir             *string
+		feedsInline          *string
+		binanceBaseURL       *string
+		binanceRegion        *string
+		defaultPullIntervals *[]string
+		allowedTaskTypes     *[]string
+		httpUserAgent        *string
+		httpDefaultHeaders   *[]string
+		httpDenyPrivateIPs   *bool
+		httpAllow            *[]string
+		httpDeny             *[]string
+
+		// Service params
+		maxConcurrentPulls      *int
+		maxConcurrentBroadcasts *int
+		pullJitter              *string
+		startDelay              *string
+		startDelayWindow        *string
+		onlyFeedTickers         *[]string
+		stateFile               *string
+		auditLog                *string
+		commitStuckThreshold    *string
+		strictFeeds             *bool
+		selfTest                *bool
+		heightLagInterval       *string
+
+		// Alerts
+		alertWebhookURL       *string
+		alertFailureThreshold *int
+		alertCooldown         *string
+
+		// Fee bumps
+		feeBumpFactor     *string
+		feeBumpMaxRetries *int
+
+		// Price transform
+		priceTransform         *string
+		priceTransformFraction *string
+
+		// Mirror network
+		mirrorName           *string
+		mirrorNetworkNode    *string
+		mirrorGRPC           *string
+		mirrorStreamGRPC     *string
+		mirrorTendermintRPC  *string
+		mirrorGasPrices      *string
+		mirrorKeyringDir     *string
+		mirrorKeyringAppName *string
+		mirrorKeyringBackend *string
+		mirrorKeyFrom        *string
+		mirrorKeyPassphrase  *string
+		mirrorPrivKey        *string
 
 		// Metrics
 		statsdPrefix   *string
@@ -59,9 +110,15 @@ func oracleCmd(cmd *cli.Cmd) {
 		statsdDisabled *string
 
 		// Stork Oracle websocket params
-		websocketUrl              *string
-		websocketHeader           *string
-		websocketSubscribeMessage *string
+		websocketUrl                *string
+		websocketHeader             *string
+		websocketExtraHeaders       *[]string
+		websocketSubscribeMessage   *string
+		websocketReadTimeout        *string
+		websocketCompression        *bool
+		websocketMaxInvalidMessages *int
+		websocketDialTimeout        *string
+		websocketReadLimit          *int
 	)
 
 	initCosmosOptions(
@@ -88,7 +145,75 @@ func oracleCmd(cmd *cli.Cmd) {
 	initExternalFeedsOptions(
 		cmd,
 		&binanceBaseURL,
+		&binanceRegion,
 		&feedsDir,
+		&feedsInline,
+		&defaultPullIntervals,
+		&allowedTaskTypes,
+	)
+
+	initHTTPTaskOptions(
+		cmd,
+		&httpUserAgent,
+		&httpDefaultHeaders,
+		&httpDenyPrivateIPs,
+		&httpAllow,
+		&httpDeny,
+	)
+
+	initServiceOptions(
+		cmd,
+		&maxConcurrentPulls,
+		&maxConcurrentBroadcasts,
+		&pullJitter,
+		&startDelay,
+		&startDelayWindow,
+		&onlyFeedTickers,
+		&auditLog,
+		&commitStuckThreshold,
+		&strictFeeds,
+		&selfTest,
+		&heightLagInterval,
+	)
+
+	initStateFileOption(
+		cmd,
+		&stateFile,
+	)
+
+	initAlertOptions(
+		cmd,
+		&alertWebhookURL,
+		&alertFailureThreshold,
+		&alertCooldown,
+	)
+
+	initFeeBumpOptions(
+		cmd,
+		&feeBumpFactor,
+		&feeBumpMaxRetries,
+	)
+
+	initPriceTransformOptions(
+		cmd,
+		&priceTransform,
+		&priceTransformFraction,
+	)
+
+	initMirrorNetworkOptions(
+		cmd,
+		&mirrorName,
+		&mirrorNetworkNode,
+		&mirrorGRPC,
+		&mirrorStreamGRPC,
+		&mirrorTendermintRPC,
+		&mirrorGasPrices,
+		&mirrorKeyringDir,
+		&mirrorKeyringAppName,
+		&mirrorKeyringBackend,
+		&mirrorKeyFrom,
+		&mirrorKeyPassphrase,
+		&mirrorPrivKey,
 	)
 
 	initStatsdOptions(
@@ -105,13 +230,24 @@ func oracleCmd(cmd *cli.Cmd) {
 		cmd,
 		&websocketUrl,
 		&websocketHeader,
+		&websocketExtraHeaders,
 		&websocketSubscribeMessage,
+		&websocketReadTimeout,
+		&websocketCompression,
+		&websocketMaxInvalidMessages,
+		&websocketDialTimeout,
+		&websocketReadLimit,
 	)
 
 	cmd.Action = func() {
-		ctx := context.Background()
+		setAllowedTaskTypes(*allowedTaskTypes)
+		setHTTPDefaultHeaders(*httpUserAgent, *httpDefaultHeaders)
+		setHTTPAccessPolicy(*httpDenyPrivateIPs, *httpAllow, *httpDeny)
+
+		ctx, cancelFn := context.WithCancel(context.Background())
 		// ensure a clean exit
 		defer closer.Close()
+		closer.Bind(cancelFn)
 
 		startMetricsGathering(
 			statsdPrefix,
@@ -122,128 +258,82 @@ func oracleCmd(cmd *cli.Cmd) {
 			statsdDisabled,
 		)
 
-		if *cosmosUseLedger {
-			log.Fatalln("cannot really use Ledger for oracle service loop, since signatures msut be realtime")
-		}
-
-		networkNodeSplit := strings.Split(*networkNode, ",")
-		networkStr, node := networkNodeSplit[0], networkNodeSplit[1]
-		network := common.LoadNetwork(networkStr, node)
-
-		senderAddress, cosmosKeyring, err := chainclient.InitCosmosKeyring(
-			*cosmosKeyringDir,
-			*cosmosKeyringAppName,
-			*cosmosKeyringBackend,
-			*cosmosKeyFrom,
-			*cosmosKeyPassphrase,
-			*cosmosPrivKey,
-			*cosmosUseLedger,
-		)
-		if err != nil {
-			log.WithError(err).Fatalln("failed to init Cosmos keyring")
-		}
+		cosmosClient, daemonConn := initChainClient(ctx, &chainClientConfig{
+			NetworkNode:    *networkNode,
+			TendermintRPC:  *tendermintRPC,
+			GRPC:           *cosmosGRPC,
+			StreamGRPC:     *cosmosStreamGRPC,
+			GasPrices:      *cosmosGasPrices,
+			KeyringDir:     *cosmosKeyringDir,
+			KeyringAppName: *cosmosKeyringAppName,
+			KeyringBackend: *cosmosKeyringBackend,
+			KeyFrom:        *cosmosKeyFrom,
+			KeyPassphrase:  *cosmosKeyPassphrase,
+			PrivKey:        *cosmosPrivKey,
+			UseLedger:      *cosmosUseLedger,
+		})
 
-		log.Infoln("using Injective Sender", senderAddress.String())
-		clientCtx, err := chainclient.NewClientContext(network.ChainId, senderAddress.String(), cosmosKeyring)
-		if err != nil {
-			log.WithError(err).Fatalln("failed to initialize cosmos client context")
-		}
+		var mirrorNetworks []oracle.MirrorNetwork
+		if len(*mirrorNetworkNode) > 0 {
+			name := *mirrorName
+			if len(name) == 0 {
+				name = strings.Split(*mirrorNetworkNode, ",")[0]
+			}
 
-		if tendermintRPC != nil && *tendermintRPC != "" {
-			network.TmEndpoint = *tendermintRPC
-		}
+			mirrorClient, _ := initChainClient(ctx, &chainClientConfig{
+				NetworkNode:    *mirrorNetworkNode,
+				TendermintRPC:  *mirrorTendermintRPC,
+				GRPC:           *mirrorGRPC,
+				StreamGRPC:     *mirrorStreamGRPC,
+				GasPrices:      *mirrorGasPrices,
+				KeyringDir:     *mirrorKeyringDir,
+				KeyringAppName: *mirrorKeyringAppName,
+				KeyringBackend: *mirrorKeyringBackend,
+				KeyFrom:        *mirrorKeyFrom,
+				KeyPassphrase:  *mirrorKeyPassphrase,
+				PrivKey:        *mirrorPrivKey,
+			})
 
-		clientCtx = clientCtx.WithNodeURI(network.TmEndpoint)
-		tmRPC, err := rpchttp.New(network.TmEndpoint, "/websocket")
-		if err != nil {
-			log.WithError(err).Fatalln("failed to connect to tendermint RPC")
+			mirrorNetworks = append(mirrorNetworks, oracle.MirrorNetwork{
+				Name:   name,
+				Client: mirrorClient,
+			})
 		}
 
-		if cosmosGRPC != nil && *cosmosGRPC != "" {
-			network.ChainGrpcEndpoint = *cosmosGRPC // env var
-		}
-		if cosmosStreamGRPC != nil && *cosmosStreamGRPC != "" {
-			network.ChainStreamGrpcEndpoint = *cosmosStreamGRPC // env var
-		}
+		feedConfigs, storkTickers := loadFeedConfigs(*feedsDir, *feedsInline, parseDefaultPullIntervals(*defaultPullIntervals), defaultRegions(*binanceRegion), *onlyFeedTickers)
 
-		clientCtx = clientCtx.WithClient(tmRPC)
-		cosmosClient, err := chainclient.NewChainClient(clientCtx, network, common.OptionGasPrices(*cosmosGasPrices))
-		if err != nil {
-			log.WithError(err).WithFields(log.Fields{
-				"endpoint": network.ChainGrpcEndpoint,
-			}).Fatalln("failed to connect to daemon, is injectived running?")
+		jitterFraction, err := strconv.ParseFloat(*pullJitter, 64)
+		if err != nil || jitterFraction < 0 || jitterFraction > 1 {
+			log.WithField("pull_jitter", *pullJitter).Fatalln("pull jitter must be a number between 0 and 1")
 		}
-		closer.Bind(func() {
-			cosmosClient.Close()
-		})
 
-		log.Infoln("waiting for GRPC services")
-		time.Sleep(1 * time.Second)
-
-		daemonWaitCtx, cancelWait := context.WithTimeout(ctx, 10*time.Second)
-		defer cancelWait()
-
-		daemonConn := cosmosClient.QueryClient()
-		if err := waitForService(daemonWaitCtx, daemonConn); err != nil {
-			panic(fmt.Errorf("failed to wait for cosmos client connection: %w", err))
+		feeBumpMultiplier, err := strconv.ParseFloat(*feeBumpFactor, 64)
+		if err != nil || feeBumpMultiplier < 1 {
+			log.WithField("fee_bump_factor", *feeBumpFactor).Fatalln("fee bump factor must be a number not less than 1")
 		}
 
-		var storkEnabled bool
-		storkMap := make(map[string]struct{})
-
-		feedConfigs := make(map[string]*oracle.FeedConfig)
-		if len(*feedsDir) > 0 {
-			err := filepath.WalkDir(*feedsDir, func(path string, d fs.DirEntry, err error) error {
-				if err != nil {
-					return err
-				} else if d.IsDir() {
-					return nil
-				} else if filepath.Ext(path) != ".toml" {
-					return nil
-				}
-
-				cfgBody, err := os.ReadFile(path)
-				if err != nil {
-					err = errors.Wrapf(err, "failed to read dynamic feed config")
-					return err
-				}
-
-				feedCfg, err := oracle.ParseDynamicFeedConfig(cfgBody)
-				if err != nil {
-					log.WithError(err).WithFields(log.Fields{
-						"filename": d.Name(),
-					}).Errorln("failed to parse dynamic feed config")
-					return nil
-				}
-
-				if feedCfg.ProviderName == oracle.FeedProviderStork.String() {
-					storkEnabled = true
-					storkMap[feedCfg.Ticker] = struct{}{}
-				}
-
-				feedConfigs[filepath.Base(path)] = feedCfg
-
-				return nil
-			})
-
-			if err != nil {
-				err = errors.Wrapf(err, "feeds dir is specified, but failed to read from it: %s", *feedsDir)
-				log.WithError(err).Fatalln("failed to load dynamic feeds")
-				return
-			}
-
-			log.Infof("found %d dynamic feed configs", len(feedConfigs))
+		transformFraction, err := strconv.ParseFloat(*priceTransformFraction, 64)
+		if err != nil {
+			log.WithField("price_transform_fraction", *priceTransformFraction).Fatalln("price transform fraction must be a number")
 		}
 
 		var storkFetcher oracle.StorkFetcher
 
-		if storkEnabled {
-			var storkTickers []string
-			for ticker := range storkMap {
-				storkTickers = append(storkTickers, ticker)
-			}
+		storkCfg := &oracle.StorkConfig{
+			WebsocketUrl:         *websocketUrl,
+			WebsocketHeader:      *websocketHeader,
+			WebsocketExtraHeader: parseWebsocketHeaders(*websocketExtraHeaders),
+			Message:              *websocketSubscribeMessage,
+			TickerMessages:       storkTickerMessages(feedConfigs),
+			ReadTimeout:          duration(*websocketReadTimeout, 0),
+			Compression:          *websocketCompression,
+			MaxInvalidMessages:   *websocketMaxInvalidMessages,
+			DialTimeout:          duration(*websocketDialTimeout, 0),
+			ReadLimit:            int64(*websocketReadLimit),
+		}
 
-			storkFetcher = oracle.NewStorkFetcher(*websocketSubscribeMessage, storkTickers)
+		if len(storkTickers) > 0 {
+			storkFetcher = oracle.NewStorkFetcher(storkCfg, storkTickers)
 		}
 
 		svc, err := oracle.NewService(
@@ -253,6 +343,34 @@ func oracleCmd(cmd *cli.Cmd) {
 			oracletypes.NewQueryClient(daemonConn),
 			feedConfigs,
 			storkFetcher,
+			oracle.ServiceConfig{
+				MaxConcurrentPulls:      *maxConcurrentPulls,
+				MaxConcurrentBroadcasts: *maxConcurrentBroadcasts,
+				PullJitter:              jitterFraction,
+				StartDelay:              duration(*startDelay, 5*time.Second),
+				StartDelayWindow:        duration(*startDelayWindow, 0),
+				StateFile:               *stateFile,
+				AuditLog:                *auditLog,
+				CommitStuckThreshold:    duration(*commitStuckThreshold, 5*time.Minute),
+				StrictFeeds:             *strictFeeds,
+				SelfTest:                *selfTest,
+				HeightLagInterval:       duration(*heightLagInterval, 0),
+				Alert: oracle.AlertConfig{
+					WebhookURL:       *alertWebhookURL,
+					FailureThreshold: *alertFailureThreshold,
+					Cooldown:         duration(*alertCooldown, 15*time.Minute),
+				},
+				FeeBump: oracle.FeeBumpConfig{
+					GasPrices:  *cosmosGasPrices,
+					Factor:     feeBumpMultiplier,
+					MaxRetries: *feeBumpMaxRetries,
+				},
+				MirrorNetworks: mirrorNetworks,
+				PriceTransform: oracle.PriceTransformConfig{
+					Name:     oracle.PriceTransform(*priceTransform),
+					Fraction: transformFraction,
+				},
+			},
 		)
 		if err != nil {
 			log.Fatalln(err)
@@ -262,31 +380,9 @@ func oracleCmd(cmd *cli.Cmd) {
 			svc.Close()
 		})
 
-		go func() {
-			if storkFetcher == nil {
-				return // no stork feeds
-			}
-			connectIn := 0 * time.Second
-			for {
-				select {
-				case <-ctx.Done():
-					return
-				case <-time.After(connectIn):
-				}
-
-				connectIn = 5 * time.Second
-				conn, err := pipeline.ConnectWebSocket(ctx, *websocketUrl, *websocketHeader, oracle.MaxRetriesReConnectWebSocket)
-				if err != nil {
-					log.WithError(err).Errorln("failed to connect to WebSocket")
-					continue
-				}
-
-				err = storkFetcher.Start(ctx, conn)
-				if err != nil {
-					log.WithError(err).Errorln("stork fetcher failed")
-				}
-			}
-		}()
+		if storkFetcher != nil {
+			go runStorkFetcher(ctx, storkFetcher, storkCfg)
+		}
 
 		go func() {
 			if err := svc.Start(); err != nil {
@@ -300,3 +396,262 @@ func oracleCmd(cmd *cli.Cmd) {
 		closer.Hold()
 	}
 }
+
+type chainClientConfig struct {
+	NetworkNode   string
+	TendermintRPC string
+	GRPC          string
+	StreamGRPC    string
+	GasPrices     string
+
+	KeyringDir     string
+	KeyringAppName string
+	KeyringBackend string
+	KeyFrom        string
+	KeyPassphrase  string
+	PrivKey        string
+	UseLedger      bool
+}
+
+// initChainClient inits Cosmos keyring and chain client, then waits for the daemon GRPC services.
+// Any failure is fatal, since no command can proceed without a chain connection.
+func initChainClient(ctx context.Context, cfg *chainClientConfig) (chainclient.ChainClient, *grpc.ClientConn) {
+	if cfg.UseLedger {
+		log.Fatalln("cannot really use Ledger for oracle service loop, since signatures msut be realtime")
+	}
+
+	networkNodeSplit := strings.Split(cfg.NetworkNode, ",")
+	networkStr, node := networkNodeSplit[0], networkNodeSplit[1]
+	network := common.LoadNetwork(networkStr, node)
+
+	senderAddress, cosmosKeyring, err := chainclient.InitCosmosKeyring(
+		cfg.KeyringDir,
+		cfg.KeyringAppName,
+		cfg.KeyringBackend,
+		cfg.KeyFrom,
+		cfg.KeyPassphrase,
+		cfg.PrivKey,
+		cfg.UseLedger,
+	)
+	if err != nil {
+		log.WithError(err).Fatalln("failed to init Cosmos keyring")
+	}
+
+	log.Infoln("using Injective Sender", senderAddress.String())
+	clientCtx, err := chainclient.NewClientContext(network.ChainId, senderAddress.String(), cosmosKeyring)
+	if err != nil {
+		log.WithError(err).Fatalln("failed to initialize cosmos client context")
+	}
+
+	if cfg.TendermintRPC != "" {
+		network.TmEndpoint = cfg.TendermintRPC
+	}
+
+	clientCtx = clientCtx.WithNodeURI(network.TmEndpoint)
+	tmRPC, err := rpchttp.New(network.TmEndpoint, "/websocket")
+	if err != nil {
+		log.WithError(err).Fatalln("failed to connect to tendermint RPC")
+	}
+
+	if cfg.GRPC != "" {
+		network.ChainGrpcEndpoint = cfg.GRPC // env var
+	}
+	if cfg.StreamGRPC != "" {
+		network.ChainStreamGrpcEndpoint = cfg.StreamGRPC // env var
+	}
+
+	clientCtx = clientCtx.WithClient(tmRPC)
+	cosmosClient, err := chainclient.NewChainClient(clientCtx, network, common.OptionGasPrices(cfg.GasPrices))
+	if err != nil {
+		log.WithError(err).WithFields(log.Fields{
+			"endpoint": network.ChainGrpcEndpoint,
+		}).Fatalln("failed to connect to daemon, is injectived running?")
+	}
+	closer.Bind(func() {
+		cosmosClient.Close()
+	})
+
+	log.Infoln("waiting for GRPC services")
+	time.Sleep(1 * time.Second)
+
+	daemonWaitCtx, cancelWait := context.WithTimeout(ctx, 10*time.Second)
+	defer cancelWait()
+
+	daemonConn := cosmosClient.QueryClient()
+	if err := waitForService(daemonWaitCtx, daemonConn); err != nil {
+		panic(fmt.Errorf("failed to wait for cosmos client connection: %w", err))
+	}
+
+	return cosmosClient, daemonConn
+}
+
+// loadFeedConfigs loads all valid feed configs from the feeds dir and inline feed configs, with default pull intervals
+// and regions of their providers set, keeping only tickers listed in onlyFeedTickers
+// if it's not empty. Returns configs keyed by their name in the feeds dir, along with tickers of the Stork feeds among them.
+func loadFeedConfigs(
+	feedsDir, feedsInline string,
+	defaultPullIntervals, defaultRegions map[string]string,
+	onlyFeedTickers []string,
+) (feedConfigs map[string]*oracle.FeedConfig, storkTickers []string) {
+	feedConfigs = make(map[string]*oracle.FeedConfig)
+	if len(feedsDir) == 0 && len(feedsInline) == 0 {
+		return feedConfigs, nil
+	}
+
+	onlyFeeds := make(map[string]struct{}, len(onlyFeedTickers))
+	for _, ticker := range onlyFeedTickers {
+		onlyFeeds[ticker] = struct{}{}
+	}
+
+	storkMap := make(map[string]struct{})
+
+	err := walkFeedConfigs(feedsDir, feedsInline, defaultPullIntervals, defaultRegions, func(name string, feedCfg *oracle.FeedConfig, err error) {
+		if err != nil {
+			log.WithError(err).WithFields(log.Fields{
+				"filename": name,
+			}).Errorln("failed to parse dynamic feed config")
+			return
+		}
+
+		if len(onlyFeeds) > 0 {
+			if _, ok := onlyFeeds[feedCfg.Ticker]; !ok {
+				log.WithFields(log.Fields{
+					"filename": name,
+					"ticker":   feedCfg.Ticker,
+				}).Infoln("skipping feed not listed in --only-feed")
+				return
+			}
+		}
+
+		if feedCfg.ProviderName == oracle.FeedProviderStork.String() {
+			storkMap[feedCfg.Ticker] = struct{}{}
+		}
+
+		feedConfigs[name] = feedCfg
+	})
+
+	if err != nil {
+		err = errors.Wrapf(err, "feeds dir is specified, but failed to read from it: %s", feedsDir)
+		log.WithError(err).Fatalln("failed to load dynamic feeds")
+	}
+
+	log.Infof("found %d dynamic feed configs", len(feedConfigs))
+
+	for ticker := range storkMap {
+		storkTickers = append(storkTickers, ticker)
+	}
+
+	return feedConfigs, storkTickers
+}
+
+// parseDefaultPullIntervals parses entries of --default-pull-interval, a malformed entry is fatal.
+func parseDefaultPullIntervals(entries []string) map[string]string {
+	intervals, err := oracle.ParseDefaultPullIntervals(entries)
+	if err != nil {
+		log.WithError(err).Fatalln("failed to parse default pull intervals")
+	}
+
+	return intervals
+}
+
+// defaultRegions returns the default regions of feed providers, keyed by the provider name of feed configs.
+func defaultRegions(binanceRegion string) map[string]string {
+	regions := make(map[string]string)
+	if len(binanceRegion) > 0 {
+		regions[oracle.FeedProviderBinance.String()] = binanceRegion
+	}
+
+	return regions
+}
+
+// setAllowedTaskTypes restricts task types of dynamic feeds to those of --allowed-task-type, an unknown type is fatal.
+func setAllowedTaskTypes(taskTypes []string) {
+	if err := pipeline.SetAllowedTaskTypes(taskTypes); err != nil {
+		log.WithError(err).Fatalln("failed to set allowed task types")
+	}
+}
+
+// setHTTPAccessPolicy restricts requests of the http task by the --http-* options, a malformed IP range is fatal.
+func setHTTPAccessPolicy(denyPrivateIPs bool, allow, deny []string) {
+	err := pipeline.SetHTTPAccessPolicy(pipeline.HTTPAccessPolicy{
+		DenyPrivateIPs: denyPrivateIPs,
+		Allow:          allow,
+		Deny:           deny,
+	})
+	if err != nil {
+		log.WithError(err).Fatalln("failed to set http access policy")
+	}
+}
+
+// setHTTPDefaultHeaders sets the User-Agent and --http-default-header headers of http task requests,
+// a malformed header is fatal.
+func setHTTPDefaultHeaders(userAgent string, pairs []string) {
+	header, err := pipeline.ParseHeaders(pairs)
+	if err != nil {
+		log.WithError(err).Fatalln("failed to parse http default headers")
+	}
+
+	if len(userAgent) > 0 && len(header.Get("User-Agent")) == 0 {
+		header.Set("User-Agent", userAgent)
+	}
+
+	pipeline.SetHTTPDefaultHeaders(header)
+}
+
+// parseWebsocketHeaders parses "Key: Value" pairs of --websocket-extra-header, a malformed pair is fatal.
+func parseWebsocketHeaders(pairs []string) http.Header {
+	header, err := pipeline.ParseHeaders(pairs)
+	if err != nil {
+		log.WithError(err).Fatalln("failed to parse websocket headers")
+	}
+
+	return header
+}
+
+// storkTickerMessages collects the subscribe message overrides of Stork feeds, keyed by ticker.
+func storkTickerMessages(feedConfigs map[string]*oracle.FeedConfig) map[string]string {
+	messages := make(map[string]string)
+	for _, feedCfg := range feedConfigs {
+		if feedCfg.ProviderName == oracle.FeedProviderStork.String() && len(feedCfg.SubscribeMessage) > 0 {
+			messages[feedCfg.Ticker] = feedCfg.SubscribeMessage
+		}
+	}
+
+	return messages
+}
+
+// runStorkFetcher keeps the Stork fetcher connected to the websocket, reconnecting on failures,
+// until ctx is done or the fetcher is closed.
+func runStorkFetcher(ctx context.Context, storkFetcher oracle.StorkFetcher, storkCfg *oracle.StorkConfig) {
+	connectIn := 0 * time.Second
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(connectIn):
+		}
+
+		connectIn = 5 * time.Second
+		conn, err := pipeline.ConnectWebSocket(
+			ctx,
+			storkCfg.WebsocketUrl,
+			storkCfg.WebsocketHeader,
+			storkCfg.WebsocketExtraHeader,
+			oracle.MaxRetriesReConnectWebSocket,
+			storkCfg.Compression,
+			storkCfg.DialTimeout,
+			storkCfg.ReadLimit,
+		)
+		if err != nil {
+			log.WithError(err).Errorln("failed to connect to WebSocket")
+			continue
+		}
+
+		err = storkFetcher.Start(ctx, conn)
+		if errors.Is(err, oracle.ErrFetcherClosed) {
+			return
+		} else if err != nil {
+			log.WithError(err).Errorln("stork fetcher failed")
+		}
+	}
+}
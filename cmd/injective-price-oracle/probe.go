@@ -2,42 +2,116 @@ package main
 
 import (
 	"context"
+	"fmt"
 	"io/ioutil"
+	"os"
+	"text/tabwriter"
+	"time"
 
 	log "github.com/InjectiveLabs/suplog"
 	cli "github.com/jawher/mow.cli"
+	"github.com/pkg/errors"
+	"github.com/shopspring/decimal"
 	"github.com/xlab/closer"
 
 	"github.com/InjectiveLabs/injective-price-oracle/oracle"
+	"github.com/InjectiveLabs/injective-price-oracle/pipeline"
 )
 
 // probeCmd action validates target TOML file spec and runs it once, printing the result.
 //
-// $ injective-price-oracle probe <FILE>
+// $ injective-price-oracle probe [--expected-value <PRICE> [--tolerance <FRACTION>]] <FILE>
 func probeCmd(cmd *cli.Cmd) {
-	tomlSource := cmd.StringArg("FILE", "", "Path to target TOML file with pipeline spec")
+	cmd.Spec = "[--expected-value [--tolerance]] [--allowed-task-type...] [--http-user-agent] [--http-default-header...] [--http-deny-private-ips] [--http-allow...] [--http-deny...] FILE"
+
+	expectedValue := cmd.String(cli.StringOpt{
+		Name: "expected-value",
+		Desc: "If set, the pulled price must be within tolerance of this value, otherwise probe exits with non-zero code",
+	})
+	tolerance := cmd.String(cli.StringOpt{
+		Name:  "tolerance",
+		Desc:  "Allowed relative deviation of the pulled price from the expected value (e.g. 0.05 = 5%)",
+		Value: "0",
+	})
+	allowedTaskTypes := cmd.Strings(cli.StringsOpt{
+		Name:   "allowed-task-type",
+		Desc:   "Only allow the pipeline to use the specified task types (e.g. jsonparse), can be repeated. All task types are allowed if not set.",
+		EnvVar: "ORACLE_ALLOWED_TASK_TYPES",
+		Value:  []string{},
+	})
+
+	var (
+		httpUserAgent      *string
+		httpDefaultHeaders *[]string
+		httpDenyPrivateIPs *bool
+		httpAllow          *[]string
+		httpDeny           *[]string
+	)
+	initHTTPTaskOptions(
+		cmd,
+		&httpUserAgent,
+		&httpDefaultHeaders,
+		&httpDenyPrivateIPs,
+		&httpAllow,
+		&httpDeny,
+	)
+
+	tomlSource := cmd.StringArg("FILE", "", "Path to target TOML, YAML or JSON file with pipeline spec")
 
 	cmd.Action = func() {
+		setAllowedTaskTypes(*allowedTaskTypes)
+		setHTTPDefaultHeaders(*httpUserAgent, *httpDefaultHeaders)
+		setHTTPAccessPolicy(*httpDenyPrivateIPs, *httpAllow, *httpDeny)
+
 		// ensure a clean exit
 		defer closer.Close()
 
+		var (
+			expected     decimal.Decimal
+			maxDeviation decimal.Decimal
+		)
+
+		if len(*expectedValue) > 0 {
+			var err error
+			if expected, err = decimal.NewFromString(*expectedValue); err != nil || !expected.IsPositive() {
+				log.WithField("expected_value", *expectedValue).Fatalln("expected value must be a positive number")
+			}
+
+			if maxDeviation, err = decimal.NewFromString(*tolerance); err != nil || maxDeviation.IsNegative() {
+				log.WithField("tolerance", *tolerance).Fatalln("tolerance must be a non-negative number")
+			}
+		}
+
 		cfgBody, err := ioutil.ReadFile(*tomlSource)
 		if err != nil {
 			log.WithField("file", *tomlSource).WithError(err).Fatalln("failed to read dynamic feed config")
 			return
 		}
 
-		feedCfg, err := oracle.ParseDynamicFeedConfig(cfgBody)
+		feedCfgs, err := oracle.ParseFeedConfigs(cfgBody, oracle.FeedConfigFormat(*tomlSource))
 		if err != nil {
 			log.WithError(err).WithFields(log.Fields{
 				"file": *tomlSource,
 			}).Errorln("failed to parse dynamic feed config")
 			return
+		} else if len(feedCfgs) > 1 {
+			log.WithFields(log.Fields{
+				"file":  *tomlSource,
+				"feeds": len(feedCfgs),
+			}).Fatalln("file declares many feeds, use batch-probe on its dir instead")
+			return
 		}
 
-		pricePuller, err := oracle.NewDynamicPriceFeed(feedCfg)
+		feedCfg := feedCfgs[0]
+
+		if feedCfg.ProviderName == oracle.FeedProviderStork.String() {
+			log.WithField("file", *tomlSource).Fatalln("Stork feeds need a websocket connection, use batch-probe on its dir instead")
+			return
+		}
+
+		pricePuller, err := oracle.NewPricePuller(feedCfg, nil)
 		if err != nil {
-			log.WithError(err).Fatalln("failed to init new dynamic price feed")
+			log.WithError(err).Fatalln("failed to init new price feed")
 			return
 		}
 
@@ -47,12 +121,327 @@ func probeCmd(cmd *cli.Cmd) {
 			"oracle_type":   pricePuller.OracleType().String(),
 		})
 
+		if multiPuller, ok := pricePuller.(oracle.MultiPricePuller); ok && len(feedCfg.Outputs) > 0 {
+			prices, err := multiPuller.PullPrices(context.Background())
+			if err != nil {
+				pullerLogger.WithError(err).Fatalln("failed to pull prices")
+			}
+
+			for _, priceData := range prices {
+				log.Infof("Answer of %s: %s", priceData.Ticker, priceData.Price)
+			}
+
+			if !expected.IsZero() {
+				log.Warningln("expected price is not checked for feeds with many outputs")
+			}
+			return
+		}
+
 		answer, err := pricePuller.PullPrice(context.Background())
 		if err != nil {
+			if !expected.IsZero() {
+				pullerLogger.WithError(err).Fatalln("failed to pull price")
+			}
+
 			pullerLogger.WithError(err).Errorln("failed to pull price")
 			return
+		} else if answer == nil {
+			if !expected.IsZero() {
+				pullerLogger.Fatalln("no price available, pull skipped")
+			}
+
+			pullerLogger.Warningln("no price available, pull skipped")
+			return
 		}
 
 		log.Infof("Answer: %s", answer.Price)
+
+		if expected.IsZero() {
+			return
+		}
+
+		deviation := answer.Price.Sub(expected).Abs().Div(expected)
+		if deviation.GreaterThan(maxDeviation) {
+			pullerLogger.WithFields(log.Fields{
+				"actual":    answer.Price.String(),
+				"expected":  expected.String(),
+				"deviation": deviation.String(),
+				"tolerance": maxDeviation.String(),
+			}).Fatalln("pulled price is out of the expected band")
+		}
+
+		log.Infof("Answer is within %s of expected %s", maxDeviation, expected)
+	}
+}
+
+// batchProbeCmd action validates all feeds in the feeds dir and pulls each of them once,
+// printing a pass/fail summary. Exits with non-zero code if any feed fails.
+//
+// $ injective-price-oracle batch-probe --feeds-dir <DIR>
+func batchProbeCmd(cmd *cli.Cmd) {
+	var (
+		// External Feeds params
+		feedsDir             *string
+		feedsInline          *string
+		binanceBaseURL       *string
+		binanceRegion        *string
+		defaultPullIntervals *[]string
+		allowedTaskTypes     *[]string
+		httpUserAgent        *string
+		httpDefaultHeaders   *[]string
+		httpDenyPrivateIPs   *bool
+		httpAllow            *[]string
+		httpDeny             *[]string
+
+		// Stork Oracle websocket params
+		websocketUrl                *string
+		websocketHeader             *string
+		websocketExtraHeaders       *[]string
+		websocketSubscribeMessage   *string
+		websocketReadTimeout        *string
+		websocketCompression        *bool
+		websocketMaxInvalidMessages *int
+		websocketDialTimeout        *string
+		websocketReadLimit          *int
+
+		timeout *string
+	)
+
+	initExternalFeedsOptions(
+		cmd,
+		&binanceBaseURL,
+		&binanceRegion,
+		&feedsDir,
+		&feedsInline,
+		&defaultPullIntervals,
+		&allowedTaskTypes,
+	)
+
+	initHTTPTaskOptions(
+		cmd,
+		&httpUserAgent,
+		&httpDefaultHeaders,
+		&httpDenyPrivateIPs,
+		&httpAllow,
+		&httpDeny,
+	)
+
+	initStorkOracleWebSocket(
+		cmd,
+		&websocketUrl,
+		&websocketHeader,
+		&websocketExtraHeaders,
+		&websocketSubscribeMessage,
+		&websocketReadTimeout,
+		&websocketCompression,
+		&websocketMaxInvalidMessages,
+		&websocketDialTimeout,
+		&websocketReadLimit,
+	)
+
+	timeout = cmd.String(cli.StringOpt{
+		Name:  "timeout",
+		Desc:  "Max time to wait for a price of a dynamic feed, or for all Stork feeds to receive an update",
+		Value: "30s",
+	})
+
+	cmd.Action = func() {
+		setAllowedTaskTypes(*allowedTaskTypes)
+		setHTTPDefaultHeaders(*httpUserAgent, *httpDefaultHeaders)
+		setHTTPAccessPolicy(*httpDenyPrivateIPs, *httpAllow, *httpDeny)
+
+		// ensure a clean exit
+		defer closer.Close()
+
+		if len(*feedsDir) == 0 && len(*feedsInline) == 0 {
+			log.Fatalln("feeds must be specified with --feeds-dir or --feeds-inline")
+		}
+
+		probeTimeout := duration(*timeout, 30*time.Second)
+
+		var (
+			results      []*probeResult
+			storkResults []*probeResult
+			storkCfgs    []*oracle.FeedConfig
+		)
+
+		err := walkFeedConfigs(*feedsDir, *feedsInline, parseDefaultPullIntervals(*defaultPullIntervals), defaultRegions(*binanceRegion), func(name string, feedCfg *oracle.FeedConfig, err error) {
+			res := &probeResult{
+				File: name,
+			}
+			results = append(results, res)
+
+			if err != nil {
+				res.Err = err
+				return
+			}
+
+			res.Ticker = feedCfg.Ticker
+			res.Provider = feedCfg.ProviderName
+
+			if feedCfg.ProviderName == oracle.FeedProviderStork.String() {
+				// stork feeds are probed at once over a shared connection
+				storkResults = append(storkResults, res)
+				storkCfgs = append(storkCfgs, feedCfg)
+				return
+			}
+
+			pricePuller, err := oracle.NewPricePuller(feedCfg, nil)
+			if err != nil {
+				res.Err = err
+				return
+			}
+
+			ctx, cancelFn := context.WithTimeout(context.Background(), probeTimeout)
+			defer cancelFn()
+
+			answer, err := pricePuller.PullPrice(ctx)
+			if err != nil {
+				res.Err = err
+				return
+			} else if answer == nil {
+				res.Err = errors.New("no price available, pull skipped")
+				return
+			}
+
+			res.Value = answer.Price.String()
+		})
+		if err != nil {
+			log.WithError(err).Fatalln("failed to read feeds dir")
+		}
+
+		if len(storkCfgs) > 0 {
+			probeStorkFeeds(storkCfgs, storkResults, &oracle.StorkConfig{
+				WebsocketUrl:         *websocketUrl,
+				WebsocketHeader:      *websocketHeader,
+				WebsocketExtraHeader: parseWebsocketHeaders(*websocketExtraHeaders),
+				Message:              *websocketSubscribeMessage,
+				ReadTimeout:          duration(*websocketReadTimeout, 0),
+				Compression:          *websocketCompression,
+				MaxInvalidMessages:   *websocketMaxInvalidMessages,
+				DialTimeout:          duration(*websocketDialTimeout, 0),
+				ReadLimit:            int64(*websocketReadLimit),
+			}, probeTimeout)
+		}
+
+		var failed int
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		_, _ = fmt.Fprintln(w, "FILE\tTICKER\tPROVIDER\tRESULT\tVALUE")
+		for _, res := range results {
+			if res.Err != nil {
+				failed++
+				_, _ = fmt.Fprintf(w, "%s\t%s\t%s\tFAIL\t%v\n", res.File, res.Ticker, res.Provider, res.Err)
+				continue
+			}
+
+			_, _ = fmt.Fprintf(w, "%s\t%s\t%s\tPASS\t%s\n", res.File, res.Ticker, res.Provider, res.Value)
+		}
+		_ = w.Flush()
+
+		fmt.Printf("\n%d passed, %d failed\n", len(results)-failed, failed)
+
+		if failed > 0 {
+			closer.Exit(1)
+		}
+	}
+}
+
+type probeResult struct {
+	File     string
+	Ticker   string
+	Provider string
+	Value    string
+	Err      error
+}
+
+// probeStorkFeeds opens a short-lived Stork websocket connection and waits until every
+// feed receives a price, or until timeout. Results are written into the matching probeResult.
+func probeStorkFeeds(
+	feedCfgs []*oracle.FeedConfig,
+	results []*probeResult,
+	storkCfg *oracle.StorkConfig,
+	timeout time.Duration,
+) {
+	tickers := make([]string, 0, len(feedCfgs))
+	storkCfg.TickerMessages = make(map[string]string)
+	for _, feedCfg := range feedCfgs {
+		tickers = append(tickers, feedCfg.Ticker)
+		if len(feedCfg.SubscribeMessage) > 0 {
+			storkCfg.TickerMessages[feedCfg.Ticker] = feedCfg.SubscribeMessage
+		}
+	}
+
+	storkFetcher := oracle.NewStorkFetcher(storkCfg, tickers)
+	defer storkFetcher.Close()
+
+	pricePullers := make([]oracle.PricePuller, len(feedCfgs))
+	for i, feedCfg := range feedCfgs {
+		pricePuller, err := oracle.NewStorkPriceFeed(storkFetcher, feedCfg)
+		if err != nil {
+			results[i].Err = err
+			continue
+		}
+
+		pricePullers[i] = pricePuller
+	}
+
+	ctx, cancelFn := context.WithTimeout(context.Background(), timeout)
+	defer cancelFn()
+
+	conn, err := pipeline.ConnectWebSocket(
+		ctx,
+		storkCfg.WebsocketUrl,
+		storkCfg.WebsocketHeader,
+		storkCfg.WebsocketExtraHeader,
+		oracle.MaxRetriesReConnectWebSocket,
+		storkCfg.Compression,
+		storkCfg.DialTimeout,
+		storkCfg.ReadLimit,
+	)
+	if err != nil {
+		for i := range results {
+			if results[i].Err == nil {
+				results[i].Err = fmt.Errorf("failed to connect to Stork websocket: %w", err)
+			}
+		}
+		return
+	}
+
+	fetcherErrC := make(chan error, 1)
+	go func() {
+		fetcherErrC <- storkFetcher.Start(ctx, conn)
+	}()
+
+	t := time.NewTicker(500 * time.Millisecond)
+	defer t.Stop()
+
+	for pending := len(pricePullers); pending > 0; {
+		select {
+		case <-ctx.Done():
+			err = fmt.Errorf("no price received within %s", timeout)
+		case err = <-fetcherErrC:
+			err = fmt.Errorf("stork fetcher failed: %w", err)
+		case <-t.C:
+		}
+
+		pending = 0
+		for i, pricePuller := range pricePullers {
+			if pricePuller == nil || results[i].Err != nil || len(results[i].Value) > 0 {
+				continue
+			}
+
+			if answer, _ := pricePuller.PullPrice(ctx); answer != nil {
+				results[i].Value = answer.AssetPair.SignedPrices[0].Price.String()
+				continue
+			}
+
+			if err != nil {
+				results[i].Err = err
+				continue
+			}
+
+			pending++
+		}
 	}
 }
@@ -0,0 +1,248 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"text/tabwriter"
+	"time"
+
+	exchangetypes "github.com/InjectiveLabs/sdk-go/chain/exchange/types"
+	oracletypes "github.com/InjectiveLabs/sdk-go/chain/oracle/types"
+	log "github.com/InjectiveLabs/suplog"
+	cli "github.com/jawher/mow.cli"
+	"github.com/xlab/closer"
+
+	"github.com/InjectiveLabs/injective-price-oracle/oracle"
+)
+
+// simulateCmd action pulls all feed prices once, composes the relay messages and simulates
+// them on chain without broadcasting, printing the gas used.
+//
+// $ injective-price-oracle simulate-broadcast --feeds-dir <DIR>
+func simulateCmd(cmd *cli.Cmd) {
+	var (
+		// Cosmos params
+		cosmosChainID    *string
+		cosmosGRPC       *string
+		cosmosStreamGRPC *string
+		tendermintRPC    *string
+		cosmosGasPrices  *string
+		networkNode      *string
+
+		// Cosmos Key Management
+		cosmosKeyringDir     *string
+		cosmosKeyringAppName *string
+		cosmosKeyringBackend *string
+
+		cosmosKeyFrom       *string
+		cosmosKeyPassphrase *string
+		cosmosPrivKey       *string
+		cosmosUseLedger     *bool
+
+		// External Feeds params
+		feedsDir             *string
+		feedsInline          *string
+		binanceBaseURL       *string
+		binanceRegion        *string
+		defaultPullIntervals *[]string
+		allowedTaskTypes     *[]string
+		httpUserAgent        *string
+		httpDefaultHeaders   *[]string
+		httpDenyPrivateIPs   *bool
+		httpAllow            *[]string
+		httpDeny             *[]string
+
+		// Stork Oracle websocket params
+		websocketUrl                *string
+		websocketHeader             *string
+		websocketExtraHeaders       *[]string
+		websocketSubscribeMessage   *string
+		websocketReadTimeout        *string
+		websocketCompression        *bool
+		websocketMaxInvalidMessages *int
+		websocketDialTimeout        *string
+		websocketReadLimit          *int
+
+		timeout *string
+	)
+
+	initCosmosOptions(
+		cmd,
+		&cosmosChainID,
+		&cosmosGRPC,
+		&cosmosStreamGRPC,
+		&tendermintRPC,
+		&cosmosGasPrices,
+		&networkNode,
+	)
+
+	initCosmosKeyOptions(
+		cmd,
+		&cosmosKeyringDir,
+		&cosmosKeyringAppName,
+		&cosmosKeyringBackend,
+		&cosmosKeyFrom,
+		&cosmosKeyPassphrase,
+		&cosmosPrivKey,
+		&cosmosUseLedger,
+	)
+
+	initExternalFeedsOptions(
+		cmd,
+		&binanceBaseURL,
+		&binanceRegion,
+		&feedsDir,
+		&feedsInline,
+		&defaultPullIntervals,
+		&allowedTaskTypes,
+	)
+
+	initHTTPTaskOptions(
+		cmd,
+		&httpUserAgent,
+		&httpDefaultHeaders,
+		&httpDenyPrivateIPs,
+		&httpAllow,
+		&httpDeny,
+	)
+
+	initStorkOracleWebSocket(
+		cmd,
+		&websocketUrl,
+		&websocketHeader,
+		&websocketExtraHeaders,
+		&websocketSubscribeMessage,
+		&websocketReadTimeout,
+		&websocketCompression,
+		&websocketMaxInvalidMessages,
+		&websocketDialTimeout,
+		&websocketReadLimit,
+	)
+
+	timeout = cmd.String(cli.StringOpt{
+		Name:  "timeout",
+		Desc:  "Max time to wait for all feeds to be pulled (e.g. Stork feeds waiting for a websocket update)",
+		Value: "30s",
+	})
+
+	cmd.Action = func() {
+		setAllowedTaskTypes(*allowedTaskTypes)
+		setHTTPDefaultHeaders(*httpUserAgent, *httpDefaultHeaders)
+		setHTTPAccessPolicy(*httpDenyPrivateIPs, *httpAllow, *httpDeny)
+
+		ctx, cancelFn := context.WithCancel(context.Background())
+		// ensure a clean exit
+		defer closer.Close()
+		closer.Bind(cancelFn)
+
+		if len(*feedsDir) == 0 && len(*feedsInline) == 0 {
+			log.Fatalln("feeds must be specified with --feeds-dir or --feeds-inline")
+		}
+
+		cosmosClient, daemonConn := initChainClient(ctx, &chainClientConfig{
+			NetworkNode:    *networkNode,
+			TendermintRPC:  *tendermintRPC,
+			GRPC:           *cosmosGRPC,
+			StreamGRPC:     *cosmosStreamGRPC,
+			GasPrices:      *cosmosGasPrices,
+			KeyringDir:     *cosmosKeyringDir,
+			KeyringAppName: *cosmosKeyringAppName,
+			KeyringBackend: *cosmosKeyringBackend,
+			KeyFrom:        *cosmosKeyFrom,
+			KeyPassphrase:  *cosmosKeyPassphrase,
+			PrivKey:        *cosmosPrivKey,
+			UseLedger:      *cosmosUseLedger,
+		})
+
+		feedConfigs, storkTickers := loadFeedConfigs(*feedsDir, *feedsInline, parseDefaultPullIntervals(*defaultPullIntervals), defaultRegions(*binanceRegion), nil)
+
+		var storkFetcher oracle.StorkFetcher
+
+		storkCfg := &oracle.StorkConfig{
+			WebsocketUrl:         *websocketUrl,
+			WebsocketHeader:      *websocketHeader,
+			WebsocketExtraHeader: parseWebsocketHeaders(*websocketExtraHeaders),
+			Message:              *websocketSubscribeMessage,
+			TickerMessages:       storkTickerMessages(feedConfigs),
+			ReadTimeout:          duration(*websocketReadTimeout, 0),
+			Compression:          *websocketCompression,
+			MaxInvalidMessages:   *websocketMaxInvalidMessages,
+			DialTimeout:          duration(*websocketDialTimeout, 0),
+			ReadLimit:            int64(*websocketReadLimit),
+		}
+
+		if len(storkTickers) > 0 {
+			storkFetcher = oracle.NewStorkFetcher(storkCfg, storkTickers)
+		}
+
+		svc, err := oracle.NewService(
+			ctx,
+			cosmosClient,
+			exchangetypes.NewQueryClient(daemonConn),
+			oracletypes.NewQueryClient(daemonConn),
+			feedConfigs,
+			storkFetcher,
+			oracle.ServiceConfig{},
+		)
+		if err != nil {
+			log.Fatalln(err)
+		}
+
+		closer.Bind(func() {
+			svc.Close()
+		})
+
+		if storkFetcher != nil {
+			go runStorkFetcher(ctx, storkFetcher, storkCfg)
+		}
+
+		simulateCtx, cancelSimulate := context.WithTimeout(ctx, duration(*timeout, 30*time.Second))
+		defer cancelSimulate()
+
+		result, err := svc.Simulate(simulateCtx)
+		if result != nil {
+			printSimulationResult(result)
+		}
+
+		if err != nil {
+			log.WithError(err).Errorln("simulation failed")
+			closer.Exit(1)
+		}
+
+		fmt.Printf("\nsimulation succeeded, %d msgs, gas used: %d\n", len(result.Msgs), result.GasUsed)
+
+		if len(result.PullErrors) > 0 {
+			closer.Exit(1)
+		}
+	}
+}
+
+func printSimulationResult(result *oracle.SimulationResult) {
+	tickers := make([]string, 0, len(result.Prices)+len(result.PullErrors))
+	for ticker := range result.Prices {
+		tickers = append(tickers, ticker)
+	}
+	for ticker := range result.PullErrors {
+		tickers = append(tickers, ticker)
+	}
+	sort.Strings(tickers)
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "TICKER\tORACLE TYPE\tRESULT")
+	for _, ticker := range tickers {
+		if err, ok := result.PullErrors[ticker]; ok {
+			_, _ = fmt.Fprintf(w, "%s\t-\tFAIL: %v\n", ticker, err)
+			continue
+		}
+
+		priceData := result.Prices[ticker]
+		_, _ = fmt.Fprintf(w, "%s\t%s\tOK\n", ticker, priceData.OracleType.String())
+	}
+	_ = w.Flush()
+
+	for _, msg := range result.Msgs {
+		fmt.Printf("\n%T: %v\n", msg, msg)
+	}
+}
@@ -0,0 +1,236 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	exchangetypes "github.com/InjectiveLabs/sdk-go/chain/exchange/types"
+	oracletypes "github.com/InjectiveLabs/sdk-go/chain/oracle/types"
+	log "github.com/InjectiveLabs/suplog"
+	cli "github.com/jawher/mow.cli"
+	"github.com/shopspring/decimal"
+	"github.com/xlab/closer"
+
+	"github.com/InjectiveLabs/injective-price-oracle/oracle"
+)
+
+// submitCmd action relays a single price of the ticker once and exits. The price is pulled
+// from the feed config of the ticker, unless it's given explicitly.
+//
+// $ injective-price-oracle submit --feeds-dir <DIR> --ticker BTC/USD [--price 12345]
+func submitCmd(cmd *cli.Cmd) {
+	var (
+		// Cosmos params
+		cosmosChainID    *string
+		cosmosGRPC       *string
+		cosmosStreamGRPC *string
+		tendermintRPC    *string
+		cosmosGasPrices  *string
+		networkNode      *string
+
+		// Cosmos Key Management
+		cosmosKeyringDir     *string
+		cosmosKeyringAppName *string
+		cosmosKeyringBackend *string
+
+		cosmosKeyFrom       *string
+		cosmosKeyPassphrase *string
+		cosmosPrivKey       *string
+		cosmosUseLedger     *bool
+
+		// External Feeds params
+		feedsDir             *string
+		feedsInline          *string
+		binanceBaseURL       *string
+		binanceRegion        *string
+		defaultPullIntervals *[]string
+		allowedTaskTypes     *[]string
+		httpUserAgent        *string
+		httpDefaultHeaders   *[]string
+		httpDenyPrivateIPs   *bool
+		httpAllow            *[]string
+		httpDeny             *[]string
+
+		// Stork Oracle websocket params
+		websocketUrl                *string
+		websocketHeader             *string
+		websocketExtraHeaders       *[]string
+		websocketSubscribeMessage   *string
+		websocketReadTimeout        *string
+		websocketCompression        *bool
+		websocketMaxInvalidMessages *int
+		websocketDialTimeout        *string
+		websocketReadLimit          *int
+
+		ticker  *string
+		price   *string
+		timeout *string
+	)
+
+	initCosmosOptions(
+		cmd,
+		&cosmosChainID,
+		&cosmosGRPC,
+		&cosmosStreamGRPC,
+		&tendermintRPC,
+		&cosmosGasPrices,
+		&networkNode,
+	)
+
+	initCosmosKeyOptions(
+		cmd,
+		&cosmosKeyringDir,
+		&cosmosKeyringAppName,
+		&cosmosKeyringBackend,
+		&cosmosKeyFrom,
+		&cosmosKeyPassphrase,
+		&cosmosPrivKey,
+		&cosmosUseLedger,
+	)
+
+	initExternalFeedsOptions(
+		cmd,
+		&binanceBaseURL,
+		&binanceRegion,
+		&feedsDir,
+		&feedsInline,
+		&defaultPullIntervals,
+		&allowedTaskTypes,
+	)
+
+	initHTTPTaskOptions(
+		cmd,
+		&httpUserAgent,
+		&httpDefaultHeaders,
+		&httpDenyPrivateIPs,
+		&httpAllow,
+		&httpDeny,
+	)
+
+	initStorkOracleWebSocket(
+		cmd,
+		&websocketUrl,
+		&websocketHeader,
+		&websocketExtraHeaders,
+		&websocketSubscribeMessage,
+		&websocketReadTimeout,
+		&websocketCompression,
+		&websocketMaxInvalidMessages,
+		&websocketDialTimeout,
+		&websocketReadLimit,
+	)
+
+	ticker = cmd.String(cli.StringOpt{
+		Name: "ticker",
+		Desc: "Ticker of the feed to submit price for (e.g. BTC/USD), the feed must be configured in feeds dir",
+	})
+
+	price = cmd.String(cli.StringOpt{
+		Name: "price",
+		Desc: "Price to submit as is, instead of pulling it from the feed. Not supported for Stork feeds",
+	})
+
+	timeout = cmd.String(cli.StringOpt{
+		Name:  "timeout",
+		Desc:  "Max time to wait for the price to be pulled and submitted",
+		Value: "1m",
+	})
+
+	cmd.Action = func() {
+		setAllowedTaskTypes(*allowedTaskTypes)
+		setHTTPDefaultHeaders(*httpUserAgent, *httpDefaultHeaders)
+		setHTTPAccessPolicy(*httpDenyPrivateIPs, *httpAllow, *httpDeny)
+
+		ctx, cancelFn := context.WithCancel(context.Background())
+		// ensure a clean exit
+		defer closer.Close()
+		closer.Bind(cancelFn)
+
+		if len(*feedsDir) == 0 && len(*feedsInline) == 0 {
+			log.Fatalln("feeds must be specified with --feeds-dir or --feeds-inline")
+		} else if len(*ticker) == 0 {
+			log.Fatalln("ticker must be specified with --ticker")
+		}
+
+		var manualPrice *decimal.Decimal
+		if len(*price) > 0 {
+			value, err := decimal.NewFromString(*price)
+			if err != nil || !value.IsPositive() {
+				log.WithField("price", *price).Fatalln("price must be a positive number")
+			}
+			manualPrice = &value
+		}
+
+		cosmosClient, daemonConn := initChainClient(ctx, &chainClientConfig{
+			NetworkNode:    *networkNode,
+			TendermintRPC:  *tendermintRPC,
+			GRPC:           *cosmosGRPC,
+			StreamGRPC:     *cosmosStreamGRPC,
+			GasPrices:      *cosmosGasPrices,
+			KeyringDir:     *cosmosKeyringDir,
+			KeyringAppName: *cosmosKeyringAppName,
+			KeyringBackend: *cosmosKeyringBackend,
+			KeyFrom:        *cosmosKeyFrom,
+			KeyPassphrase:  *cosmosKeyPassphrase,
+			PrivKey:        *cosmosPrivKey,
+			UseLedger:      *cosmosUseLedger,
+		})
+
+		feedConfigs, storkTickers := loadFeedConfigs(*feedsDir, *feedsInline, parseDefaultPullIntervals(*defaultPullIntervals), defaultRegions(*binanceRegion), []string{*ticker})
+
+		var storkFetcher oracle.StorkFetcher
+
+		storkCfg := &oracle.StorkConfig{
+			WebsocketUrl:         *websocketUrl,
+			WebsocketHeader:      *websocketHeader,
+			WebsocketExtraHeader: parseWebsocketHeaders(*websocketExtraHeaders),
+			Message:              *websocketSubscribeMessage,
+			TickerMessages:       storkTickerMessages(feedConfigs),
+			ReadTimeout:          duration(*websocketReadTimeout, 0),
+			Compression:          *websocketCompression,
+			MaxInvalidMessages:   *websocketMaxInvalidMessages,
+			DialTimeout:          duration(*websocketDialTimeout, 0),
+			ReadLimit:            int64(*websocketReadLimit),
+		}
+
+		if len(storkTickers) > 0 {
+			storkFetcher = oracle.NewStorkFetcher(storkCfg, storkTickers)
+		}
+
+		svc, err := oracle.NewService(
+			ctx,
+			cosmosClient,
+			exchangetypes.NewQueryClient(daemonConn),
+			oracletypes.NewQueryClient(daemonConn),
+			feedConfigs,
+			storkFetcher,
+			oracle.ServiceConfig{},
+		)
+		if err != nil {
+			log.Fatalln(err)
+		}
+
+		closer.Bind(func() {
+			svc.Close()
+		})
+
+		if storkFetcher != nil {
+			go runStorkFetcher(ctx, storkFetcher, storkCfg)
+		}
+
+		submitCtx, cancelSubmit := context.WithTimeout(ctx, duration(*timeout, time.Minute))
+		defer cancelSubmit()
+
+		txHash, err := svc.Submit(submitCtx, *ticker, manualPrice)
+		if err != nil {
+			log.WithError(err).WithFields(log.Fields{
+				"ticker": *ticker,
+				"hash":   txHash,
+			}).Errorln("failed to submit price")
+			closer.Exit(1)
+		}
+
+		fmt.Println(txHash)
+	}
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	log "github.com/InjectiveLabs/suplog"
+	cli "github.com/jawher/mow.cli"
+	"github.com/xlab/closer"
+
+	"github.com/InjectiveLabs/injective-price-oracle/oracle"
+)
+
+// validateCmd action checks feed config files without initializing feeds or pulling prices, so it's fast
+// and makes no network calls, e.g. for editor and CI integrations. Defaults of a file's dir are merged in.
+//
+// $ injective-price-oracle validate [--json] FILE...
+func validateCmd(cmd *cli.Cmd) {
+	cmd.Spec = "[--json] [--allowed-task-type...] FILE..."
+
+	jsonOutput := cmd.Bool(cli.BoolOpt{
+		Name:  "json",
+		Desc:  "Print validation errors of every file as JSON",
+		Value: false,
+	})
+	allowedTaskTypes := cmd.Strings(cli.StringsOpt{
+		Name:   "allowed-task-type",
+		Desc:   "Only allow pipelines to use the specified task types (e.g. jsonparse), can be repeated. All task types are allowed if not set.",
+		EnvVar: "ORACLE_ALLOWED_TASK_TYPES",
+		Value:  []string{},
+	})
+	files := cmd.StringsArg("FILE", nil, "Paths to feed config files in TOML, YAML or JSON format")
+
+	cmd.Action = func() {
+		defer closer.Close()
+
+		setAllowedTaskTypes(*allowedTaskTypes)
+
+		type fileResult struct {
+			File   string                   `json:"file"`
+			Errors []oracle.ValidationError `json:"errors"`
+		}
+
+		results := make([]fileResult, 0, len(*files))
+		var invalid int
+		for _, path := range *files {
+			errs := validateFeedConfigFile(path)
+			if len(errs) > 0 {
+				invalid++
+			} else {
+				errs = []oracle.ValidationError{}
+			}
+
+			results = append(results, fileResult{File: path, Errors: errs})
+		}
+
+		if *jsonOutput {
+			out, err := json.MarshalIndent(results, "", "  ")
+			if err != nil {
+				log.WithError(err).Fatalln("failed to marshal validation errors")
+			}
+
+			fmt.Println(string(out))
+		} else {
+			for _, res := range results {
+				if len(res.Errors) == 0 {
+					fmt.Printf("%s: OK\n", res.File)
+					continue
+				}
+
+				for _, err := range res.Errors {
+					fmt.Printf("%s: %v\n", res.File, err)
+				}
+			}
+		}
+
+		if invalid > 0 {
+			closer.Exit(1)
+		}
+	}
+}
+
+// validateFeedConfigFile reads a feed config file and the defaults of its dir, returning validation errors.
+func validateFeedConfigFile(path string) []oracle.ValidationError {
+	fileError := func(err error) []oracle.ValidationError {
+		return []oracle.ValidationError{{Feed: -1, Message: err.Error()}}
+	}
+
+	format := oracle.FeedConfigFormat(path)
+	if len(format) == 0 {
+		return fileError(fmt.Errorf("unsupported file extension %s (expected .toml, .yaml, .yml or .json)", filepath.Ext(path)))
+	}
+
+	body, err := os.ReadFile(path)
+	if err != nil {
+		return fileError(err)
+	}
+
+	var defaults *oracle.FeedConfig
+	if filepath.Base(path) != oracle.FeedDefaultsFile {
+		if defaults, err = loadFeedDefaults(filepath.Dir(path)); err != nil {
+			return fileError(err)
+		}
+	}
+
+	return oracle.ValidateConfig(body, format, defaults)
+}
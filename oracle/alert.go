@@ -0,0 +1,149 @@
+package oracle
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/InjectiveLabs/metrics"
+	log "github.com/InjectiveLabs/suplog"
+	"github.com/pkg/errors"
+)
+
+const (
+	defaultAlertFailureThreshold = 5
+	defaultAlertCooldown         = 15 * time.Minute
+)
+
+// AlertConfig configures webhook alerts on repeated failures.
+type AlertConfig struct {
+	// WebhookURL receives alerts as JSON POSTs, compatible with Slack and Discord incoming webhooks.
+	// Empty disables alerts.
+	WebhookURL string
+
+	// FailureThreshold is the number of consecutive failures of a feed pull or broadcast that raises an alert.
+	FailureThreshold int
+
+	// Cooldown is the minimum time between alerts of the same kind, so a persistent failure doesn't spam.
+	Cooldown time.Duration
+}
+
+// alertNotifier posts rate-limited alerts to a webhook.
+type alertNotifier struct {
+	webhookURL       string
+	failureThreshold int
+	cooldown         time.Duration
+	client           *http.Client
+
+	lastSent map[string]time.Time
+	mu       sync.Mutex
+
+	logger  log.Logger
+	svcTags metrics.Tags
+}
+
+func newAlertNotifier(cfg AlertConfig) *alertNotifier {
+	if len(cfg.WebhookURL) == 0 {
+		return nil
+	}
+
+	notifier := &alertNotifier{
+		webhookURL:       cfg.WebhookURL,
+		failureThreshold: cfg.FailureThreshold,
+		cooldown:         cfg.Cooldown,
+		client:           restHTTPClient,
+		lastSent:         make(map[string]time.Time),
+		logger:           log.WithField("svc", "alert"),
+		svcTags: metrics.Tags{
+			"svc": "alert",
+		},
+	}
+
+	if notifier.failureThreshold <= 0 {
+		notifier.failureThreshold = defaultAlertFailureThreshold
+	}
+
+	if notifier.cooldown <= 0 {
+		notifier.cooldown = defaultAlertCooldown
+	}
+
+	return notifier
+}
+
+// alertPayload carries the alert text under keys of both Slack (text) and Discord (content) webhooks,
+// along with structured fields for generic JSON receivers.
+type alertPayload struct {
+	Text     string    `json:"text"`
+	Content  string    `json:"content"`
+	Alert    string    `json:"alert"`
+	Failures int       `json:"failures"`
+	Time     time.Time `json:"time"`
+}
+
+// failed reports the number of consecutive failures of the alert kind, alerting once it reaches the threshold,
+// unless an alert of the same kind has been sent within the cooldown. Safe to call on a nil notifier.
+func (n *alertNotifier) failed(kind string, failures int, err error) {
+	if n == nil || failures < n.failureThreshold {
+		return
+	}
+
+	n.mu.Lock()
+	if lastSent, ok := n.lastSent[kind]; ok && time.Since(lastSent) < n.cooldown {
+		n.mu.Unlock()
+		return
+	}
+	n.lastSent[kind] = time.Now()
+	n.mu.Unlock()
+
+	payload := &alertPayload{
+		Alert:    kind,
+		Failures: failures,
+		Time:     time.Now().UTC(),
+	}
+	payload.Text = fmt.Sprintf("injective-price-oracle: %s failed %d times in a row: %v", kind, failures, err)
+	payload.Content = payload.Text
+
+	go func() {
+		if err := n.post(payload); err != nil {
+			metrics.ReportFuncError(n.svcTags)
+			n.logger.WithError(err).Warningln("failed to send alert")
+		}
+	}()
+}
+
+func (n *alertNotifier) post(payload *alertPayload) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal alert")
+	}
+
+	ctx, cancelFn := context.WithTimeout(context.Background(), maxRespTime)
+	defer cancelFn()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.webhookURL, bytes.NewReader(body))
+	if err != nil {
+		return errors.Wrap(err, "failed to create request")
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return errors.Wrap(err, "failed to POST alert")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, maxRespBytes))
+		return &httpStatusError{
+			StatusCode: resp.StatusCode,
+			Body:       string(respBody),
+		}
+	}
+
+	return nil
+}
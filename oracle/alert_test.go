@@ -0,0 +1,64 @@
+package oracle
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+func TestAlertNotifier(t *testing.T) {
+	alertsC := make(chan alertPayload, 10)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload alertPayload
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Errorf("invalid alert payload: %v", err)
+		}
+		alertsC <- payload
+	}))
+	defer srv.Close()
+
+	notifier := newAlertNotifier(AlertConfig{
+		WebhookURL:       srv.URL,
+		FailureThreshold: 3,
+		Cooldown:         time.Hour,
+	})
+
+	err := errors.New("connection refused")
+	for failures := 1; failures <= 5; failures++ {
+		notifier.failed("feed INJ/USDT (binance)", failures, err)
+	}
+	notifier.failed("broadcast", 3, err)
+
+	received := map[string]int{}
+	timeout := time.After(5 * time.Second)
+	for len(received) < 2 {
+		select {
+		case payload := <-alertsC:
+			received[payload.Alert]++
+			if payload.Text == "" || payload.Text != payload.Content {
+				t.Errorf("expected alert text for Slack and Discord, got %+v", payload)
+			}
+		case <-timeout:
+			t.Fatalf("timed out waiting for alerts, got %v", received)
+		}
+	}
+
+	// the feed alert is sent once within the cooldown, despite more failures
+	select {
+	case payload := <-alertsC:
+		t.Errorf("unexpected alert within cooldown: %+v", payload)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	if received["feed INJ/USDT (binance)"] != 1 || received["broadcast"] != 1 {
+		t.Errorf("expected one alert of each kind, got %v", received)
+	}
+
+	// alerts are disabled without a webhook URL
+	disabled := newAlertNotifier(AlertConfig{})
+	disabled.failed("broadcast", 100, err)
+}
@@ -0,0 +1,99 @@
+package oracle
+
+import (
+	"encoding/json"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// auditLog is an append-only JSON lines file recording every broadcast price batch.
+type auditLog struct {
+	file *os.File
+	mu   sync.Mutex
+}
+
+// auditRecord is a single line of the audit log.
+type auditRecord struct {
+	Time    time.Time      `json:"time"`
+	TxHash  string         `json:"txHash"`
+	Height  int64          `json:"height"`
+	Code    uint32         `json:"code"`
+	RawLog  string         `json:"rawLog,omitempty"`
+	Relayer string         `json:"relayer"`
+	Prices  []auditedPrice `json:"prices"`
+}
+
+type auditedPrice struct {
+	Ticker     string `json:"ticker"`
+	Provider   string `json:"provider"`
+	OracleType string `json:"oracleType"`
+	Price      string `json:"price"`
+}
+
+func openAuditLog(path string) (*auditLog, error) {
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to open audit log")
+	}
+
+	return &auditLog{
+		file: file,
+	}, nil
+}
+
+// write appends the record as a single line, syncing it to disk so it survives a crash.
+func (l *auditLog) write(record *auditRecord) error {
+	line, err := json.Marshal(record)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal audit record")
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if _, err := l.file.Write(append(line, '\n')); err != nil {
+		return errors.Wrap(err, "failed to write audit record")
+	}
+
+	return l.file.Sync()
+}
+
+func (l *auditLog) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.file.Close()
+}
+
+// auditBroadcast records a broadcast price batch in the audit log, if it's configured.
+func (s *oracleSvc) auditBroadcast(priceBatch []*PriceData, txHash string, height int64, code uint32, rawLog string) {
+	if s.auditLog == nil {
+		return
+	}
+
+	record := &auditRecord{
+		Time:    time.Now().UTC(),
+		TxHash:  txHash,
+		Height:  height,
+		Code:    code,
+		RawLog:  rawLog,
+		Relayer: s.cosmosClient.FromAddress().String(),
+		Prices:  make([]auditedPrice, 0, len(priceBatch)),
+	}
+
+	for _, priceData := range priceBatch {
+		record.Prices = append(record.Prices, auditedPrice{
+			Ticker:     string(priceData.Ticker),
+			Provider:   priceData.ProviderName,
+			OracleType: priceData.OracleType.String(),
+			Price:      priceData.Price.String(),
+		})
+	}
+
+	if err := s.auditLog.write(record); err != nil {
+		s.logger.WithError(err).Errorln("failed to write audit log")
+	}
+}
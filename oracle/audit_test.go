@@ -0,0 +1,50 @@
+package oracle
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestAuditLogAppends(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+
+	for _, txHash := range []string{"A1", "B2"} {
+		auditLog, err := openAuditLog(path)
+		if err != nil {
+			t.Fatalf("failed to open audit log: %v", err)
+		}
+
+		err = auditLog.write(&auditRecord{
+			TxHash: txHash,
+			Prices: []auditedPrice{{Ticker: "INJ/USDT", Price: "25.5"}},
+		})
+		if err != nil {
+			t.Fatalf("failed to write audit record: %v", err)
+		}
+
+		_ = auditLog.Close()
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("failed to open audit log: %v", err)
+	}
+	defer file.Close()
+
+	var hashes []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var record auditRecord
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			t.Fatalf("invalid audit record %s: %v", scanner.Text(), err)
+		}
+		hashes = append(hashes, record.TxHash)
+	}
+
+	if len(hashes) != 2 || hashes[0] != "A1" || hashes[1] != "B2" {
+		t.Errorf("expected records A1, B2 appended in order, got %v", hashes)
+	}
+}
@@ -0,0 +1,197 @@
+package oracle
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/InjectiveLabs/metrics"
+	log "github.com/InjectiveLabs/suplog"
+)
+
+// priceBatchJob is a batch of prices handed over to a broadcast worker.
+type priceBatchJob struct {
+	batch   map[string]*PriceData
+	timeout bool
+}
+
+// broadcastPool broadcasts price batches with a bounded number of workers, so a slow broadcast doesn't
+// stall batching. A price is never in two batches being broadcast at once.
+type broadcastPool struct {
+	svc  *oracleSvc
+	jobs chan priceBatchJob
+
+	// inFlight are keys of the prices being broadcast
+	inFlight map[string]struct{}
+	workers  map[*broadcastWorker]struct{}
+	mu       sync.Mutex
+	wg       sync.WaitGroup
+}
+
+type broadcastWorker struct {
+	// batch is being broadcast since startedAt, nil if the worker is idle
+	batch     map[string]*PriceData
+	startedAt time.Time
+}
+
+func newBroadcastPool(svc *oracleSvc, size int) *broadcastPool {
+	pool := &broadcastPool{
+		svc:      svc,
+		jobs:     make(chan priceBatchJob),
+		inFlight: make(map[string]struct{}),
+		workers:  make(map[*broadcastWorker]struct{}, size),
+	}
+
+	for i := 0; i < size; i++ {
+		pool.startWorker()
+	}
+
+	return pool
+}
+
+func (p *broadcastPool) startWorker() {
+	worker := &broadcastWorker{}
+
+	p.mu.Lock()
+	p.workers[worker] = struct{}{}
+	p.mu.Unlock()
+
+	p.wg.Add(1)
+	go p.runWorker(worker)
+}
+
+func (p *broadcastPool) runWorker(worker *broadcastWorker) {
+	defer func() {
+		p.mu.Lock()
+		delete(p.workers, worker)
+		p.mu.Unlock()
+
+		p.wg.Done()
+	}()
+
+	for job := range p.jobs {
+		p.mu.Lock()
+		worker.batch = job.batch
+		worker.startedAt = time.Now()
+		p.mu.Unlock()
+
+		p.svc.broadcastBatch(job.batch, job.timeout)
+
+		p.mu.Lock()
+		for key := range job.batch {
+			delete(p.inFlight, key)
+		}
+		worker.batch = nil
+		p.mu.Unlock()
+	}
+}
+
+// dispatch hands the prices of the batch not in flight over to a free worker, removing them from the batch.
+// If wait is false and all workers are busy, the prices are kept in the batch.
+func (p *broadcastPool) dispatch(pricesBatch map[string]*PriceData, timeout, wait bool) {
+	p.mu.Lock()
+	ready := make(map[string]*PriceData, len(pricesBatch))
+	for key, priceData := range pricesBatch {
+		if _, ok := p.inFlight[key]; !ok {
+			ready[key] = priceData
+			p.inFlight[key] = struct{}{}
+		}
+	}
+	p.mu.Unlock()
+
+	if len(ready) == 0 {
+		return
+	}
+
+	job := priceBatchJob{
+		batch:   ready,
+		timeout: timeout,
+	}
+
+	if wait {
+		p.jobs <- job
+	} else {
+		select {
+		case p.jobs <- job:
+		default:
+			p.mu.Lock()
+			for key := range ready {
+				delete(p.inFlight, key)
+			}
+			p.mu.Unlock()
+
+			p.svc.logger.WithField("batch_size", len(ready)).Debugln("all broadcast workers are busy, keeping prices for the next batch")
+			return
+		}
+	}
+
+	for key := range ready {
+		delete(pricesBatch, key)
+	}
+}
+
+// close waits for the workers to finish the broadcasts in progress.
+func (p *broadcastPool) close() {
+	close(p.jobs)
+	p.wg.Wait()
+}
+
+// stuckWorkers returns the workers broadcasting for longer than the threshold.
+func (p *broadcastPool) stuckWorkers(threshold time.Duration) (stuck []*broadcastWorker) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for worker := range p.workers {
+		if worker.batch != nil && time.Since(worker.startedAt) > threshold {
+			stuck = append(stuck, worker)
+		}
+	}
+
+	return stuck
+}
+
+// watchCommitLoop reports the commit loop as stuck when it hasn't processed a price or a batch timer tick,
+// or a broadcast hasn't returned, within the threshold, so a hung RPC call doesn't freeze submissions silently.
+// Stuck broadcasts are only reported: the chain client holds its broadcast lock until the call returns,
+// so a replacement worker would block on it as well.
+func (s *oracleSvc) watchCommitLoop(pool *broadcastPool, lastTick *atomic.Int64, done <-chan struct{}) {
+	checkInterval := s.commitStuckThreshold / 4
+	if checkInterval < time.Second {
+		checkInterval = time.Second
+	}
+
+	ticker := time.NewTicker(checkInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+		}
+
+		var stuck bool
+		if sinceTick := time.Since(time.Unix(0, lastTick.Load())); sinceTick > s.commitStuckThreshold {
+			stuck = true
+			s.logger.WithField("since_last_tick", sinceTick.Truncate(time.Second)).
+				Errorln("commit loop hasn't processed a price or batch timer tick, it's stuck")
+		}
+
+		for _, worker := range pool.stuckWorkers(s.commitStuckThreshold) {
+			stuck = true
+			s.logger.WithFields(log.Fields{
+				"batch_size":   len(worker.batch),
+				"broadcasting": time.Since(worker.startedAt).Truncate(time.Second),
+			}).Errorln("price batch broadcast is stuck")
+		}
+
+		stuckValue := 0.0
+		if stuck {
+			stuckValue = 1
+		}
+
+		metrics.CustomReport(func(s metrics.Statter, tagSpec []string) {
+			s.Gauge("price_oracle.commit_loop.stuck", stuckValue, tagSpec, 1)
+		}, s.svcTags)
+	}
+}
@@ -0,0 +1,31 @@
+package oracle
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBroadcastPoolStuckWorkers(t *testing.T) {
+	pool := newBroadcastPool(&oracleSvc{}, 0)
+
+	// a worker wedged in a broadcast of the INJ price, next to an idle one and a recent broadcast
+	stuck := &broadcastWorker{
+		batch:     map[string]*PriceData{"PriceFeed:INJ": {Symbol: "INJ"}},
+		startedAt: time.Now().Add(-time.Hour),
+	}
+	recent := &broadcastWorker{
+		batch:     map[string]*PriceData{"PriceFeed:ATOM": {Symbol: "ATOM"}},
+		startedAt: time.Now(),
+	}
+	idle := &broadcastWorker{
+		startedAt: time.Now().Add(-time.Hour),
+	}
+
+	pool.workers[stuck] = struct{}{}
+	pool.workers[recent] = struct{}{}
+	pool.workers[idle] = struct{}{}
+
+	if workers := pool.stuckWorkers(time.Minute); len(workers) != 1 || workers[0] != stuck {
+		t.Fatalf("expected only the wedged worker to be reported as stuck, got %v", workers)
+	}
+}
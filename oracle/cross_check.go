@@ -0,0 +1,226 @@
+package oracle
+
+import (
+	"time"
+
+	"github.com/InjectiveLabs/metrics"
+	oracletypes "github.com/InjectiveLabs/sdk-go/chain/oracle/types"
+	log "github.com/InjectiveLabs/suplog"
+	"github.com/pkg/errors"
+	"github.com/shopspring/decimal"
+)
+
+// crossCheck is a consistency check between a feed and a Stork feed of the same asset. Prices of either feed
+// diverging from the latest price of the other by more than the max divergence are not submitted. Prices of
+// the feed are submitted as the weighted median of both prices, Stork prices are signed so they're left as is.
+// Its state is only accessed by the commit loop, or by Simulate, which runs without it.
+type crossCheck struct {
+	feedTicker    string
+	storkTicker   string
+	maxDivergence decimal.Decimal
+	storkWeight   decimal.Decimal
+
+	// maxAge is how long the latest price of a feed is compared against, twice the pull interval of the feed
+	maxAge time.Duration
+
+	feedPrice  crossCheckPrice
+	storkPrice crossCheckPrice
+}
+
+type crossCheckPrice struct {
+	price decimal.Decimal
+	at    time.Time
+}
+
+func (p crossCheckPrice) fresh(maxAge time.Duration) bool {
+	return !p.at.IsZero() && time.Since(p.at) <= maxAge
+}
+
+// validateCrossCheck validates the optional cross check of the feed config against a Stork feed.
+func (c *FeedConfig) validateCrossCheck() error {
+	if len(c.CrossCheckTicker) == 0 {
+		if len(c.CrossCheckMaxDivergence) > 0 || len(c.CrossCheckWeight) > 0 {
+			return errors.New("cross check max divergence or weight is set, but cross check ticker is empty")
+		}
+
+		return nil
+	}
+
+	if c.ProviderName == FeedProviderStork.String() {
+		return errors.New("cross check must be set on the feed checked against the Stork feed, not on the Stork feed")
+	} else if c.CrossCheckTicker == c.Ticker {
+		return errors.New("cross check ticker must differ from the feed ticker")
+	}
+
+	if _, err := c.crossCheckMaxDivergence(); err != nil {
+		return err
+	}
+
+	if _, err := c.crossCheckWeight(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (c *FeedConfig) crossCheckMaxDivergence() (decimal.Decimal, error) {
+	maxDivergence, err := decimal.NewFromString(c.CrossCheckMaxDivergence)
+	if err != nil || !maxDivergence.IsPositive() {
+		return decimal.Zero, errors.Errorf("cross check max divergence must be a positive number, got %q", c.CrossCheckMaxDivergence)
+	}
+
+	return maxDivergence, nil
+}
+
+func (c *FeedConfig) crossCheckWeight() (decimal.Decimal, error) {
+	if len(c.CrossCheckWeight) == 0 {
+		return decimal.Zero, nil
+	}
+
+	weight, err := decimal.NewFromString(c.CrossCheckWeight)
+	if err != nil || weight.IsNegative() || weight.GreaterThan(decimal.NewFromInt(1)) {
+		return decimal.Zero, errors.Errorf("cross check weight must be a number between 0 and 1, got %q", c.CrossCheckWeight)
+	}
+
+	return weight, nil
+}
+
+// newCrossChecks inits cross checks of the feeds, keyed by the tickers of both the feed and the Stork feed.
+// Cross checks against a Stork feed that isn't loaded are disabled with a warning, e.g. if filtered out.
+func newCrossChecks(feedConfigs map[string]*FeedConfig, pricePullers map[string]PricePuller) (map[string]*crossCheck, error) {
+	storkFeeds := make(map[string]bool, len(feedConfigs))
+	for _, feedCfg := range feedConfigs {
+		storkFeeds[feedCfg.Ticker] = feedCfg.ProviderName == FeedProviderStork.String()
+	}
+
+	crossChecks := map[string]*crossCheck{}
+	for _, feedCfg := range feedConfigs {
+		if len(feedCfg.CrossCheckTicker) == 0 {
+			continue
+		}
+
+		if err := feedCfg.validateCrossCheck(); err != nil {
+			return nil, errors.Wrapf(err, "invalid cross check for ticker %s", feedCfg.Ticker)
+		}
+
+		isStork, ok := storkFeeds[feedCfg.CrossCheckTicker]
+		if !ok {
+			log.WithFields(log.Fields{
+				"ticker":       feedCfg.Ticker,
+				"cross_ticker": feedCfg.CrossCheckTicker,
+			}).Warningln("cross check ticker is not loaded, prices of the feed are submitted unchecked")
+			continue
+		} else if !isStork {
+			return nil, errors.Errorf("cross check ticker %s of ticker %s is not a Stork feed", feedCfg.CrossCheckTicker, feedCfg.Ticker)
+		} else if check, ok := crossChecks[feedCfg.CrossCheckTicker]; ok {
+			return nil, errors.Errorf("Stork feed %s is cross checked by both %s and %s", feedCfg.CrossCheckTicker, check.feedTicker, feedCfg.Ticker)
+		}
+
+		maxDivergence, _ := feedCfg.crossCheckMaxDivergence()
+		storkWeight, _ := feedCfg.crossCheckWeight()
+		check := &crossCheck{
+			feedTicker:    feedCfg.Ticker,
+			storkTicker:   feedCfg.CrossCheckTicker,
+			maxDivergence: maxDivergence,
+			storkWeight:   storkWeight,
+			maxAge:        2 * pricePullers[feedCfg.Ticker].Interval(),
+		}
+
+		crossChecks[check.feedTicker] = check
+		crossChecks[check.storkTicker] = check
+	}
+
+	return crossChecks, nil
+}
+
+// crossCheckPrice records the price as the latest one of its feed and checks it against the latest price
+// of the other feed, if fresh. Returns the price to submit, reconciled for prices of the feed, and whether
+// the prices are consistent.
+func (s *oracleSvc) crossCheckPrice(priceData *PriceData) (*PriceData, bool) {
+	check, ok := s.crossChecks[string(priceData.Ticker)]
+	if !ok {
+		return priceData, true
+	}
+
+	isStork := priceData.OracleType == oracletypes.OracleType_Stork
+	observed := crossCheckPrice{at: priceData.Timestamp}
+	if isStork {
+		observed.price = storkMedianPrice(priceData.AssetPair)
+		check.storkPrice = observed
+	} else {
+		observed.price = priceData.Price
+		check.feedPrice = observed
+	}
+
+	feedPrice, storkPrice := check.feedPrice, check.storkPrice
+	if !feedPrice.fresh(check.maxAge) || !storkPrice.fresh(check.maxAge) || !storkPrice.price.IsPositive() {
+		return priceData, true
+	}
+
+	// fresh tags, since Tags.With adds the tickers to the service tags in place
+	tags := metrics.Tags{
+		"svc":          "price_oracle",
+		"ticker":       check.feedTicker,
+		"stork_ticker": check.storkTicker,
+	}
+
+	divergence := feedPrice.price.Sub(storkPrice.price).Abs().Div(storkPrice.price)
+	divergenceValue, _ := divergence.Float64()
+	metrics.CustomReport(func(s metrics.Statter, tagSpec []string) {
+		s.Gauge("price_oracle.cross_check.divergence", divergenceValue, tagSpec, 1)
+	}, tags)
+
+	if divergence.GreaterThan(check.maxDivergence) {
+		metrics.CustomReport(func(s metrics.Statter, tagSpec []string) {
+			s.Count("price_oracle.cross_check.diverged.size", 1, tagSpec, 1)
+		}, tags)
+
+		s.logger.WithFields(log.Fields{
+			"ticker":         priceData.Ticker,
+			"provider":       priceData.ProviderName,
+			"feed_price":     feedPrice.price.String(),
+			"stork_ticker":   check.storkTicker,
+			"stork_price":    storkPrice.price.String(),
+			"divergence":     divergence.String(),
+			"max_divergence": check.maxDivergence.String(),
+		}).Warningln("feed and Stork prices diverge, skipping price")
+
+		return priceData, false
+	}
+
+	if isStork || check.storkWeight.IsZero() {
+		return priceData, true
+	}
+
+	reconciled := *priceData
+	reconciled.Price = weightedMedian(feedPrice.price, storkPrice.price, check.storkWeight)
+	return s.roundToSubmitPrecision(&reconciled), true
+}
+
+// weightedMedian returns the weighted median of the feed price and the Stork price weighted by storkWeight,
+// which for two prices is the one weighing more than a half, or their mean for equal weights.
+func weightedMedian(feedPrice, storkPrice, storkWeight decimal.Decimal) decimal.Decimal {
+	half := decimal.NewFromFloat(0.5)
+	switch {
+	case storkWeight.GreaterThan(half):
+		return storkPrice
+	case storkWeight.LessThan(half):
+		return feedPrice
+	default:
+		return feedPrice.Add(storkPrice).Div(decimal.NewFromInt(2))
+	}
+}
+
+// storkMedianPrice returns the median of the signed prices of the asset pair, zero if it has none.
+func storkMedianPrice(pair *oracletypes.AssetPair) decimal.Decimal {
+	if pair == nil || len(pair.SignedPrices) == 0 {
+		return decimal.Zero
+	}
+
+	prices := make([]decimal.Decimal, 0, len(pair.SignedPrices))
+	for _, signedPrice := range pair.SignedPrices {
+		prices = append(prices, decimal.RequireFromString(signedPrice.Price.String()))
+	}
+
+	return medianDecimal(prices)
+}
@@ -0,0 +1,127 @@
+package oracle
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"cosmossdk.io/math"
+	oracletypes "github.com/InjectiveLabs/sdk-go/chain/oracle/types"
+	"github.com/shopspring/decimal"
+)
+
+func TestCrossCheckPrice(t *testing.T) {
+	newService := func(weight string) *oracleSvc {
+		svc, err := NewService(context.Background(), nil, nil, nil, map[string]*FeedConfig{
+			"inj.toml": {
+				ProviderName:            "test",
+				Ticker:                  "INJ/USDT",
+				OracleType:              "PriceFeed",
+				ObservationSource:       `price [type=memo value="25.5"]`,
+				CrossCheckTicker:        "INJUSD",
+				CrossCheckMaxDivergence: "0.02",
+				CrossCheckWeight:        weight,
+			},
+			"inj_stork.toml": {
+				ProviderName: "stork",
+				Ticker:       "INJUSD",
+				OracleType:   "Stork",
+			},
+		}, nil, ServiceConfig{})
+		if err != nil {
+			t.Fatalf("failed to init service: %v", err)
+		}
+
+		return svc.(*oracleSvc)
+	}
+
+	feedPrice := func(price string) *PriceData {
+		return &PriceData{
+			Ticker:     "INJ/USDT",
+			Price:      decimal.RequireFromString(price),
+			OracleType: oracletypes.OracleType_PriceFeed,
+			Timestamp:  time.Now(),
+		}
+	}
+
+	storkPrice := func(prices ...string) *PriceData {
+		pair := &oracletypes.AssetPair{AssetId: "INJUSD"}
+		for _, price := range prices {
+			pair.SignedPrices = append(pair.SignedPrices, &oracletypes.SignedPriceOfAssetPair{Price: math.LegacyMustNewDecFromStr(price)})
+		}
+
+		return &PriceData{
+			Ticker:     "INJUSD",
+			AssetPair:  pair,
+			OracleType: oracletypes.OracleType_Stork,
+			Timestamp:  time.Now(),
+		}
+	}
+
+	svc := newService("")
+
+	// nothing to check against until both feeds have a price
+	if _, ok := svc.crossCheckPrice(feedPrice("30")); !ok {
+		t.Error("expected feed price accepted without a Stork price")
+	}
+
+	// the Stork price diverges from the latest feed price
+	if _, ok := svc.crossCheckPrice(storkPrice("24", "25", "26")); ok {
+		t.Error("expected Stork price skipped when diverging from the feed price")
+	}
+
+	for price, expected := range map[string]bool{
+		"24.4": false,
+		"24.5": true,
+		"25.5": true,
+		"25.6": false,
+	} {
+		priceData, ok := svc.crossCheckPrice(feedPrice(price))
+		if ok != expected {
+			t.Errorf("expected crossCheckPrice(%s) = %v, got %v", price, expected, ok)
+		} else if !priceData.Price.Equal(decimal.RequireFromString(price)) {
+			t.Errorf("expected price %s submitted as pulled without a weight, got %s", price, priceData.Price)
+		}
+	}
+
+	// a stale Stork price is not checked against
+	svc.crossChecks["INJUSD"].storkPrice.at = time.Now().Add(-time.Hour)
+	if _, ok := svc.crossCheckPrice(feedPrice("30")); !ok {
+		t.Error("expected feed price accepted with a stale Stork price")
+	}
+
+	for weight, expected := range map[string]string{
+		"0.2": "25.4",
+		"0.5": "25.2",
+		"0.8": "25",
+	} {
+		svc := newService(weight)
+		svc.crossCheckPrice(storkPrice("25"))
+
+		priceData, ok := svc.crossCheckPrice(feedPrice("25.4"))
+		if !ok {
+			t.Fatalf("weight %s: expected feed price accepted", weight)
+		} else if !priceData.Price.Equal(decimal.RequireFromString(expected)) {
+			t.Errorf("weight %s: expected reconciled price %s, got %s", weight, expected, priceData.Price)
+		}
+	}
+}
+
+func TestValidateCrossCheck(t *testing.T) {
+	for _, feedCfg := range []*FeedConfig{
+		{Ticker: "INJ/USDT", CrossCheckMaxDivergence: "0.02"},
+		{Ticker: "INJ/USDT", CrossCheckTicker: "INJUSD"},
+		{Ticker: "INJ/USDT", CrossCheckTicker: "INJUSD", CrossCheckMaxDivergence: "-0.02"},
+		{Ticker: "INJ/USDT", CrossCheckTicker: "INJUSD", CrossCheckMaxDivergence: "0.02", CrossCheckWeight: "1.5"},
+		{Ticker: "INJ/USDT", CrossCheckTicker: "INJ/USDT", CrossCheckMaxDivergence: "0.02"},
+		{ProviderName: "stork", Ticker: "INJUSD", CrossCheckTicker: "INJ/USDT", CrossCheckMaxDivergence: "0.02"},
+	} {
+		if err := feedCfg.validateCrossCheck(); err == nil {
+			t.Errorf("expected error for cross check of %+v", feedCfg)
+		}
+	}
+
+	if err := (&FeedConfig{Ticker: "INJ/USDT", CrossCheckTicker: "INJUSD", CrossCheckMaxDivergence: "0.02", CrossCheckWeight: "0.5"}).validateCrossCheck(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
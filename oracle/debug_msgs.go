@@ -0,0 +1,59 @@
+package oracle
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+
+	oracletypes "github.com/InjectiveLabs/sdk-go/chain/oracle/types"
+	log "github.com/InjectiveLabs/suplog"
+	cosmtypes "github.com/cosmos/cosmos-sdk/types"
+)
+
+// debugLogMsgs dumps the composed messages pretty-printed, along with the number of messages of every type
+// and of Stork asset pairs. It's a no-op unless the debug log level is enabled, since marshalling is costly.
+func debugLogMsgs(logger log.Logger, msgs []cosmtypes.Msg) {
+	if !log.DefaultLogger.IsLevelEnabled(log.DebugLevel) {
+		return
+	}
+
+	msgCounts := make(map[string]int)
+	var assetPairs int
+	for i, msg := range msgs {
+		typeURL := cosmtypes.MsgTypeURL(msg)
+		msgCounts[typeURL]++
+
+		if storkMsg, ok := msg.(*oracletypes.MsgRelayStorkPrices); ok {
+			assetPairs += len(storkMsg.AssetPairs)
+		}
+
+		body, err := json.MarshalIndent(msg, "", "  ")
+		if err != nil {
+			logger.WithError(err).Debugf("failed to marshal composed msg #%d (%s)", i, typeURL)
+			continue
+		}
+
+		logger.Debugf("composed msg #%d (%s):\n%s", i, typeURL, body)
+	}
+
+	logger.WithFields(log.Fields{
+		"msg_counts":  msgCounts,
+		"asset_pairs": assetPairs,
+	}).Debugf("composed %d msgs", len(msgs))
+}
+
+// debugLogBatch dumps the prices of a batch that composed no messages, so it's clear which were dropped.
+// It's a no-op unless the debug log level is enabled.
+func debugLogBatch(logger log.Logger, priceBatch []*PriceData) {
+	if !log.DefaultLogger.IsLevelEnabled(log.DebugLevel) {
+		return
+	}
+
+	prices := make([]string, 0, len(priceBatch))
+	for _, priceData := range priceBatch {
+		prices = append(prices, priceData.OracleType.String()+":"+string(priceData.Ticker)+"="+priceData.Price.String())
+	}
+	sort.Strings(prices)
+
+	logger.WithField("prices", strings.Join(prices, ", ")).Debugln("prices of the batch that composed no messages")
+}
@@ -0,0 +1,46 @@
+package oracle
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+
+	"cosmossdk.io/math"
+	oracletypes "github.com/InjectiveLabs/sdk-go/chain/oracle/types"
+	log "github.com/InjectiveLabs/suplog"
+	cosmtypes "github.com/cosmos/cosmos-sdk/types"
+)
+
+func TestDebugLogMsgs(t *testing.T) {
+	var out bytes.Buffer
+	level := log.DefaultLogger.GetLevel()
+	log.DefaultLogger.SetOutput(&out)
+	defer func() {
+		log.DefaultLogger.SetLevel(level)
+		log.DefaultLogger.SetOutput(os.Stderr)
+	}()
+
+	msgs := []cosmtypes.Msg{
+		&oracletypes.MsgRelayPriceFeedPrice{
+			Sender: "inj1relayer",
+			Base:   []string{"INJ"},
+			Quote:  []string{"USDT"},
+			Price:  []math.LegacyDec{math.LegacyMustNewDecFromStr("25.5")},
+		},
+	}
+
+	log.DefaultLogger.SetLevel(log.InfoLevel)
+	debugLogMsgs(log.DefaultLogger, msgs)
+	if out.Len() > 0 {
+		t.Fatalf("expected no output above the debug level, got %s", out.String())
+	}
+
+	log.DefaultLogger.SetLevel(log.DebugLevel)
+	debugLogMsgs(log.DefaultLogger, msgs)
+	for _, expected := range []string{"/injective.oracle.v1beta1.MsgRelayPriceFeedPrice", "inj1relayer", "25.5", "composed 1 msgs"} {
+		if !strings.Contains(out.String(), expected) {
+			t.Errorf("expected %q in the dump, got %s", expected, out.String())
+		}
+	}
+}
@@ -0,0 +1,205 @@
+package oracle
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	"cosmossdk.io/math"
+
+	log "github.com/InjectiveLabs/suplog"
+	"github.com/cosmos/cosmos-sdk/client/tx"
+	cosmtypes "github.com/cosmos/cosmos-sdk/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+	txtypes "github.com/cosmos/cosmos-sdk/types/tx"
+	"github.com/pkg/errors"
+
+	"github.com/InjectiveLabs/metrics"
+	chainclient "github.com/InjectiveLabs/sdk-go/client/chain"
+)
+
+const (
+	// feeBumpBroadcastTimeout limits a single bumped broadcast, including the wait for its inclusion.
+	feeBumpBroadcastTimeout = time.Minute
+	feeBumpTxPollInterval   = time.Second
+)
+
+// FeeBumpConfig configures rebroadcasts of Txs rejected for insufficient fees.
+type FeeBumpConfig struct {
+	// GasPrices are the gas prices the chain client broadcasts with, e.g. 500000000inj.
+	// Empty disables fee bumps.
+	GasPrices string
+
+	// Factor multiplies the gas prices on every retry, e.g. 1.5 raises them by 50% per retry.
+	// A factor of 1 or less disables fee bumps.
+	Factor float64
+
+	// MaxRetries caps the number of retries with bumped gas prices, zero disables fee bumps.
+	MaxRetries int
+}
+
+// feeBumper rebroadcasts Txs rejected for insufficient fees with gas prices raised by a factor
+// on every retry, so submissions keep landing during fee spikes.
+type feeBumper struct {
+	gasPrices  cosmtypes.DecCoins
+	factor     math.LegacyDec
+	maxRetries int
+}
+
+func newFeeBumper(cfg FeeBumpConfig) (*feeBumper, error) {
+	if len(cfg.GasPrices) == 0 || cfg.Factor <= 1 || cfg.MaxRetries <= 0 {
+		return nil, nil
+	}
+
+	gasPrices, err := cosmtypes.ParseDecCoins(cfg.GasPrices)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to parse gas prices: %s", cfg.GasPrices)
+	} else if gasPrices.IsZero() {
+		return nil, nil
+	}
+
+	factor, err := math.LegacyNewDecFromStr(strconv.FormatFloat(cfg.Factor, 'f', 6, 64))
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to parse fee bump factor: %v", cfg.Factor)
+	}
+
+	return &feeBumper{
+		gasPrices:  gasPrices,
+		factor:     factor,
+		maxRetries: cfg.MaxRetries,
+	}, nil
+}
+
+// gasPricesAt returns the gas prices of the given retry, raised by the factor once per retry.
+func (b *feeBumper) gasPricesAt(retry int) cosmtypes.DecCoins {
+	multiplier := b.factor.Power(uint64(retry))
+	return b.gasPrices.MulDec(multiplier)
+}
+
+// isInsufficientFeeRejection checks whether the Tx was rejected for paying fees below the minimum gas
+// prices, either by its result code or, if the broadcast failed, by the error.
+func isInsufficientFeeRejection(txResp *txtypes.BroadcastTxResponse, err error) bool {
+	if txResp != nil && txResp.TxResponse != nil {
+		return txResp.TxResponse.Codespace == sdkerrors.ErrInsufficientFee.Codespace() &&
+			txResp.TxResponse.Code == sdkerrors.ErrInsufficientFee.ABCICode()
+	}
+
+	return err != nil && strings.Contains(err.Error(), sdkerrors.ErrInsufficientFee.Error())
+}
+
+// broadcastMsgs broadcasts the messages in a single Tx, with bumped gas prices if rejected for insufficient fees.
+// Bumped broadcasts sign with the account sequence queried from the chain, bypassing the chain client's lock
+// and sequence, so the broadcast lock serializes them with the client broadcasts of other workers.
+func (s *oracleSvc) broadcastMsgs(msgs []cosmtypes.Msg) (*txtypes.BroadcastTxResponse, error) {
+	s.broadcastMu.Lock()
+	defer s.broadcastMu.Unlock()
+
+	txResp, err := s.cosmosClient.SyncBroadcastMsg(msgs...)
+	if s.feeBump != nil && isInsufficientFeeRejection(txResp, err) {
+		txResp, err = s.broadcastWithFeeBump(msgs)
+	}
+
+	return txResp, err
+}
+
+// broadcastWithFeeBump rebroadcasts messages of a Tx rejected for insufficient fees, raising the gas prices
+// on every retry until the Tx is accepted, fails for another reason, or the retries are exhausted.
+func (s *oracleSvc) broadcastWithFeeBump(msgs []cosmtypes.Msg) (txResp *txtypes.BroadcastTxResponse, err error) {
+	for retry := 1; retry <= s.feeBump.maxRetries; retry++ {
+		gasPrices := s.feeBump.gasPricesAt(retry)
+
+		metrics.CustomReport(func(s metrics.Statter, tagSpec []string) {
+			s.Count("price_oracle.broadcast.fee_bumped.size", 1, tagSpec, 1)
+		}, s.svcTags)
+
+		s.logger.WithFields(log.Fields{
+			"retry":      retry,
+			"gas_prices": gasPrices.String(),
+		}).Warningln("Tx rejected for insufficient fees, retrying with bumped gas prices")
+
+		txResp, err = s.broadcastWithGasPrices(msgs, gasPrices)
+		if !isInsufficientFeeRejection(txResp, err) {
+			return txResp, err
+		}
+	}
+
+	return txResp, err
+}
+
+// broadcastWithGasPrices signs and broadcasts messages with the given gas prices, bypassing the gas prices
+// of the chain client, and waits for the Tx inclusion. The account sequence is queried from the chain,
+// since the rejected Tx didn't consume the one the chain client has reserved for it. Must be called
+// with the broadcast lock held, so no client broadcast signs with the same sequence meanwhile.
+func (s *oracleSvc) broadcastWithGasPrices(msgs []cosmtypes.Msg, gasPrices cosmtypes.DecCoins) (*txtypes.BroadcastTxResponse, error) {
+	ctx, cancelFn := context.WithTimeout(context.Background(), feeBumpBroadcastTimeout)
+	defer cancelFn()
+
+	clientCtx := s.cosmosClient.ClientContext()
+	txClient := txtypes.NewServiceClient(s.cosmosClient.QueryClient())
+
+	txf, err := chainclient.PrepareFactory(clientCtx, chainclient.NewTxFactory(clientCtx).WithGasPrices(gasPrices.String()))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to prepare Tx factory")
+	}
+
+	simTxBytes, err := txf.BuildSimTx(msgs...)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to build sim Tx bytes")
+	}
+
+	simRes, err := txClient.Simulate(ctx, &txtypes.SimulateRequest{TxBytes: simTxBytes})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to simulate Tx")
+	}
+	txf = txf.WithGas(uint64(txf.GasAdjustment() * float64(simRes.GasInfo.GasUsed)))
+
+	txn, err := txf.BuildUnsignedTx(msgs...)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to build unsigned Tx")
+	}
+	txn.SetFeeGranter(clientCtx.GetFeeGranterAddress())
+
+	if err := tx.Sign(ctx, txf, clientCtx.GetFromName(), txn, true); err != nil {
+		return nil, errors.Wrap(err, "failed to sign Tx")
+	}
+
+	txBytes, err := clientCtx.TxConfig.TxEncoder()(txn.GetTx())
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to encode Tx")
+	}
+
+	txResp, err := txClient.BroadcastTx(ctx, &txtypes.BroadcastTxRequest{
+		TxBytes: txBytes,
+		Mode:    txtypes.BroadcastMode_BROADCAST_MODE_SYNC,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to broadcast Tx")
+	} else if txResp.TxResponse == nil || txResp.TxResponse.Code != 0 {
+		// rejected by CheckTx, never included
+		return txResp, nil
+	}
+
+	return awaitTx(ctx, txClient, txResp.TxResponse.TxHash)
+}
+
+// awaitTx polls the Tx until it's included in a block.
+func awaitTx(ctx context.Context, txClient txtypes.ServiceClient, txHash string) (*txtypes.BroadcastTxResponse, error) {
+	t := time.NewTicker(feeBumpTxPollInterval)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, errors.Wrapf(chainclient.ErrTimedOut, "%s", txHash)
+		case <-t.C:
+			res, err := txClient.GetTx(ctx, &txtypes.GetTxRequest{Hash: txHash})
+			if err != nil || res.TxResponse == nil || res.TxResponse.Height == 0 {
+				// not included yet
+				continue
+			}
+
+			return &txtypes.BroadcastTxResponse{TxResponse: res.TxResponse}, nil
+		}
+	}
+}
@@ -0,0 +1,202 @@
+package oracle
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/cosmos/cosmos-sdk/client"
+	cosmtypes "github.com/cosmos/cosmos-sdk/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+	txtypes "github.com/cosmos/cosmos-sdk/types/tx"
+	"github.com/pkg/errors"
+)
+
+func TestFeeBumperGasPrices(t *testing.T) {
+	bumper, err := newFeeBumper(FeeBumpConfig{
+		GasPrices:  "500000000inj",
+		Factor:     1.5,
+		MaxRetries: 3,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if bumper == nil {
+		t.Fatalf("expected fee bumps to be enabled")
+	}
+
+	for retry, expected := range map[int]string{
+		1: "750000000.000000000000000000inj",
+		2: "1125000000.000000000000000000inj",
+		3: "1687500000.000000000000000000inj",
+	} {
+		if got := bumper.gasPricesAt(retry).String(); got != expected {
+			t.Errorf("expected gas prices %s at retry %d, got %s", expected, retry, got)
+		}
+	}
+
+	for _, cfg := range []FeeBumpConfig{
+		{Factor: 1.5, MaxRetries: 3},
+		{GasPrices: "500000000inj", Factor: 1, MaxRetries: 3},
+		{GasPrices: "500000000inj", Factor: 1.5},
+	} {
+		if bumper, err := newFeeBumper(cfg); err != nil || bumper != nil {
+			t.Errorf("expected fee bumps to be disabled for config %+v", cfg)
+		}
+	}
+
+	if _, err := newFeeBumper(FeeBumpConfig{GasPrices: "inj", Factor: 1.5, MaxRetries: 3}); err == nil {
+		t.Errorf("expected error for malformed gas prices")
+	}
+}
+
+func TestIsInsufficientFeeRejection(t *testing.T) {
+	txResp := func(codespace string, code uint32) *txtypes.BroadcastTxResponse {
+		return &txtypes.BroadcastTxResponse{
+			TxResponse: &cosmtypes.TxResponse{
+				Codespace: codespace,
+				Code:      code,
+			},
+		}
+	}
+
+	tests := []struct {
+		name     string
+		txResp   *txtypes.BroadcastTxResponse
+		err      error
+		expected bool
+	}{
+		{
+			name:     "Insufficient fee code",
+			txResp:   txResp(sdkerrors.RootCodespace, sdkerrors.ErrInsufficientFee.ABCICode()),
+			expected: true,
+		},
+		{
+			name:   "Same code of another codespace",
+			txResp: txResp("oracle", sdkerrors.ErrInsufficientFee.ABCICode()),
+		},
+		{
+			name:   "Successful Tx",
+			txResp: txResp("", 0),
+		},
+		{
+			name:     "Insufficient fee error",
+			err:      errors.Wrap(sdkerrors.ErrInsufficientFee, "insufficient fees; got: 1inj required: 2inj"),
+			expected: true,
+		},
+		{
+			name: "Other error",
+			err:  errors.New("account sequence mismatch"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isInsufficientFeeRejection(tt.txResp, tt.err); got != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+type feeBumpStubClient struct {
+	stubChainClient
+
+	retriever *blockingAccountRetriever
+	calls     atomic.Int32
+	overlaps  atomic.Int32
+}
+
+// SyncBroadcastMsg rejects the first Tx for insufficient fees, and counts broadcasts overlapping a bumped one.
+func (c *feeBumpStubClient) SyncBroadcastMsg(...cosmtypes.Msg) (*txtypes.BroadcastTxResponse, error) {
+	if c.retriever.bumping.Load() {
+		c.overlaps.Add(1)
+	}
+
+	if c.calls.Add(1) == 1 {
+		return &txtypes.BroadcastTxResponse{TxResponse: &cosmtypes.TxResponse{
+			Codespace: sdkerrors.ErrInsufficientFee.Codespace(),
+			Code:      sdkerrors.ErrInsufficientFee.ABCICode(),
+		}}, nil
+	}
+
+	return &txtypes.BroadcastTxResponse{TxResponse: &cosmtypes.TxResponse{Height: 100, TxHash: "ABCD"}}, nil
+}
+
+func (c *feeBumpStubClient) ClientContext() client.Context {
+	return client.Context{}.WithAccountRetriever(c.retriever)
+}
+
+// blockingAccountRetriever holds a bumped broadcast while it prepares the Tx factory, until released.
+type blockingAccountRetriever struct {
+	client.AccountRetriever
+
+	bumping  atomic.Bool
+	started  chan struct{}
+	released chan struct{}
+}
+
+func (r *blockingAccountRetriever) EnsureExists(client.Context, cosmtypes.AccAddress) error {
+	r.bumping.Store(true)
+	defer r.bumping.Store(false)
+
+	close(r.started)
+	<-r.released
+
+	return errors.New("account not found")
+}
+
+func TestFeeBumpSerializedWithBroadcasts(t *testing.T) {
+	cosmosClient := &feeBumpStubClient{
+		stubChainClient: stubChainClient{from: cosmtypes.AccAddress("sender______________")},
+		retriever: &blockingAccountRetriever{
+			started:  make(chan struct{}),
+			released: make(chan struct{}),
+		},
+	}
+
+	svc, err := NewService(context.Background(), cosmosClient, nil, nil, map[string]*FeedConfig{}, nil, ServiceConfig{
+		MaxConcurrentBroadcasts: 2,
+		FeeBump: FeeBumpConfig{
+			GasPrices:  "500000000inj",
+			Factor:     1.5,
+			MaxRetries: 1,
+		},
+	})
+	if err != nil {
+		t.Fatalf("failed to init service: %v", err)
+	}
+	oracleSvc := svc.(*oracleSvc)
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+
+	go func() {
+		defer wg.Done()
+		if _, err := oracleSvc.broadcastMsgs(nil); err == nil {
+			t.Error("expected the bumped broadcast to fail")
+		}
+	}()
+
+	// broadcast another batch while the first one is being fee bumped
+	<-cosmosClient.retriever.started
+	go func() {
+		defer wg.Done()
+		if _, err := oracleSvc.broadcastMsgs(nil); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	}()
+
+	time.Sleep(100 * time.Millisecond)
+	close(cosmosClient.retriever.released)
+	wg.Wait()
+
+	if calls := cosmosClient.calls.Load(); calls != 2 {
+		t.Errorf("expected 2 client broadcasts, got %d", calls)
+	}
+
+	if overlaps := cosmosClient.overlaps.Load(); overlaps != 0 {
+		t.Errorf("expected no client broadcast during the bumped one, got %d", overlaps)
+	}
+}
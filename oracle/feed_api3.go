@@ -0,0 +1,137 @@
+package oracle
+
+import (
+	"context"
+	"math/big"
+	"time"
+
+	"github.com/InjectiveLabs/metrics"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/pkg/errors"
+	"github.com/shopspring/decimal"
+)
+
+const (
+	// api3ReadSelector is the selector of read() returns (int224 value, uint32 timestamp) of a dAPI proxy
+	api3ReadSelector = "0x57de26a4"
+
+	// api3Decimals is the number of decimals of dAPI values
+	api3Decimals = 18
+
+	// api3DefaultMaxPriceAge is used when the feed config has no maxPriceAge, dAPIs are updated
+	// at least every 24h heartbeat.
+	api3DefaultMaxPriceAge = 24 * time.Hour
+)
+
+var _ PricePuller = &api3PriceFeed{}
+
+// api3PriceFeed reads the value of an API3 dAPI proxy contract over an EVM JSON-RPC endpoint.
+type api3PriceFeed struct {
+	*restPriceFeed
+
+	proxy       common.Address
+	rpcEndpoint string
+}
+
+// NewAPI3PriceFeed returns price puller for API3 dAPIs. The symbol config field is required and holds
+// the dAPI proxy address, rpcEndpoint is the JSON-RPC endpoint of the chain the proxy is deployed on.
+func NewAPI3PriceFeed(cfg *FeedConfig) (PricePuller, error) {
+	restFeed, err := newRestPriceFeed(FeedProviderAPI3, cfg, "")
+	if err != nil {
+		return nil, err
+	}
+
+	if !common.IsHexAddress(restFeed.symbol) {
+		return nil, errors.Errorf("symbol must be a dAPI proxy address for %s provider, got %s", FeedProviderAPI3, restFeed.symbol)
+	}
+
+	if len(cfg.RPCEndpoint) == 0 {
+		return nil, errors.Errorf("rpcEndpoint must be set for %s provider", FeedProviderAPI3)
+	}
+
+	if restFeed.maxPriceAge == 0 {
+		restFeed.maxPriceAge = api3DefaultMaxPriceAge
+	}
+
+	return &api3PriceFeed{
+		restPriceFeed: restFeed,
+		proxy:         common.HexToAddress(restFeed.symbol),
+		rpcEndpoint:   cfg.RPCEndpoint,
+	}, nil
+}
+
+type ethCallRequest struct {
+	JSONRPC string        `json:"jsonrpc"`
+	ID      int           `json:"id"`
+	Method  string        `json:"method"`
+	Params  []interface{} `json:"params"`
+}
+
+type ethCallResponse struct {
+	Result string `json:"result"`
+	Error  *struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+func (f *api3PriceFeed) PullPrice(ctx context.Context) (*PriceData, error) {
+	req := ethCallRequest{
+		JSONRPC: "2.0",
+		ID:      1,
+		Method:  "eth_call",
+		Params: []interface{}{
+			map[string]string{
+				"to":   f.proxy.Hex(),
+				"data": api3ReadSelector,
+			},
+			"latest",
+		},
+	}
+
+	var resp ethCallResponse
+	if err := f.postJSON(ctx, f.rpcEndpoint, nil, req, &resp); err != nil {
+		return nil, err
+	}
+
+	if resp.Error != nil {
+		return nil, errors.Errorf("eth_call error %d: %s", resp.Error.Code, resp.Error.Message)
+	}
+
+	value, timestamp, err := decodeAPI3Read(resp.Result)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to decode read() of dAPI proxy %s", f.proxy.Hex())
+	}
+
+	metrics.CustomReport(func(s metrics.Statter, tagSpec []string) {
+		s.Timing("price_oracle.api3.timestamp_age", time.Since(timestamp), tagSpec, 1)
+	}, f.svcTags.With("ticker", f.ticker))
+
+	return f.priceDataAt(value, timestamp)
+}
+
+// decodeAPI3Read decodes the ABI-encoded (int224 value, uint32 timestamp) returned by read() of a dAPI proxy.
+func decodeAPI3Read(result string) (value decimal.Decimal, timestamp time.Time, err error) {
+	data, err := hexutil.Decode(result)
+	if err != nil {
+		return value, timestamp, errors.Wrapf(err, "failed to decode hex result: %s", result)
+	}
+
+	if len(data) != 64 {
+		return value, timestamp, errors.Errorf("expected 64 bytes of result, got %d", len(data))
+	}
+
+	// int224 is sign-extended to 256 bits
+	v := new(big.Int).SetBytes(data[:32])
+	if data[0]&0x80 != 0 {
+		v.Sub(v, new(big.Int).Lsh(big.NewInt(1), 256))
+	}
+
+	ts := new(big.Int).SetBytes(data[32:])
+	if !ts.IsUint64() || ts.Uint64() > uint64(^uint32(0)) {
+		return value, timestamp, errors.Errorf("timestamp out of uint32 range: %s", ts)
+	}
+
+	return decimal.NewFromBigInt(v, -api3Decimals), time.Unix(int64(ts.Uint64()), 0), nil
+}
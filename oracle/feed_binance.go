@@ -0,0 +1,131 @@
+package oracle
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/shopspring/decimal"
+)
+
+const (
+	binanceSpotBaseURL    = "https://api.binance.com"
+	binanceUSSpotBaseURL  = "https://api.binance.us"
+	binanceFuturesBaseURL = "https://fapi.binance.com"
+
+	BinanceMarketSpot    = "spot"
+	BinanceMarketFutures = "futures"
+
+	BinanceRegionGlobal = "global"
+	BinanceRegionUS     = "us"
+)
+
+var _ PricePuller = &binancePriceFeed{}
+
+// binancePriceFeed pulls the last trade price of a spot pair, or the mark price of a USDⓈ-M perpetual from Binance.
+type binancePriceFeed struct {
+	*restPriceFeed
+
+	market  string
+	baseURL string
+}
+
+// NewBinancePriceFeed returns price puller for Binance tickers. Binance symbols are uppercase and
+// concatenated (e.g. BTCUSDT), by default derived from the ticker, unless set by the symbol config field.
+// The market config field selects between spot last price (default) and futures mark price, which is
+// less manipulable than the last trade for derivatives references. The region config field selects
+// Binance.US for operators that can't reach the global API, it lists spot markets only, with the same symbols.
+func NewBinancePriceFeed(cfg *FeedConfig) (PricePuller, error) {
+	defaultSymbol := strings.ToUpper(strings.ReplaceAll(cfg.Ticker, "/", ""))
+
+	restFeed, err := newRestPriceFeed(FeedProviderBinance, cfg, defaultSymbol)
+	if err != nil {
+		return nil, err
+	}
+
+	feed := &binancePriceFeed{
+		restPriceFeed: restFeed,
+		market:        cfg.Market,
+	}
+
+	switch cfg.Market {
+	case "", BinanceMarketSpot:
+		feed.market = BinanceMarketSpot
+		feed.baseURL = binanceSpotBaseURL
+	case BinanceMarketFutures:
+		feed.baseURL = binanceFuturesBaseURL
+	default:
+		return nil, errors.Errorf("unsupported market %s for %s provider (expected %s or %s)",
+			cfg.Market, FeedProviderBinance, BinanceMarketSpot, BinanceMarketFutures)
+	}
+
+	switch cfg.Region {
+	case "", BinanceRegionGlobal:
+	case BinanceRegionUS:
+		if feed.market != BinanceMarketSpot {
+			return nil, errors.Errorf("%s market is not available in %s region of %s provider", feed.market, cfg.Region, FeedProviderBinance)
+		}
+		feed.baseURL = binanceUSSpotBaseURL
+	default:
+		return nil, errors.Errorf("unsupported region %s for %s provider (expected %s or %s)",
+			cfg.Region, FeedProviderBinance, BinanceRegionGlobal, BinanceRegionUS)
+	}
+
+	return feed, nil
+}
+
+type binanceSpotPrice struct {
+	Symbol string          `json:"symbol"`
+	Price  decimal.Decimal `json:"price"`
+}
+
+type binancePremiumIndex struct {
+	Symbol    string          `json:"symbol"`
+	MarkPrice decimal.Decimal `json:"markPrice"`
+}
+
+type binanceError struct {
+	Code int    `json:"code"`
+	Msg  string `json:"msg"`
+}
+
+func (f *binancePriceFeed) PullPrice(ctx context.Context) (*PriceData, error) {
+	query := "?symbol=" + url.QueryEscape(f.symbol)
+
+	var price decimal.Decimal
+	switch f.market {
+	case BinanceMarketFutures:
+		var index binancePremiumIndex
+		if err := f.getJSON(ctx, f.baseURL+"/fapi/v1/premiumIndex"+query, nil, &index); err != nil {
+			return nil, f.apiError(err)
+		}
+
+		price = index.MarkPrice
+	default:
+		var ticker binanceSpotPrice
+		if err := f.getJSON(ctx, f.baseURL+"/api/v3/ticker/price"+query, nil, &ticker); err != nil {
+			return nil, f.apiError(err)
+		}
+
+		price = ticker.Price
+	}
+
+	return f.priceData(price)
+}
+
+// apiError surfaces the {"code", "msg"} error of non-2xx Binance responses, e.g. for invalid symbols.
+func (f *binancePriceFeed) apiError(err error) error {
+	var statusErr *httpStatusError
+	if !errors.As(err, &statusErr) {
+		return err
+	}
+
+	var apiErr binanceError
+	if json.Unmarshal([]byte(statusErr.Body), &apiErr) != nil || apiErr.Code == 0 {
+		return err
+	}
+
+	return errors.Errorf("Binance API error %d for symbol %s: %s", apiErr.Code, f.symbol, apiErr.Msg)
+}
@@ -0,0 +1,105 @@
+package oracle
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/shopspring/decimal"
+)
+
+const (
+	bitfinexBaseURL = "https://api-pub.bitfinex.com"
+
+	// bitfinexLastPriceIdx is the index of LAST_PRICE in the trading pair ticker array
+	bitfinexLastPriceIdx = 6
+)
+
+var _ PricePuller = &bitfinexPriceFeed{}
+
+// bitfinexPriceFeed pulls the last price of a trading pair from Bitfinex public ticker.
+type bitfinexPriceFeed struct {
+	*restPriceFeed
+
+	baseURL string
+}
+
+// NewBitfinexPriceFeed returns price puller for Bitfinex trading pairs. Bitfinex symbols are uppercase
+// and prefixed with t (e.g. tBTCUSD), by default derived from the ticker, unless set by the symbol config field.
+// The t prefix is added to the configured symbol, if missing.
+func NewBitfinexPriceFeed(cfg *FeedConfig) (PricePuller, error) {
+	defaultSymbol := strings.ToUpper(strings.ReplaceAll(cfg.Ticker, "/", ""))
+
+	restFeed, err := newRestPriceFeed(FeedProviderBitfinex, cfg, defaultSymbol)
+	if err != nil {
+		return nil, err
+	}
+
+	if !strings.HasPrefix(restFeed.symbol, "t") {
+		restFeed.symbol = "t" + strings.ToUpper(restFeed.symbol)
+	}
+
+	return &bitfinexPriceFeed{
+		restPriceFeed: restFeed,
+		baseURL:       bitfinexBaseURL,
+	}, nil
+}
+
+func (f *bitfinexPriceFeed) PullPrice(ctx context.Context) (*PriceData, error) {
+	u := f.baseURL + "/v2/ticker/" + url.PathEscape(f.symbol)
+
+	var ticker []json.RawMessage
+	if err := f.getJSON(ctx, u, nil, &ticker); err != nil {
+		var statusErr *httpStatusError
+		if errors.As(err, &statusErr) {
+			if apiErr := parseBitfinexError([]byte(statusErr.Body)); apiErr != nil {
+				return nil, errors.Wrapf(apiErr, "failed to get ticker %s", f.symbol)
+			}
+		}
+
+		return nil, err
+	}
+
+	if apiErr := parseBitfinexErrorArray(ticker); apiErr != nil {
+		return nil, errors.Wrapf(apiErr, "failed to get ticker %s", f.symbol)
+	}
+
+	if len(ticker) <= bitfinexLastPriceIdx {
+		return nil, errors.Errorf("unexpected ticker array of length %d for symbol %s", len(ticker), f.symbol)
+	}
+
+	var price decimal.Decimal
+	if err := json.Unmarshal(ticker[bitfinexLastPriceIdx], &price); err != nil {
+		return nil, errors.Wrapf(err, "failed to parse last price: %s", ticker[bitfinexLastPriceIdx])
+	}
+
+	return f.priceData(price)
+}
+
+// parseBitfinexError returns the API error of the ["error", code, msg] shaped body, or nil if the body has other shape.
+func parseBitfinexError(body []byte) error {
+	var arr []json.RawMessage
+	if err := json.Unmarshal(body, &arr); err != nil {
+		return nil
+	}
+
+	return parseBitfinexErrorArray(arr)
+}
+
+func parseBitfinexErrorArray(arr []json.RawMessage) error {
+	if len(arr) != 3 {
+		return nil
+	}
+
+	var kind string
+	if err := json.Unmarshal(arr[0], &kind); err != nil || kind != "error" {
+		return nil
+	}
+
+	var msg string
+	_ = json.Unmarshal(arr[2], &msg)
+
+	return errors.Errorf("Bitfinex API error %s: %s", arr[1], msg)
+}
@@ -0,0 +1,46 @@
+package oracle
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"github.com/shopspring/decimal"
+)
+
+var _ PricePuller = &constantPriceFeed{}
+
+// constantPriceFeed returns a fixed price on every pull, e.g. to relay a hard peg of a stablecoin
+// without an upstream source, or to validate the submission path in staging.
+type constantPriceFeed struct {
+	*restPriceFeed
+
+	value decimal.Decimal
+}
+
+// NewConstantPriceFeed returns price puller for a fixed price set by the required value config field.
+func NewConstantPriceFeed(cfg *FeedConfig) (PricePuller, error) {
+	restFeed, err := newRestPriceFeed(FeedProviderConstant, cfg, cfg.Ticker)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(cfg.Value) == 0 {
+		return nil, errors.Errorf("value must be set for %s provider", FeedProviderConstant)
+	}
+
+	value, err := decimal.NewFromString(cfg.Value)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to parse value: %s", cfg.Value)
+	} else if err := validatePrice(value, restFeed.oracleType); err != nil {
+		return nil, err
+	}
+
+	return &constantPriceFeed{
+		restPriceFeed: restFeed,
+		value:         value,
+	}, nil
+}
+
+func (f *constantPriceFeed) PullPrice(_ context.Context) (*PriceData, error) {
+	return f.priceData(f.value)
+}
@@ -0,0 +1,154 @@
+package oracle
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/InjectiveLabs/metrics"
+	log "github.com/InjectiveLabs/suplog"
+	"github.com/pkg/errors"
+)
+
+var _ PricePuller = &conversionPriceFeed{}
+
+// conversionPriceFeed converts the price of a base feed into another quote currency by multiplying it
+// by the price of a peg feed, e.g. BTC/USD from BTC/USDT and USDT/USD.
+type conversionPriceFeed struct {
+	*restPriceFeed
+
+	base PricePuller
+	peg  PricePuller
+}
+
+// NewConversionPriceFeed returns price puller for a feed converting the price of its base source with
+// the price of its peg source, both declared as nested feed configs. The base source inherits the ticker
+// and oracle type of the feed. The pull is skipped if the peg price is unavailable, or older than
+// maxPriceAge of the feed. Stork sources are not supported.
+func NewConversionPriceFeed(cfg *FeedConfig) (PricePuller, error) {
+	restFeed, err := newRestPriceFeed(FeedProviderConversion, cfg, cfg.Ticker)
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.Base == nil || cfg.Peg == nil {
+		return nil, errors.Errorf("base and peg must be set for %s provider", FeedProviderConversion)
+	}
+
+	base, err := newConversionSource("base", cfg.Base, cfg.Ticker, cfg.OracleType)
+	if err != nil {
+		return nil, err
+	} else if base.OracleType() != restFeed.oracleType {
+		return nil, errors.Errorf("base: oracle type %s differs from the feed oracle type %s", base.OracleType(), restFeed.oracleType)
+	}
+
+	peg, err := newConversionSource("peg", cfg.Peg, cfg.Ticker, "")
+	if err != nil {
+		return nil, err
+	}
+
+	return &conversionPriceFeed{
+		restPriceFeed: restFeed,
+		base:          base,
+		peg:           peg,
+	}, nil
+}
+
+func newConversionSource(name string, sourceCfg *FeedConfig, ticker, oracleType string) (PricePuller, error) {
+	if FeedProvider(sourceCfg.ProviderName) == FeedProviderStork {
+		return nil, errors.Errorf("%s: %s provider is not supported as a conversion source", name, sourceCfg.ProviderName)
+	}
+
+	cfg := *sourceCfg
+	if len(cfg.Ticker) == 0 {
+		cfg.Ticker = ticker
+	}
+
+	if len(cfg.OracleType) == 0 {
+		cfg.OracleType = oracleType
+	}
+
+	if err := validateFeedConfig(&cfg); err != nil {
+		return nil, errors.Wrap(err, name)
+	}
+
+	source, err := NewPricePuller(&cfg, nil)
+	if err != nil {
+		return nil, errors.Wrapf(err, "%s: failed to init %s price feed", name, cfg.ProviderName)
+	}
+
+	return source, nil
+}
+
+// PullPrice pulls the base and peg prices at once, returning their product. It fails if the base price
+// can't be pulled, and returns nil price data if the peg price is unavailable or stale.
+func (f *conversionPriceFeed) PullPrice(ctx context.Context) (*PriceData, error) {
+	var (
+		basePrice, pegPrice *PriceData
+		baseErr, pegErr     error
+		wg                  sync.WaitGroup
+	)
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		pegPrice, pegErr = f.peg.PullPrice(ctx)
+	}()
+
+	basePrice, baseErr = f.base.PullPrice(ctx)
+	wg.Wait()
+
+	if baseErr != nil {
+		return nil, errors.Wrapf(baseErr, "base (%s)", f.base.ProviderName())
+	} else if basePrice == nil {
+		return nil, nil
+	}
+
+	tags := f.svcTags.With("ticker", f.ticker)
+	pegLog := f.logger.WithFields(log.Fields{
+		"ticker": f.ticker,
+		"peg":    f.peg.ProviderName(),
+	})
+
+	if pegErr != nil || pegPrice == nil {
+		metrics.CustomReport(func(s metrics.Statter, tagSpec []string) {
+			s.Count("price_oracle.conversion.peg_unavailable.size", 1, tagSpec, 1)
+		}, tags)
+
+		pegLog.WithError(pegErr).Warningln("peg price is unavailable, skipping conversion")
+		return nil, nil
+	}
+
+	if f.maxPriceAge > 0 {
+		if age := time.Since(pegPrice.Timestamp); age > f.maxPriceAge {
+			metrics.CustomReport(func(s metrics.Statter, tagSpec []string) {
+				s.Count("price_oracle.conversion.peg_stale.size", 1, tagSpec, 1)
+			}, tags)
+
+			pegLog.WithField("age", age.Truncate(time.Second)).Warningln("peg price is stale, skipping conversion")
+			return nil, nil
+		}
+	}
+
+	priceData, err := f.priceData(basePrice.Price.Mul(pegPrice.Price))
+	if err != nil {
+		return nil, err
+	}
+
+	// the converted price is as old as the older of its inputs
+	priceData.Timestamp = basePrice.Timestamp
+	if pegPrice.Timestamp.Before(priceData.Timestamp) {
+		priceData.Timestamp = pegPrice.Timestamp
+	}
+
+	return priceData, nil
+}
+
+// Close stops streaming sources.
+func (f *conversionPriceFeed) Close() {
+	for _, source := range []PricePuller{f.base, f.peg} {
+		if closer, ok := source.(interface{ Close() }); ok {
+			closer.Close()
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package oracle
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestConversionPriceFeed(t *testing.T) {
+	cfg, err := ParseFeedConfig([]byte(`
+provider = "conversion"
+ticker = "BTC/USD"
+pullInterval = "10s"
+maxPriceAge = "1m"
+
+[base]
+provider = "btc_usdt"
+observationSource = 'a [type=memo value="64000"]; b [type=multiply times=1]; a -> b'
+
+[peg]
+provider = "usdt_usd"
+ticker = "USDT/USD"
+observationSource = 'a [type=memo value="0.999"]; b [type=multiply times=1]; a -> b'
+`), FeedConfigFormatTOML)
+	if err != nil {
+		t.Fatalf("failed to parse config: %v", err)
+	}
+
+	feed, err := NewPricePuller(cfg, nil)
+	if err != nil {
+		t.Fatalf("failed to init feed: %v", err)
+	}
+
+	priceData, err := feed.PullPrice(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if priceData.Price.String() != "63936" {
+		t.Errorf("expected converted price 63936, got %s", priceData.Price)
+	}
+
+	if priceData.Ticker != "BTC/USD" || priceData.ProviderName != "conversion" {
+		t.Errorf("expected price data of the conversion feed, got %s from %s", priceData.Ticker, priceData.ProviderName)
+	}
+
+	// unavailable and stale pegs skip the pull
+	for name, pegSource := range map[string]string{
+		"unavailable peg": `a [type=http method=GET url="http://127.0.0.1:1/price"]; b [type=jsonparse path="price"]; a -> b`,
+		"stale peg": fmt.Sprintf(`result [type=merge left=<{"price": "0.999", "timestamp": %d}> right=<{"source": "test"}>]`,
+			time.Now().Add(-time.Hour).Unix()),
+	} {
+		cfg.Peg.ObservationSource = pegSource
+		if feed, err = NewPricePuller(cfg, nil); err != nil {
+			t.Fatalf("%s: failed to init feed: %v", name, err)
+		}
+
+		if priceData, err := feed.PullPrice(context.Background()); err != nil || priceData != nil {
+			t.Errorf("%s: expected the pull to be skipped, got %+v, %v", name, priceData, err)
+		}
+	}
+
+	// a failing base fails the pull
+	cfg.Base.ObservationSource = `a [type=http method=GET url="http://127.0.0.1:1/price"]; b [type=jsonparse path="price"]; a -> b`
+	if feed, err = NewPricePuller(cfg, nil); err != nil {
+		t.Fatalf("failed to init feed: %v", err)
+	}
+
+	if _, err := feed.PullPrice(context.Background()); err == nil {
+		t.Errorf("expected error when the base source fails")
+	}
+}
+
+func TestNewConversionPriceFeedValidation(t *testing.T) {
+	memoSource := &FeedConfig{ProviderName: "test", ObservationSource: `price [type=memo value="1"]`}
+
+	for name, cfg := range map[string]*FeedConfig{
+		"no peg":     {ProviderName: "conversion", Ticker: "BTC/USD", Base: memoSource},
+		"stork base": {ProviderName: "conversion", Ticker: "BTC/USD", Base: &FeedConfig{ProviderName: "stork"}, Peg: memoSource},
+		"oracle type mismatch": {ProviderName: "conversion", Ticker: "BTC/USD", Peg: memoSource, Base: &FeedConfig{
+			ProviderName: "test", ObservationSource: `price [type=memo value="1"]`, OracleType: "Provider",
+		}},
+	} {
+		if _, err := NewConversionPriceFeed(cfg); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
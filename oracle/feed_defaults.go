@@ -0,0 +1,55 @@
+package oracle
+
+import (
+	"encoding/json"
+	"reflect"
+
+	"github.com/pkg/errors"
+)
+
+// FeedDefaultsFile is the file in the feeds dir declaring fields shared by all feeds, e.g. provider or pull interval.
+const FeedDefaultsFile = "defaults.toml"
+
+// ParseFeedDefaults parses feed defaults in the given format. Defaults may set any feed config field but
+// the ticker, and they are not validated on their own, since they are not a complete feed.
+func ParseFeedDefaults(body []byte, format string) (*FeedConfig, error) {
+	var defaults FeedConfig
+	if err := unmarshalFeedConfig(body, format, &defaults); err != nil {
+		return nil, err
+	}
+
+	if len(defaults.Ticker) > 0 {
+		return nil, errors.New("feed defaults must not set the ticker")
+	}
+
+	return &defaults, nil
+}
+
+// applyDefaults sets fields of the feed config left empty to the ones of defaults, so fields set by the feed
+// take precedence. Lists and nested feed configs are taken as a whole, never merged with the feed ones.
+func (c *FeedConfig) applyDefaults(defaults *FeedConfig) error {
+	if defaults == nil {
+		return nil
+	}
+
+	// deep copy, so feeds don't share nested configs of defaults
+	body, err := json.Marshal(defaults)
+	if err != nil {
+		return errors.Wrap(err, "failed to copy feed defaults")
+	}
+
+	var copied FeedConfig
+	if err := json.Unmarshal(body, &copied); err != nil {
+		return errors.Wrap(err, "failed to copy feed defaults")
+	}
+
+	cfg := reflect.ValueOf(c).Elem()
+	defaultCfg := reflect.ValueOf(&copied).Elem()
+	for i := 0; i < cfg.NumField(); i++ {
+		if field := cfg.Field(i); field.IsZero() {
+			field.Set(defaultCfg.Field(i))
+		}
+	}
+
+	return nil
+}
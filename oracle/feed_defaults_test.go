@@ -0,0 +1,80 @@
+package oracle
+
+import (
+	"testing"
+)
+
+func TestParseFeedConfigsWithDefaults(t *testing.T) {
+	defaults, err := ParseFeedDefaults([]byte(`
+provider = "binance"
+pullInterval = "30s"
+minPrice = "1"
+`), FeedConfigFormatTOML)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	feedCfgs, err := ParseFeedConfigsWithDefaults([]byte(`
+[[feeds]]
+ticker = "INJ/USDT"
+
+[[feeds]]
+ticker = "BTC/USDT"
+pullInterval = "1m"
+provider = "kucoin"
+`), FeedConfigFormatTOML, defaults)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := []struct {
+		provider     string
+		pullInterval string
+	}{
+		{provider: "binance", pullInterval: "30s"},
+		{provider: "kucoin", pullInterval: "1m"},
+	}
+
+	for i, feedCfg := range feedCfgs {
+		if feedCfg.ProviderName != expected[i].provider {
+			t.Errorf("feed #%d: expected provider %s, got %s", i, expected[i].provider, feedCfg.ProviderName)
+		}
+		if feedCfg.PullInterval != expected[i].pullInterval {
+			t.Errorf("feed #%d: expected pull interval %s, got %s", i, expected[i].pullInterval, feedCfg.PullInterval)
+		}
+		if feedCfg.MinPrice != "1" {
+			t.Errorf("feed #%d: expected default min price 1, got %q", i, feedCfg.MinPrice)
+		}
+	}
+
+	// the merged config is validated
+	_, err = ParseFeedConfigsWithDefaults([]byte(`
+ticker = "INJ/USDT"
+maxPrice = "0.5"
+`), FeedConfigFormatTOML, defaults)
+	if err == nil {
+		t.Errorf("expected error for min price of defaults above max price of the feed")
+	}
+
+	if _, err := ParseFeedDefaults([]byte(`ticker = "INJ/USDT"`), FeedConfigFormatTOML); err == nil {
+		t.Errorf("expected error for ticker in defaults")
+	}
+}
+
+func TestFeedConfigApplyDefaultsCopiesNestedConfigs(t *testing.T) {
+	defaults := &FeedConfig{
+		Sources: []*FeedConfig{{ProviderName: "binance"}},
+	}
+
+	first, second := &FeedConfig{}, &FeedConfig{}
+	for _, cfg := range []*FeedConfig{first, second} {
+		if err := cfg.applyDefaults(defaults); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	first.Sources[0].Ticker = "INJ/USDT"
+	if second.Sources[0].Ticker != "" || defaults.Sources[0].Ticker != "" {
+		t.Errorf("expected nested configs of defaults not to be shared between feeds")
+	}
+}
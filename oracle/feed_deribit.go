@@ -0,0 +1,83 @@
+package oracle
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/shopspring/decimal"
+)
+
+const deribitBaseURL = "https://www.deribit.com"
+
+var _ PricePuller = &deribitPriceFeed{}
+
+// deribitPriceFeed pulls a crypto index price, used by Deribit derivatives, from Deribit public API.
+type deribitPriceFeed struct {
+	*restPriceFeed
+
+	baseURL string
+}
+
+// NewDeribitPriceFeed returns price puller for Deribit index prices. The symbol config field is used
+// as the index name (e.g. btc_usd), by default derived from the ticker.
+func NewDeribitPriceFeed(cfg *FeedConfig) (PricePuller, error) {
+	defaultSymbol := strings.ToLower(strings.ReplaceAll(cfg.Ticker, "/", "_"))
+
+	restFeed, err := newRestPriceFeed(FeedProviderDeribit, cfg, defaultSymbol)
+	if err != nil {
+		return nil, err
+	}
+
+	return &deribitPriceFeed{
+		restPriceFeed: restFeed,
+		baseURL:       deribitBaseURL,
+	}, nil
+}
+
+type deribitResponse struct {
+	Result *struct {
+		IndexPrice decimal.Decimal `json:"index_price"`
+	} `json:"result"`
+	Error *deribitError `json:"error"`
+}
+
+type deribitError struct {
+	Code    int             `json:"code"`
+	Message string          `json:"message"`
+	Data    json.RawMessage `json:"data,omitempty"`
+}
+
+func (e *deribitError) Error() string {
+	if len(e.Data) > 0 {
+		return fmt.Sprintf("Deribit API error %d: %s %s", e.Code, e.Message, e.Data)
+	}
+
+	return fmt.Sprintf("Deribit API error %d: %s", e.Code, e.Message)
+}
+
+func (f *deribitPriceFeed) PullPrice(ctx context.Context) (*PriceData, error) {
+	u := f.baseURL + "/api/v2/public/get_index_price?index_name=" + url.QueryEscape(f.symbol)
+
+	var resp deribitResponse
+	if err := f.getJSON(ctx, u, nil, &resp); err != nil {
+		// JSON-RPC errors come with a non-2xx status, but still carry the error object
+		var statusErr *httpStatusError
+		if !errors.As(err, &statusErr) || json.Unmarshal([]byte(statusErr.Body), &resp) != nil || resp.Error == nil {
+			return nil, err
+		}
+	}
+
+	if resp.Error != nil {
+		return nil, errors.Wrapf(resp.Error, "failed to get index price %s", f.symbol)
+	}
+
+	if resp.Result == nil {
+		return nil, errors.Errorf("no result returned for index %s", f.symbol)
+	}
+
+	return f.priceData(resp.Result.IndexPrice)
+}
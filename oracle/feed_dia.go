@@ -0,0 +1,66 @@
+package oracle
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/shopspring/decimal"
+)
+
+const diaBaseURL = "https://api.diadata.org"
+
+var _ PricePuller = &diaPriceFeed{}
+
+// diaPriceFeed pulls the asset quotation of a token from DIA, which keys assets by blockchain and contract address.
+type diaPriceFeed struct {
+	*restPriceFeed
+
+	blockchain string
+	address    string
+	baseURL    string
+}
+
+// NewDIAPriceFeed returns price puller for DIA asset quotations. The symbol config field is required
+// and must be in blockchain:address format (e.g. Ethereum:0x0000000000000000000000000000000000000000).
+func NewDIAPriceFeed(cfg *FeedConfig) (PricePuller, error) {
+	restFeed, err := newRestPriceFeed(FeedProviderDIA, cfg, "")
+	if err != nil {
+		return nil, err
+	}
+
+	blockchain, address, ok := strings.Cut(restFeed.symbol, ":")
+	if !ok || len(blockchain) == 0 || len(address) == 0 {
+		return nil, errors.Errorf("symbol must be in blockchain:address format for %s provider, got %s", FeedProviderDIA, restFeed.symbol)
+	}
+
+	return &diaPriceFeed{
+		restPriceFeed: restFeed,
+		blockchain:    blockchain,
+		address:       address,
+		baseURL:       diaBaseURL,
+	}, nil
+}
+
+type diaQuotation struct {
+	Symbol string          `json:"Symbol"`
+	Price  decimal.Decimal `json:"Price"`
+}
+
+func (f *diaPriceFeed) PullPrice(ctx context.Context) (*PriceData, error) {
+	u := f.baseURL + "/v1/assetQuotation/" + url.PathEscape(f.blockchain) + "/" + url.PathEscape(f.address)
+
+	var quotation diaQuotation
+	if err := f.getJSON(ctx, u, nil, &quotation); err != nil {
+		var statusErr *httpStatusError
+		if errors.As(err, &statusErr) && statusErr.StatusCode == http.StatusNotFound {
+			return nil, errors.Errorf("asset %s is unknown to DIA", f.symbol)
+		}
+
+		return nil, err
+	}
+
+	return f.priceData(quotation.Price)
+}
@@ -4,7 +4,12 @@ import (
 	"context"
 	"crypto/sha256"
 	"encoding/hex"
+	"encoding/json"
 	"fmt"
+	"math"
+	"path/filepath"
+	"reflect"
+	"strings"
 	"sync/atomic"
 	"time"
 
@@ -12,6 +17,7 @@ import (
 	"github.com/pelletier/go-toml/v2"
 	"github.com/pkg/errors"
 	"github.com/shopspring/decimal"
+	"gopkg.in/yaml.v3"
 
 	"github.com/InjectiveLabs/metrics"
 	oracletypes "github.com/InjectiveLabs/sdk-go/chain/oracle/types"
@@ -19,21 +25,350 @@ import (
 	"github.com/InjectiveLabs/injective-price-oracle/pipeline"
 )
 
+// Supported formats of feed config files.
+const (
+	FeedConfigFormatTOML = "toml"
+	FeedConfigFormatYAML = "yaml"
+	FeedConfigFormatJSON = "json"
+)
+
+// FeedConfigFormat returns the feed config format matching the file extension,
+// or an empty string if the extension is not supported.
+func FeedConfigFormat(path string) string {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".toml":
+		return FeedConfigFormatTOML
+	case ".yaml", ".yml":
+		return FeedConfigFormatYAML
+	case ".json":
+		return FeedConfigFormatJSON
+	default:
+		return ""
+	}
+}
+
+// ParseDynamicFeedConfig parses a feed config in TOML format.
 func ParseDynamicFeedConfig(body []byte) (*FeedConfig, error) {
+	return ParseFeedConfig(body, FeedConfigFormatTOML)
+}
+
+// ParseFeedConfig parses a feed config in the given format, see FeedConfigFormat.
+func ParseFeedConfig(body []byte, format string) (*FeedConfig, error) {
 	var config FeedConfig
-	if err := toml.Unmarshal(body, &config); err != nil {
-		err = errors.Wrap(err, "failed to unmarshal TOML config")
+	if err := unmarshalFeedConfig(body, format, &config); err != nil {
+		return nil, err
+	}
+
+	if err := validateFeedConfig(&config); err != nil {
+		return nil, err
+	}
+
+	return &config, nil
+}
+
+// ParseFeedConfigs parses a feed config file in the given format. The file either declares a single feed
+// at the top level, or many feeds as a list under the "feeds" key ([[feeds]] array of tables in TOML).
+func ParseFeedConfigs(body []byte, format string) ([]*FeedConfig, error) {
+	return ParseFeedConfigsWithDefaults(body, format, nil)
+}
+
+// ParseFeedConfigsWithDefaults parses a feed config file same as ParseFeedConfigs, merging defaults into
+// every feed before validation. Fields set by a feed take precedence over the defaults.
+func ParseFeedConfigsWithDefaults(body []byte, format string, defaults *FeedConfig) ([]*FeedConfig, error) {
+	var file struct {
+		Feeds []*FeedConfig `toml:"feeds" yaml:"feeds" json:"feeds"`
+	}
+
+	if err := unmarshalFeedConfig(body, format, &file); err != nil {
+		return nil, err
+	}
+
+	if len(file.Feeds) == 0 {
+		var config FeedConfig
+		if err := unmarshalFeedConfig(body, format, &config); err != nil {
+			return nil, err
+		}
+
+		if err := config.applyDefaults(defaults); err != nil {
+			return nil, err
+		}
+
+		if err := validateFeedConfig(&config); err != nil {
+			return nil, err
+		}
+
+		return []*FeedConfig{&config}, nil
+	}
+
+	var topLevel FeedConfig
+	if err := unmarshalFeedConfig(body, format, &topLevel); err != nil {
 		return nil, err
+	} else if !reflect.DeepEqual(topLevel, FeedConfig{}) {
+		return nil, errors.New("feed config must declare either a single feed at the top level, or a list of feeds, not both")
 	}
 
+	tickers := make(map[string]int, len(file.Feeds))
+	for i, config := range file.Feeds {
+		if prev, ok := tickers[config.Ticker]; ok {
+			return nil, errors.Errorf("duplicate ticker %s in feeds #%d and #%d", config.Ticker, prev, i)
+		}
+		tickers[config.Ticker] = i
+
+		if err := config.applyDefaults(defaults); err != nil {
+			return nil, err
+		}
+
+		if err := validateFeedConfig(config); err != nil {
+			return nil, errors.Wrapf(err, "invalid feed #%d (%s)", i, config.Ticker)
+		}
+	}
+
+	return file.Feeds, nil
+}
+
+func unmarshalFeedConfig(body []byte, format string, v interface{}) (err error) {
+	switch format {
+	case FeedConfigFormatTOML:
+		err = toml.Unmarshal(body, v)
+	case FeedConfigFormatYAML:
+		err = yaml.Unmarshal(body, v)
+	case FeedConfigFormatJSON:
+		err = json.Unmarshal(body, v)
+	default:
+		return errors.Errorf("unsupported feed config format: %s", format)
+	}
+
+	if err != nil {
+		err = errors.Wrapf(err, "failed to unmarshal %s config", strings.ToUpper(format))
+		return err
+	}
+
+	return nil
+}
+
+func validateFeedConfig(config *FeedConfig) error {
 	// validate the observation source graph
 	_, err := pipeline.Parse(config.ObservationSource)
 	if err != nil {
 		err = errors.Wrap(err, "observation source pipeline parse error")
-		return nil, err
+		return err
 	}
 
-	return &config, nil
+	if _, err := config.priceBounds(); err != nil {
+		return err
+	}
+
+	if err := config.validateReferenceSource(); err != nil {
+		return err
+	}
+
+	if err := config.validateCrossCheck(); err != nil {
+		return err
+	}
+
+	if _, err := config.submitPrecision(); err != nil {
+		return err
+	}
+
+	if _, err := config.priceScale(); err != nil {
+		return err
+	}
+
+	if _, err := config.minSubmitInterval(); err != nil {
+		return err
+	}
+
+	if _, err := config.oracleTypes(); err != nil {
+		return err
+	}
+
+	if _, err := config.retryPolicy(); err != nil {
+		return err
+	}
+
+	if err := config.validateOutputs(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// priceBounds is an optional band of prices accepted for a feed, nil means no bound.
+type priceBounds struct {
+	min *decimal.Decimal
+	max *decimal.Decimal
+}
+
+func (b priceBounds) contains(price decimal.Decimal) bool {
+	if b.min != nil && price.LessThan(*b.min) {
+		return false
+	} else if b.max != nil && price.GreaterThan(*b.max) {
+		return false
+	}
+
+	return true
+}
+
+func (c *FeedConfig) priceBounds() (bounds priceBounds, err error) {
+	if len(c.MinPrice) > 0 {
+		min, err := decimal.NewFromString(c.MinPrice)
+		if err != nil {
+			return bounds, errors.Wrapf(err, "failed to parse min price: %s", c.MinPrice)
+		}
+		bounds.min = &min
+	}
+
+	if len(c.MaxPrice) > 0 {
+		max, err := decimal.NewFromString(c.MaxPrice)
+		if err != nil {
+			return bounds, errors.Wrapf(err, "failed to parse max price: %s", c.MaxPrice)
+		}
+		bounds.max = &max
+	}
+
+	if bounds.min != nil && bounds.max != nil && bounds.min.GreaterThan(*bounds.max) {
+		return bounds, errors.Errorf("min price %s is greater than max price %s", c.MinPrice, c.MaxPrice)
+	}
+
+	return bounds, nil
+}
+
+// maxSubmitPrecision is the number of decimal places of on-chain prices (LegacyDec).
+const maxSubmitPrecision = 18
+
+func (c *FeedConfig) submitPrecision() (int32, error) {
+	if c.SubmitPrecision == nil {
+		return maxSubmitPrecision, nil
+	}
+
+	if *c.SubmitPrecision < 0 || *c.SubmitPrecision > maxSubmitPrecision {
+		return 0, errors.Errorf("submit precision must be between 0 and %d: %d", maxSubmitPrecision, *c.SubmitPrecision)
+	}
+
+	return int32(*c.SubmitPrecision), nil
+}
+
+func (c *FeedConfig) minSubmitInterval() (time.Duration, error) {
+	if len(c.MinSubmitInterval) == 0 {
+		return 0, nil
+	}
+
+	interval, err := time.ParseDuration(c.MinSubmitInterval)
+	if err != nil || interval <= 0 {
+		return 0, errors.Errorf("failed to parse min submit interval: %s (expected positive duration, e.g. 5m)", c.MinSubmitInterval)
+	}
+
+	return interval, nil
+}
+
+// retryPolicy is how many times, and how long apart, a failed pull of a feed is retried within its pull interval.
+type retryPolicy struct {
+	retries int
+	backoff time.Duration
+}
+
+var defaultRetryPolicy = retryPolicy{
+	retries: maxRetriesPerInterval,
+	backoff: defaultRetryBackoff,
+}
+
+func (c *FeedConfig) retryPolicy() (retryPolicy, error) {
+	policy := defaultRetryPolicy
+
+	if c.Retries != nil {
+		if *c.Retries < 0 {
+			return policy, errors.Errorf("retries must not be negative, got %d", *c.Retries)
+		}
+		policy.retries = *c.Retries
+	}
+
+	if len(c.RetryBackoff) > 0 {
+		backoff, err := time.ParseDuration(c.RetryBackoff)
+		if err != nil || backoff <= 0 {
+			return policy, errors.Errorf("failed to parse retry backoff: %s (expected positive duration, e.g. 500ms)", c.RetryBackoff)
+		}
+		policy.backoff = backoff
+	}
+
+	return policy, nil
+}
+
+// oracleTypes parses the oracle types of a feed submitted to many oracle types at once,
+// the first one is pulled for. Returns nil for feeds of a single oracle type.
+func (c *FeedConfig) oracleTypes() ([]oracletypes.OracleType, error) {
+	if len(c.OracleTypes) == 0 {
+		return nil, nil
+	} else if len(c.OracleType) > 0 {
+		return nil, errors.New("either oracleType or oracleTypes can be set, not both")
+	} else if FeedProvider(c.ProviderName) == FeedProviderStork {
+		return nil, errors.Errorf("oracleTypes is not supported by %s provider", FeedProviderStork)
+	}
+
+	oracleTypes := make([]oracletypes.OracleType, 0, len(c.OracleTypes))
+	seen := make(map[oracletypes.OracleType]struct{}, len(c.OracleTypes))
+	for _, name := range c.OracleTypes {
+		value, ok := oracletypes.OracleType_value[name]
+		if !ok {
+			return nil, errors.Errorf("oracle type does not exist: %s", name)
+		}
+
+		oracleType := oracletypes.OracleType(value)
+		if oracleType == oracletypes.OracleType_Stork {
+			// Stork prices are publisher signed asset pairs, not a value to share with other oracle types
+			return nil, errors.Errorf("oracle type %s cannot be combined with other oracle types", name)
+		} else if _, ok := seen[oracleType]; ok {
+			return nil, errors.Errorf("duplicate oracle type: %s", name)
+		}
+
+		seen[oracleType] = struct{}{}
+		oracleTypes = append(oracleTypes, oracleType)
+	}
+
+	return oracleTypes, nil
+}
+
+// ParseDefaultPullIntervals parses "provider=interval" entries of default pull intervals, keyed by the
+// provider name of feed configs.
+func ParseDefaultPullIntervals(entries []string) (map[string]string, error) {
+	intervals := make(map[string]string, len(entries))
+	for _, entry := range entries {
+		provider, interval, ok := strings.Cut(entry, "=")
+		provider, interval = strings.TrimSpace(provider), strings.TrimSpace(interval)
+		if !ok || len(provider) == 0 || len(interval) == 0 {
+			return nil, errors.Errorf("malformed default pull interval %q (expected format: provider=60s)", entry)
+		}
+
+		if d, err := time.ParseDuration(interval); err != nil || d < time.Second {
+			return nil, errors.Errorf("invalid default pull interval of %s provider: %s (minimum interval = 1s)", provider, interval)
+		}
+
+		intervals[provider] = interval
+	}
+
+	return intervals, nil
+}
+
+// SetDefaultRegion sets the default region of the feed provider, if the feed config doesn't declare its own region.
+func (c *FeedConfig) SetDefaultRegion(defaults map[string]string) {
+	if len(c.Region) > 0 {
+		return
+	}
+
+	if region, ok := defaults[c.ProviderName]; ok {
+		c.Region = region
+	}
+}
+
+// SetDefaultPullInterval sets the default pull interval of the feed provider, if the feed config doesn't
+// declare its own pull interval.
+func (c *FeedConfig) SetDefaultPullInterval(defaults map[string]string) {
+	if len(c.PullInterval) > 0 {
+		return
+	}
+
+	if interval, ok := defaults[c.ProviderName]; ok {
+		c.PullInterval = interval
+	}
 }
 
 func (c *FeedConfig) Hash() string {
@@ -58,7 +393,7 @@ func NewDynamicPriceFeed(cfg *FeedConfig) (PricePuller, error) {
 		}
 
 		if interval < 1*time.Second {
-			err = errors.Wrapf(err, "failed to parse pull interval: %s (minimum interval = 1s)", cfg.PullInterval)
+			err = errors.Errorf("failed to parse pull interval: %s (minimum interval = 1s)", cfg.PullInterval)
 			return nil, err
 		}
 
@@ -77,12 +412,24 @@ func NewDynamicPriceFeed(cfg *FeedConfig) (PricePuller, error) {
 		oracleType = oracletypes.OracleType(tmpType)
 	}
 
+	var maxPriceAge time.Duration
+	if len(cfg.MaxPriceAge) > 0 {
+		age, err := time.ParseDuration(cfg.MaxPriceAge)
+		if err != nil || age <= 0 {
+			return nil, errors.Errorf("failed to parse max price age: %s (expected positive duration, e.g. 5m)", cfg.MaxPriceAge)
+		}
+
+		maxPriceAge = age
+	}
+
 	feed := &dynamicPriceFeed{
 		ticker:       cfg.Ticker,
 		providerName: cfg.ProviderName,
 		interval:     pullInterval,
 		dotDagSource: cfg.ObservationSource,
 		oracleType:   oracleType,
+		maxPriceAge:  maxPriceAge,
+		outputs:      cfg.Outputs,
 
 		logger: log.WithFields(log.Fields{
 			"svc":      "oracle",
@@ -103,6 +450,10 @@ type dynamicPriceFeed struct {
 	providerName string
 	interval     time.Duration
 	dotDagSource string
+	maxPriceAge  time.Duration
+
+	// outputs maps names of the pipeline result map to the tickers of their prices, empty for a single price
+	outputs map[string]string
 
 	runNonce int32
 
@@ -147,11 +498,47 @@ func (f *dynamicPriceFeed) PullPrice(ctx context.Context) (
 
 	ts := time.Now()
 
-	runner := pipeline.NewRunner(f.logger)
 	runLogger := f.logger.WithFields(log.Fields{
 		"ticker": f.ticker,
 	})
 
+	value, err := f.runPipeline(ctx, runLogger)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(f.outputs) > 0 {
+		// a pipeline of many outputs yields the price of the feed ticker along with the rest
+		prices, err := f.outputPrices(value)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, priceData := range prices {
+			if string(priceData.Ticker) == f.ticker {
+				runLogger.Infoln("PullPrice (pipeline run) done in", time.Since(ts))
+				return priceData, nil
+			}
+		}
+
+		err = errors.Errorf("no pipeline output is mapped to the feed ticker %s", f.ticker)
+		return nil, feedConfigError(err)
+	}
+
+	priceData, err = f.priceData(f.ticker, value)
+	if err != nil {
+		return nil, err
+	}
+
+	runLogger.Infoln("PullPrice (pipeline run) done in", time.Since(ts))
+
+	return priceData, nil
+}
+
+// runPipeline runs the observation source once and returns the value of its single final result.
+func (f *dynamicPriceFeed) runPipeline(ctx context.Context, runLogger log.Logger) (interface{}, error) {
+	runner := pipeline.NewRunner(f.logger)
+
 	jobID := atomic.AddInt32(&f.runNonce, 1)
 	spec := pipeline.Spec{
 		ID:           jobID,
@@ -166,7 +553,7 @@ func (f *dynamicPriceFeed) PullPrice(ctx context.Context) (
 	run, trrs, err := runner.ExecuteRun(ctx, spec, runVars, runLogger)
 	if err != nil {
 		err = errors.Wrap(err, "failed to execute pipeline run")
-		return nil, err
+		return nil, classifyPipelineErrors(err, []error{err})
 	} else if run.State != pipeline.RunStatusCompleted {
 		if run.HasErrors() {
 			runLogger.Warningf("final run result has non-critical errors: %s", run.AllErrors.ToError())
@@ -174,11 +561,11 @@ func (f *dynamicPriceFeed) PullPrice(ctx context.Context) (
 
 		if run.HasFatalErrors() {
 			err = errors.Errorf("final run result has fatal errors: %s", run.FatalErrors.ToError())
-			return nil, err
+			return nil, classifyPipelineErrors(err, trrs.FinalResult(runLogger).AllErrors)
 		}
 
 		err = errors.Errorf("expected run to be completed, yet got %v", run.State)
-		return nil, err
+		return nil, transientError(err)
 	}
 
 	finalResult := trrs.FinalResult(runLogger)
@@ -188,7 +575,8 @@ func (f *dynamicPriceFeed) PullPrice(ctx context.Context) (
 	}
 
 	if finalResult.HasFatalErrors() {
-		return nil, errors.Errorf("final run result has fatal errors: %v", finalResult.FatalErrors)
+		err = errors.Errorf("final run result has fatal errors: %v", finalResult.FatalErrors)
+		return nil, classifyPipelineErrors(err, finalResult.AllErrors)
 	}
 
 	res, err := finalResult.SingularResult()
@@ -196,30 +584,124 @@ func (f *dynamicPriceFeed) PullPrice(ctx context.Context) (
 		return nil, errors.Wrap(err, "failed to get single result of pipeline run")
 	}
 
-	price, ok := res.Value.(decimal.Decimal)
-	if !ok {
-		if floatPrice, ok := res.Value.(float64); ok {
-			price = decimal.NewFromFloat(floatPrice)
-		} else if someString, ok := res.Value.(string); ok {
-			price, err = decimal.NewFromString(someString)
-		} else {
-			err = errors.New("value is neither decimals, float64 nor string")
+	return res.Value, nil
+}
+
+// priceData converts a pipeline result value into the price data of the ticker.
+func (f *dynamicPriceFeed) priceData(ticker string, value interface{}) (*PriceData, error) {
+	var err error
+	timestamp := time.Now()
+
+	// a pipeline may attach the upstream timestamp by yielding a map with price and timestamp keys
+	if result, ok := value.(map[string]interface{}); ok {
+		if value, ok = result[pipelineResultPriceKey]; !ok {
+			err = errors.Errorf("expected pipeline result map to have the %s key", pipelineResultPriceKey)
+			return nil, feedConfigError(err)
 		}
 
-		if err != nil {
-			err = fmt.Errorf("expected pipeline result as string, decimal.Decimal or float64, but got %T, err: %w", res.Value, err)
-			return nil, err
+		if rawTimestamp, ok := result[pipelineResultTimestampKey]; ok && rawTimestamp != nil {
+			if timestamp, err = parseUpstreamTimestamp(rawTimestamp); err != nil {
+				return nil, errors.Wrap(err, "failed to parse pipeline result timestamp")
+			}
+
+			if f.maxPriceAge > 0 {
+				if age := time.Since(timestamp); age > f.maxPriceAge {
+					return nil, errors.Errorf("price is stale: reported at %s, %s ago (max age %s)",
+						timestamp.UTC().Format(time.RFC3339), age.Truncate(time.Second), f.maxPriceAge)
+				}
+			}
 		}
 	}
 
-	runLogger.Infoln("PullPrice (pipeline run) done in", time.Since(ts))
+	price, err := pipelineResultPrice(value)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = validatePrice(price, f.OracleType()); err != nil {
+		return nil, err
+	}
 
 	return &PriceData{
-		Ticker:       Ticker(f.ticker),
+		Ticker:       Ticker(ticker),
 		ProviderName: f.ProviderName(),
 		Symbol:       f.Symbol(),
 		Price:        price,
-		Timestamp:    time.Now(),
+		Timestamp:    timestamp,
 		OracleType:   f.OracleType(),
 	}, nil
 }
+
+// Keys of a pipeline result map carrying the price along with its upstream timestamp.
+const (
+	pipelineResultPriceKey     = "price"
+	pipelineResultTimestampKey = "timestamp"
+)
+
+// pipelineResultPrice converts a pipeline result value into a price. Big integers and floats, e.g. decoded
+// from uint256 on-chain values, are converted exactly rather than through float64.
+func pipelineResultPrice(value interface{}) (price decimal.Decimal, err error) {
+	switch v := value.(type) {
+	case decimal.Decimal:
+		return v, nil
+	case float64:
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			err = errors.Errorf("pipeline result is not a finite number: %v", v)
+		} else {
+			price = decimal.NewFromFloat(v)
+		}
+	case string:
+		price, err = decimal.NewFromString(v)
+	default:
+		price, err = pipeline.ToDecimal(value)
+	}
+
+	if err != nil {
+		err = fmt.Errorf("expected pipeline result as a number or string, but got %T, err: %w", value, err)
+		return price, err
+	}
+
+	return price, nil
+}
+
+// parseUpstreamTimestamp parses a timestamp reported by the data source, either as unix time in seconds,
+// milliseconds, microseconds or nanoseconds (told apart by magnitude), or as an RFC 3339 string.
+func parseUpstreamTimestamp(value interface{}) (time.Time, error) {
+	if s, ok := value.(string); ok {
+		if t, err := time.Parse(time.RFC3339Nano, s); err == nil {
+			return t, nil
+		}
+	}
+
+	unix, err := pipeline.ToDecimal(value)
+	if err != nil {
+		return time.Time{}, errors.Errorf("expected unix time or RFC 3339 string, got %v (%T)", value, value)
+	} else if !unix.IsPositive() {
+		return time.Time{}, errors.Errorf("expected positive unix time, got %s", unix.String())
+	}
+
+	switch {
+	case unix.GreaterThanOrEqual(decimal.New(1, 18)):
+		return time.Unix(0, unix.IntPart()), nil
+	case unix.GreaterThanOrEqual(decimal.New(1, 15)):
+		return time.UnixMicro(unix.IntPart()), nil
+	case unix.GreaterThanOrEqual(decimal.New(1, 12)):
+		return time.UnixMilli(unix.IntPart()), nil
+	default:
+		return time.Unix(0, unix.Shift(9).IntPart()), nil
+	}
+}
+
+// validatePrice ensures that a price produced by the pipeline can be submitted on-chain.
+// Stork prices are carried in signed asset pairs, so only the rest must be positive.
+func validatePrice(price decimal.Decimal, oracleType oracletypes.OracleType) error {
+	if oracleType == oracletypes.OracleType_Stork {
+		return nil
+	}
+
+	if !price.IsPositive() {
+		return errors.Errorf("expected pipeline result to be a positive price, but got %s", price.String())
+	}
+
+	return nil
+}
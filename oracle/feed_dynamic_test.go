@@ -0,0 +1,486 @@
+package oracle
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/shopspring/decimal"
+)
+
+func TestParseFeedConfig(t *testing.T) {
+	expected := FeedConfig{
+		ProviderName:      "binance_v3",
+		Ticker:            "INJ/USDT",
+		PullInterval:      "1m",
+		ObservationSource: "ticker [type=memo value=\"1.5\"]",
+		OracleType:        "PriceFeed",
+	}
+
+	tests := []struct {
+		name   string
+		path   string
+		config string
+	}{
+		{
+			name: "TOML config",
+			path: "feed.toml",
+			config: `provider = "binance_v3"
+ticker = "INJ/USDT"
+pullInterval = "1m"
+observationSource = 'ticker [type=memo value="1.5"]'
+oracleType = "PriceFeed"
+`,
+		},
+		{
+			name: "YAML config",
+			path: "feed.yaml",
+			config: `provider: binance_v3
+ticker: INJ/USDT
+pullInterval: 1m
+observationSource: 'ticker [type=memo value="1.5"]'
+oracleType: PriceFeed
+`,
+		},
+		{
+			name: "YML config",
+			path: "feed.yml",
+			config: `provider: binance_v3
+ticker: INJ/USDT
+pullInterval: 1m
+observationSource: 'ticker [type=memo value="1.5"]'
+oracleType: PriceFeed
+`,
+		},
+		{
+			name: "JSON config",
+			path: "feed.json",
+			config: `{
+  "provider": "binance_v3",
+  "ticker": "INJ/USDT",
+  "pullInterval": "1m",
+  "observationSource": "ticker [type=memo value=\"1.5\"]",
+  "oracleType": "PriceFeed"
+}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, err := ParseFeedConfig([]byte(tt.config), FeedConfigFormat(tt.path))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if !reflect.DeepEqual(*cfg, expected) {
+				t.Errorf("expected %+v, got %+v", expected, *cfg)
+			}
+		})
+	}
+
+	if _, err := ParseFeedConfig([]byte("{}"), FeedConfigFormat("feed.txt")); err == nil {
+		t.Errorf("expected error for unsupported format")
+	}
+}
+
+func TestParseFeedConfigs(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  string
+		tickers []string
+		wantErr bool
+	}{
+		{
+			name: "Single feed at the top level",
+			config: `provider = "binance_v3"
+ticker = "INJ/USDT"
+observationSource = 'ticker [type=memo value="1.5"]'
+`,
+			tickers: []string{"INJ/USDT"},
+		},
+		{
+			name: "Many feeds",
+			config: `[[feeds]]
+provider = "binance_v3"
+ticker = "INJ/USDT"
+observationSource = 'ticker [type=memo value="1.5"]'
+
+[[feeds]]
+provider = "binance_v3"
+ticker = "ATOM/USDT"
+pullInterval = "30s"
+observationSource = 'ticker [type=memo value="7.5"]'
+`,
+			tickers: []string{"INJ/USDT", "ATOM/USDT"},
+		},
+		{
+			name: "Duplicate tickers",
+			config: `[[feeds]]
+provider = "binance_v3"
+ticker = "INJ/USDT"
+observationSource = 'ticker [type=memo value="1.5"]'
+
+[[feeds]]
+provider = "binance_v3"
+ticker = "INJ/USDT"
+observationSource = 'ticker [type=memo value="7.5"]'
+`,
+			wantErr: true,
+		},
+		{
+			name: "Both top level feed and many feeds",
+			config: `provider = "binance_v3"
+ticker = "INJ/USDT"
+observationSource = 'ticker [type=memo value="1.5"]'
+
+[[feeds]]
+provider = "binance_v3"
+ticker = "ATOM/USDT"
+observationSource = 'ticker [type=memo value="7.5"]'
+`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfgs, err := ParseFeedConfigs([]byte(tt.config), FeedConfigFormatTOML)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected error, got none")
+				}
+				return
+			} else if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if len(cfgs) != len(tt.tickers) {
+				t.Fatalf("expected %d feeds, got %d", len(tt.tickers), len(cfgs))
+			}
+
+			for i, cfg := range cfgs {
+				if cfg.Ticker != tt.tickers[i] {
+					t.Errorf("expected ticker %s at #%d, got %s", tt.tickers[i], i, cfg.Ticker)
+				}
+			}
+		})
+	}
+}
+
+func TestFeedConfigPriceBounds(t *testing.T) {
+	cfg := &FeedConfig{
+		MinPrice: "1.5",
+		MaxPrice: "10",
+	}
+
+	bounds, err := cfg.priceBounds()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for price, expected := range map[string]bool{
+		"1.4":  false,
+		"1.5":  true,
+		"5":    true,
+		"10":   true,
+		"10.1": false,
+	} {
+		if got := bounds.contains(decimal.RequireFromString(price)); got != expected {
+			t.Errorf("expected contains(%s) = %v, got %v", price, expected, got)
+		}
+	}
+
+	bounds, err = (&FeedConfig{MaxPrice: "10"}).priceBounds()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if !bounds.contains(decimal.RequireFromString("0.001")) {
+		t.Errorf("expected price to be within bounds without min price")
+	}
+
+	if _, err = (&FeedConfig{MinPrice: "10", MaxPrice: "1"}).priceBounds(); err == nil {
+		t.Errorf("expected error for min price greater than max price")
+	}
+
+	if _, err = (&FeedConfig{MinPrice: "abc"}).priceBounds(); err == nil {
+		t.Errorf("expected error for invalid min price")
+	}
+}
+
+func TestRoundToSubmitPrecision(t *testing.T) {
+	precision := 2
+	cfg := &FeedConfig{
+		Ticker:          "BTC/USD",
+		SubmitPrecision: &precision,
+	}
+
+	submitPrecision, err := cfg.submitPrecision()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	svc := &oracleSvc{
+		submitPrecisions: map[string]int32{
+			cfg.Ticker: submitPrecision,
+		},
+	}
+
+	for _, tt := range []struct {
+		ticker   string
+		price    string
+		expected string
+	}{
+		{ticker: "BTC/USD", price: "64000.125", expected: "64000.13"},
+		{ticker: "BTC/USD", price: "64000.1", expected: "64000.1"},
+		{ticker: "BTC/USD", price: "0.001", expected: "0"},
+		{ticker: "ETH/USD", price: "0.0000000000000000015", expected: "0.000000000000000002"},
+	} {
+		priceData := &PriceData{
+			Ticker: Ticker(tt.ticker),
+			Price:  decimal.RequireFromString(tt.price),
+		}
+
+		if got := svc.roundToSubmitPrecision(priceData); got.Price.String() != tt.expected {
+			t.Errorf("expected %s price %s rounded to %s, got %s", tt.ticker, tt.price, tt.expected, got.Price)
+		} else if priceData.Price.String() != tt.price {
+			t.Errorf("expected original price data to stay unchanged, got %s", priceData.Price)
+		}
+	}
+
+	for _, precision := range []int{-1, 19} {
+		if _, err := (&FeedConfig{SubmitPrecision: &precision}).submitPrecision(); err == nil {
+			t.Errorf("expected error for submit precision %d", precision)
+		}
+	}
+}
+
+func TestDynamicPriceFeedUpstreamTimestamp(t *testing.T) {
+	reportedAt := time.Now().Add(-90 * time.Second).Truncate(time.Millisecond)
+
+	tests := []struct {
+		name              string
+		observationSource string
+		maxPriceAge       string
+		expectedTimestamp time.Time
+		wantErr           bool
+	}{
+		{
+			name: "Timestamp in unix milliseconds",
+			observationSource: fmt.Sprintf(`result [type=merge left=<{"price": "64000.5", "timestamp": %d}> right=<{"source": "test"}>]`,
+				reportedAt.UnixMilli()),
+			expectedTimestamp: reportedAt,
+		},
+		{
+			name: "Timestamp as RFC 3339 string",
+			observationSource: fmt.Sprintf(`result [type=merge left=<{"price": "64000.5", "timestamp": "%s"}> right=<{"source": "test"}>]`,
+				reportedAt.UTC().Format(time.RFC3339Nano)),
+			expectedTimestamp: reportedAt,
+		},
+		{
+			name: "Stale price",
+			observationSource: fmt.Sprintf(`result [type=merge left=<{"price": "64000.5", "timestamp": %d}> right=<{"source": "test"}>]`,
+				reportedAt.Unix()),
+			maxPriceAge: "1m",
+			wantErr:     true,
+		},
+		{
+			name:              "Map without price",
+			observationSource: `result [type=merge left=<{"value": "64000.5"}> right=<{"source": "test"}>]`,
+			wantErr:           true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			feed, err := NewDynamicPriceFeed(&FeedConfig{
+				ProviderName:      "test",
+				Ticker:            "BTC/USDT",
+				ObservationSource: tt.observationSource,
+				MaxPriceAge:       tt.maxPriceAge,
+			})
+			if err != nil {
+				t.Fatalf("failed to init feed: %v", err)
+			}
+
+			priceData, err := feed.PullPrice(context.Background())
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("expected error, got %+v", priceData)
+				}
+				return
+			} else if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if !priceData.Price.Equal(decimal.RequireFromString("64000.5")) {
+				t.Errorf("expected price 64000.5, got %s", priceData.Price)
+			}
+
+			if !priceData.Timestamp.Equal(tt.expectedTimestamp) {
+				t.Errorf("expected timestamp %s, got %s", tt.expectedTimestamp, priceData.Timestamp)
+			}
+		})
+	}
+}
+
+func TestDynamicPriceFeedErrorKinds(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer srv.Close()
+
+	tests := []struct {
+		name              string
+		observationSource string
+		expected          error
+	}{
+		{
+			name:              "Task missing a parameter",
+			observationSource: `result [type=rangecheck input="1"]`,
+			expected:          ErrFeedConfig,
+		},
+		{
+			name:              "Map without price",
+			observationSource: `result [type=merge left=<{"value": "64000.5"}> right=<{"source": "test"}>]`,
+			expected:          ErrFeedConfig,
+		},
+		{
+			name:              "Upstream failure",
+			observationSource: fmt.Sprintf(`ticker [type=http method=GET url="%s"]; parse [type=jsonparse path="price"]; ticker -> parse`, srv.URL),
+			expected:          ErrTransient,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			feed, err := NewDynamicPriceFeed(&FeedConfig{
+				ProviderName:      "test",
+				Ticker:            "BTC/USDT",
+				ObservationSource: tt.observationSource,
+			})
+			if err != nil {
+				t.Fatalf("failed to init feed: %v", err)
+			}
+
+			_, err = feed.PullPrice(context.Background())
+			if !errors.Is(err, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, err)
+			}
+		})
+	}
+}
+
+func TestDynamicPriceFeedBigIntPrice(t *testing.T) {
+	// 123456789012345678901234567 is way above 2^53, it doesn't survive a float64 conversion
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":"0x000000000000000000000000000000000000000000661efdf158f2a82c9f4b87"}`))
+	}))
+	defer srv.Close()
+
+	feed, err := NewDynamicPriceFeed(&FeedConfig{
+		ProviderName: "test",
+		Ticker:       "BTC/USDT",
+		ObservationSource: fmt.Sprintf(`
+			call   [type=http method=GET url="%s"];
+			parse  [type=jsonparse path="result"];
+			decode [type=ethabidecode abi="uint256 price" data="$(parse)"];
+			result [type=merge left="$(decode)" right=<{"source": "test"}>];
+
+			call -> parse -> decode -> result
+		`, srv.URL),
+	})
+	if err != nil {
+		t.Fatalf("failed to init feed: %v", err)
+	}
+
+	priceData, err := feed.PullPrice(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if priceData.Price.String() != "123456789012345678901234567" {
+		t.Errorf("expected price 123456789012345678901234567, got %s", priceData.Price)
+	}
+}
+
+func TestPipelineResultPrice(t *testing.T) {
+	bigInt, _ := new(big.Int).SetString("123456789012345678901234567", 10)
+	bigFloat, _, _ := big.ParseFloat("123456789012345678.125", 10, 256, big.ToNearestEven)
+
+	for value, expected := range map[interface{}]string{
+		bigInt:                         "123456789012345678901234567",
+		bigFloat:                       "123456789012345678.125",
+		uint64(18446744073709551615):   "18446744073709551615",
+		"64000.5":                      "64000.5",
+		decimal.RequireFromString("1"): "1",
+	} {
+		price, err := pipelineResultPrice(value)
+		if err != nil {
+			t.Errorf("unexpected error for %v (%T): %v", value, value, err)
+		} else if price.String() != expected {
+			t.Errorf("expected price %s for %T, got %s", expected, value, price)
+		}
+	}
+
+	if _, err := pipelineResultPrice(new(big.Float).SetInf(false)); err == nil {
+		t.Errorf("expected error for infinite big.Float")
+	}
+}
+
+func TestParseUpstreamTimestamp(t *testing.T) {
+	expected := time.Unix(1700000000, 0)
+
+	for _, value := range []interface{}{
+		float64(1700000000),
+		"1700000000",
+		int64(1700000000000),
+		"1700000000000000",
+		decimal.New(1700000000, 9),
+		"2023-11-14T22:13:20Z",
+	} {
+		timestamp, err := parseUpstreamTimestamp(value)
+		if err != nil {
+			t.Errorf("unexpected error for %v: %v", value, err)
+		} else if !timestamp.Equal(expected) {
+			t.Errorf("expected %s for %v, got %s", expected, value, timestamp)
+		}
+	}
+
+	for _, value := range []interface{}{"yesterday", float64(-1), true} {
+		if _, err := parseUpstreamTimestamp(value); err == nil {
+			t.Errorf("expected error for %v", value)
+		}
+	}
+}
+
+func TestDefaultPullIntervals(t *testing.T) {
+	defaults, err := ParseDefaultPullIntervals([]string{"stork=10s", " binance = 30s "})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if expected := map[string]string{"stork": "10s", "binance": "30s"}; !reflect.DeepEqual(defaults, expected) {
+		t.Errorf("expected %v, got %v", expected, defaults)
+	}
+
+	for _, entries := range [][]string{{"stork"}, {"=10s"}, {"stork=soon"}, {"stork=100ms"}} {
+		if _, err := ParseDefaultPullIntervals(entries); err == nil {
+			t.Errorf("expected error for %v", entries)
+		}
+	}
+
+	cfg := &FeedConfig{ProviderName: "stork"}
+	cfg.SetDefaultPullInterval(defaults)
+	if cfg.PullInterval != "10s" {
+		t.Errorf("expected default pull interval 10s, got %q", cfg.PullInterval)
+	}
+
+	cfg = &FeedConfig{ProviderName: "binance", PullInterval: "1m"}
+	cfg.SetDefaultPullInterval(defaults)
+	if cfg.PullInterval != "1m" {
+		t.Errorf("expected the feed pull interval to take precedence, got %q", cfg.PullInterval)
+	}
+}
@@ -0,0 +1,63 @@
+package oracle
+
+import (
+	"github.com/pkg/errors"
+
+	"github.com/InjectiveLabs/injective-price-oracle/pipeline"
+)
+
+var (
+	// ErrFeedConfig marks pull errors caused by the feed config, e.g. a malformed pipeline spec,
+	// which fail every pull the same way until the config is fixed, so they're not worth retrying.
+	ErrFeedConfig = errors.New("feed config error")
+
+	// ErrTransient marks pull errors that may go away on retry, e.g. a failed or timed out request.
+	ErrTransient = errors.New("transient error")
+)
+
+// feedError classifies a pull error as one of ErrFeedConfig or ErrTransient, keeping the original error as its cause.
+type feedError struct {
+	kind error
+	err  error
+}
+
+func (e *feedError) Error() string {
+	return e.err.Error()
+}
+
+func (e *feedError) Unwrap() error {
+	return e.err
+}
+
+func (e *feedError) Is(target error) bool {
+	return target == e.kind
+}
+
+func feedConfigError(err error) error {
+	return &feedError{kind: ErrFeedConfig, err: err}
+}
+
+func transientError(err error) error {
+	return &feedError{kind: ErrTransient, err: err}
+}
+
+// isPipelineConfigError checks whether a pipeline task error is caused by the spec rather than by the data
+// it processed, e.g. a task missing its inputs or a required parameter.
+func isPipelineConfigError(err error) bool {
+	var specErr pipeline.ErrInvalidSpec
+	return errors.As(err, &specErr) ||
+		errors.Is(err, pipeline.ErrWrongInputCardinality) ||
+		errors.Is(err, pipeline.ErrParameterEmpty)
+}
+
+// classifyPipelineErrors classifies a failed pipeline run as a config error, if any of its task errors
+// is caused by the spec, since retrying the run would fail the same way, or as a transient error otherwise.
+func classifyPipelineErrors(err error, taskErrors []error) error {
+	for _, taskErr := range taskErrors {
+		if taskErr != nil && isPipelineConfigError(taskErr) {
+			return feedConfigError(err)
+		}
+	}
+
+	return transientError(err)
+}
@@ -0,0 +1,169 @@
+package oracle
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/InjectiveLabs/metrics"
+	log "github.com/InjectiveLabs/suplog"
+	"github.com/pkg/errors"
+)
+
+const defaultFailoverAfter = 2
+
+var _ PricePuller = &failoverPriceFeed{}
+
+// failoverPriceFeed pulls the price from the primary puller of a feed, switching to the fallback puller
+// once the primary has failed for a number of pull intervals in a row, and back once the primary recovers.
+// Unlike the fallback provider, it sticks to the primary through short outages and works with any provider.
+type failoverPriceFeed struct {
+	PricePuller
+
+	ticker        string
+	fallback      PricePuller
+	failoverAfter int
+
+	// failed intervals of the primary in a row, and the time the last one was counted
+	failures    int
+	lastFailure time.Time
+	failedOver  bool
+	mu          sync.Mutex
+
+	logger log.Logger
+}
+
+// newFailoverPriceFeed wraps the primary puller of the feed with failover to its fallbackProvider, declared
+// as a nested feed config inheriting the ticker and oracle type of the feed. Stork is not supported.
+func newFailoverPriceFeed(primary PricePuller, cfg *FeedConfig) (PricePuller, error) {
+	fallbackCfg := *cfg.FallbackProvider
+	switch {
+	case primary.Provider() == FeedProviderStork || FeedProvider(fallbackCfg.ProviderName) == FeedProviderStork:
+		return nil, errors.Errorf("fallbackProvider is not supported by Stork feeds")
+	case len(cfg.Outputs) > 0:
+		return nil, errors.New("fallbackProvider is not supported by feeds with outputs")
+	case fallbackCfg.FallbackProvider != nil:
+		return nil, errors.New("fallbackProvider: nested fallbackProvider is not supported")
+	case cfg.FailoverAfter < 0:
+		return nil, errors.Errorf("failoverAfter must not be negative, got %d", cfg.FailoverAfter)
+	}
+
+	if len(fallbackCfg.Ticker) == 0 {
+		fallbackCfg.Ticker = cfg.Ticker
+	}
+
+	if len(fallbackCfg.OracleType) == 0 {
+		fallbackCfg.OracleType = primary.OracleType().String()
+	}
+
+	if err := validateFeedConfig(&fallbackCfg); err != nil {
+		return nil, errors.Wrap(err, "fallbackProvider")
+	}
+
+	fallback, err := NewPricePuller(&fallbackCfg, nil)
+	if err != nil {
+		return nil, errors.Wrapf(err, "fallbackProvider: failed to init %s price feed", fallbackCfg.ProviderName)
+	} else if fallback.OracleType() != primary.OracleType() {
+		return nil, errors.Errorf("fallbackProvider: oracle type %s differs from the feed oracle type %s", fallback.OracleType(), primary.OracleType())
+	}
+
+	failoverAfter := cfg.FailoverAfter
+	if failoverAfter == 0 {
+		failoverAfter = defaultFailoverAfter
+	}
+
+	return &failoverPriceFeed{
+		PricePuller:   primary,
+		ticker:        cfg.Ticker,
+		fallback:      fallback,
+		failoverAfter: failoverAfter,
+		logger: log.WithFields(log.Fields{
+			"svc":      "oracle",
+			"provider": primary.ProviderName(),
+			"fallback": fallback.ProviderName(),
+			"ticker":   cfg.Ticker,
+		}),
+	}, nil
+}
+
+// PullPrice pulls the primary price, or the fallback price once the primary has failed, returned an error
+// or no price, for failoverAfter pull intervals in a row. Retries of a pull count as the same interval.
+// The primary is pulled first every time, so the feed switches back as soon as it recovers.
+func (f *failoverPriceFeed) PullPrice(ctx context.Context) (*PriceData, error) {
+	priceData, err := f.PricePuller.PullPrice(ctx)
+	if err == nil && priceData != nil {
+		f.primaryRecovered()
+		return priceData, nil
+	}
+
+	if err == nil {
+		err = errors.New("no price")
+	}
+
+	if !f.primaryFailed(err) {
+		return nil, err
+	}
+
+	priceData, fallbackErr := f.fallback.PullPrice(ctx)
+	if fallbackErr != nil {
+		return nil, errors.Wrapf(fallbackErr, "primary (%s) failed with %v, fallback (%s)", f.ProviderName(), err, f.fallback.ProviderName())
+	} else if priceData == nil {
+		return nil, nil
+	}
+
+	priceData.Ticker = Ticker(f.ticker)
+	priceData.ProviderName = f.ProviderName()
+	return priceData, nil
+}
+
+// primaryFailed counts a failed interval of the primary, returning whether the feed has failed over.
+func (f *failoverPriceFeed) primaryFailed(err error) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.failures == 0 || time.Since(f.lastFailure) >= f.Interval()/2 {
+		f.failures++
+		f.lastFailure = time.Now()
+	}
+
+	if !f.failedOver && f.failures >= f.failoverAfter {
+		f.failedOver = true
+		f.logger.WithError(err).Warningf("primary failed for %d intervals, failing over", f.failures)
+		f.reportSwitch(f.fallback.ProviderName())
+	}
+
+	return f.failedOver
+}
+
+func (f *failoverPriceFeed) primaryRecovered() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.failures = 0
+	if f.failedOver {
+		f.failedOver = false
+		f.logger.Infoln("primary recovered, switching back")
+		f.reportSwitch(f.ProviderName())
+	}
+}
+
+func (f *failoverPriceFeed) reportSwitch(to string) {
+	tags := metrics.Tags{
+		"svc":    "price_oracle",
+		"ticker": f.ticker,
+		"to":     to,
+	}
+
+	metrics.CustomReport(func(s metrics.Statter, tagSpec []string) {
+		s.Count("price_oracle.failover.switched.size", 1, tagSpec, 1)
+	}, tags)
+}
+
+// Close stops streaming pullers.
+func (f *failoverPriceFeed) Close() {
+	for _, puller := range []PricePuller{f.PricePuller, f.fallback} {
+		if closer, ok := puller.(interface{ Close() }); ok {
+			closer.Close()
+		}
+	}
+}
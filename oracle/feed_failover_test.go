@@ -0,0 +1,71 @@
+package oracle
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestFailoverPriceFeed(t *testing.T) {
+	pricePuller, err := NewPricePuller(&FeedConfig{
+		ProviderName:      "test",
+		Ticker:            "INJ/USDT",
+		PullInterval:      "1m",
+		ObservationSource: `down [type=fail msg="source down"]`,
+		FallbackProvider: &FeedConfig{
+			ProviderName: "constant",
+			Value:        "24",
+		},
+		FailoverAfter: 2,
+	}, nil)
+	if err != nil {
+		t.Fatalf("failed to init feed: %v", err)
+	}
+
+	feed, ok := pricePuller.(*failoverPriceFeed)
+	if !ok {
+		t.Fatalf("expected failover feed, got %T", pricePuller)
+	}
+
+	// a retry within the same interval doesn't count as another failed interval
+	for i := 0; i < 2; i++ {
+		if _, err := feed.PullPrice(context.Background()); err == nil {
+			t.Fatalf("expected primary error before failover")
+		}
+	}
+
+	feed.lastFailure = time.Now().Add(-time.Minute)
+
+	priceData, err := feed.PullPrice(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if priceData.Ticker != "INJ/USDT" || !priceData.Price.Equal(decimal.NewFromInt(24)) {
+		t.Errorf("expected fallback INJ/USDT price 24, got %s price %s", priceData.Ticker, priceData.Price)
+	}
+
+	// switches back once the primary recovers
+	feed.PricePuller, err = NewConstantPriceFeed(&FeedConfig{ProviderName: "test", Ticker: "INJ/USDT", Value: "25"})
+	if err != nil {
+		t.Fatalf("failed to init feed: %v", err)
+	}
+
+	priceData, err = feed.PullPrice(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if !priceData.Price.Equal(decimal.NewFromInt(25)) || feed.failedOver {
+		t.Errorf("expected primary price 25, got %s", priceData.Price)
+	}
+
+	for name, cfg := range map[string]*FeedConfig{
+		"Stork fallback":    {ProviderName: "test", Ticker: "INJ/USDT", FallbackProvider: &FeedConfig{ProviderName: "stork"}},
+		"nested fallback":   {ProviderName: "test", Ticker: "INJ/USDT", FallbackProvider: &FeedConfig{ProviderName: "constant", Value: "1", FallbackProvider: &FeedConfig{ProviderName: "constant", Value: "1"}}},
+		"negative failover": {ProviderName: "test", Ticker: "INJ/USDT", FallbackProvider: &FeedConfig{ProviderName: "constant", Value: "1"}, FailoverAfter: -1},
+	} {
+		cfg.ObservationSource = `down [type=fail msg="source down"]`
+		if _, err := NewPricePuller(cfg, nil); err == nil {
+			t.Errorf("expected error for %s", name)
+		}
+	}
+}
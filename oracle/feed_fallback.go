@@ -0,0 +1,116 @@
+package oracle
+
+import (
+	"context"
+
+	"github.com/InjectiveLabs/metrics"
+	log "github.com/InjectiveLabs/suplog"
+	"github.com/pkg/errors"
+	"go.uber.org/multierr"
+)
+
+var _ PricePuller = &fallbackPriceFeed{}
+
+// fallbackPriceFeed pulls the price from the first available of its sources, in priority order,
+// so a feed degrades gracefully when its primary source stalls.
+type fallbackPriceFeed struct {
+	*restPriceFeed
+
+	sources []PricePuller
+}
+
+// NewFallbackPriceFeed returns price puller for a feed with prioritized sources, each declared as
+// a nested feed config, inheriting the ticker and oracle type of the feed. A source is available
+// if it pulls a price without an error, so sources should set maxPriceAge to be skipped when stale.
+// Stork and nested fallback sources are not supported.
+func NewFallbackPriceFeed(cfg *FeedConfig) (PricePuller, error) {
+	restFeed, err := newRestPriceFeed(FeedProviderFallback, cfg, cfg.Ticker)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(cfg.Sources) == 0 {
+		return nil, errors.Errorf("sources must be set for %s provider", FeedProviderFallback)
+	}
+
+	sources := make([]PricePuller, 0, len(cfg.Sources))
+	for i, sourceCfg := range cfg.Sources {
+		switch FeedProvider(sourceCfg.ProviderName) {
+		case FeedProviderStork, FeedProviderFallback:
+			return nil, errors.Errorf("source %d: %s provider is not supported as a fallback source", i, sourceCfg.ProviderName)
+		}
+
+		sourceCfg := *sourceCfg
+		if len(sourceCfg.Ticker) == 0 {
+			sourceCfg.Ticker = cfg.Ticker
+		}
+
+		if len(sourceCfg.OracleType) == 0 {
+			sourceCfg.OracleType = cfg.OracleType
+		}
+
+		if err := validateFeedConfig(&sourceCfg); err != nil {
+			return nil, errors.Wrapf(err, "source %d", i)
+		}
+
+		source, err := NewPricePuller(&sourceCfg, nil)
+		if err != nil {
+			return nil, errors.Wrapf(err, "source %d: failed to init %s price feed", i, sourceCfg.ProviderName)
+		} else if source.OracleType() != restFeed.oracleType {
+			return nil, errors.Errorf("source %d: oracle type %s differs from the feed oracle type %s", i, source.OracleType(), restFeed.oracleType)
+		}
+
+		sources = append(sources, source)
+	}
+
+	return &fallbackPriceFeed{
+		restPriceFeed: restFeed,
+		sources:       sources,
+	}, nil
+}
+
+// PullPrice returns the price of the first source in priority order that has one. It fails only if
+// all sources fail, and returns nil price data if no source has a price yet.
+func (f *fallbackPriceFeed) PullPrice(ctx context.Context) (*PriceData, error) {
+	var errs error
+	for i, source := range f.sources {
+		priceData, err := source.PullPrice(ctx)
+		if err != nil {
+			errs = multierr.Append(errs, errors.Wrapf(err, "source %d (%s)", i, source.ProviderName()))
+			continue
+		} else if priceData == nil {
+			continue
+		}
+
+		tags := f.svcTags.With("ticker", f.ticker)
+		metrics.CustomReport(func(s metrics.Statter, tagSpec []string) {
+			s.Count("price_oracle.fallback.source_used.size", 1, tagSpec, 1)
+		}, tags.With("source", source.ProviderName()))
+
+		if i > 0 {
+			f.logger.WithFields(log.Fields{
+				"source":   source.ProviderName(),
+				"priority": i,
+			}).WithError(errs).Warningln("primary source unavailable, using fallback source")
+		}
+
+		priceData.Ticker = Ticker(f.ticker)
+		priceData.ProviderName = f.ProviderName()
+		return priceData, nil
+	}
+
+	if errs != nil && len(multierr.Errors(errs)) == len(f.sources) {
+		return nil, errors.Wrap(errs, "all sources failed")
+	}
+
+	return nil, nil
+}
+
+// Close stops streaming sources.
+func (f *fallbackPriceFeed) Close() {
+	for _, source := range f.sources {
+		if closer, ok := source.(interface{ Close() }); ok {
+			closer.Close()
+		}
+	}
+}
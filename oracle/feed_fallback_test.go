@@ -0,0 +1,74 @@
+package oracle
+
+import (
+	"context"
+	"testing"
+)
+
+func TestFallbackPriceFeed(t *testing.T) {
+	cfg, err := ParseFeedConfig([]byte(`
+provider = "fallback"
+ticker = "INJ/USDT"
+pullInterval = "10s"
+
+[[sources]]
+provider = "primary"
+observationSource = """
+   ticker [type=http method=GET url="http://127.0.0.1:1/price"];
+   parsePrice [type="jsonparse" path="price"]
+
+   ticker -> parsePrice
+"""
+
+[[sources]]
+provider = "secondary"
+observationSource = 'a [type=memo value="25.5"]; b [type=multiply times=1]; a -> b'
+`), FeedConfigFormatTOML)
+	if err != nil {
+		t.Fatalf("failed to parse config: %v", err)
+	}
+
+	feed, err := NewPricePuller(cfg, nil)
+	if err != nil {
+		t.Fatalf("failed to init feed: %v", err)
+	}
+
+	priceData, err := feed.PullPrice(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if priceData.Price.String() != "25.5" {
+		t.Errorf("expected price of the secondary source 25.5, got %s", priceData.Price)
+	}
+
+	if priceData.Ticker != "INJ/USDT" || priceData.ProviderName != "fallback" {
+		t.Errorf("expected price data of the fallback feed, got %s from %s", priceData.Ticker, priceData.ProviderName)
+	}
+
+	// all sources failing fails the pull
+	cfg.Sources = cfg.Sources[:1]
+	if feed, err = NewPricePuller(cfg, nil); err != nil {
+		t.Fatalf("failed to init feed: %v", err)
+	}
+
+	if _, err := feed.PullPrice(context.Background()); err == nil {
+		t.Errorf("expected error when all sources fail")
+	}
+}
+
+func TestNewFallbackPriceFeedValidation(t *testing.T) {
+	for name, cfg := range map[string]*FeedConfig{
+		"no sources": {ProviderName: "fallback", Ticker: "INJ/USDT"},
+		"stork source": {ProviderName: "fallback", Ticker: "INJ/USDT", Sources: []*FeedConfig{
+			{ProviderName: "stork"},
+		}},
+		"oracle type mismatch": {ProviderName: "fallback", Ticker: "INJ/USDT", Sources: []*FeedConfig{
+			{ProviderName: "test", ObservationSource: `price [type=memo value="1"]`, OracleType: "Provider"},
+		}},
+	} {
+		if _, err := NewFallbackPriceFeed(cfg); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package oracle
+
+import (
+	"context"
+	"net/url"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/shopspring/decimal"
+)
+
+const gateioBaseURL = "https://api.gateio.ws"
+
+var _ PricePuller = &gateioPriceFeed{}
+
+// gateioPriceFeed pulls the last trade price of a spot pair from Gate.io.
+type gateioPriceFeed struct {
+	*restPriceFeed
+
+	baseURL string
+}
+
+// NewGateioPriceFeed returns price puller for Gate.io spot tickers. Gate.io pairs are underscore-separated
+// (e.g. BTC_USDT), by default derived from the ticker, unless set by the symbol config field.
+func NewGateioPriceFeed(cfg *FeedConfig) (PricePuller, error) {
+	defaultSymbol := strings.ToUpper(strings.ReplaceAll(cfg.Ticker, "/", "_"))
+
+	restFeed, err := newRestPriceFeed(FeedProviderGateio, cfg, defaultSymbol)
+	if err != nil {
+		return nil, err
+	}
+
+	return &gateioPriceFeed{
+		restPriceFeed: restFeed,
+		baseURL:       gateioBaseURL,
+	}, nil
+}
+
+type gateioTicker struct {
+	CurrencyPair string `json:"currency_pair"`
+	Last         string `json:"last"`
+}
+
+func (f *gateioPriceFeed) PullPrice(ctx context.Context) (*PriceData, error) {
+	u := f.baseURL + "/api/v4/spot/tickers?currency_pair=" + url.QueryEscape(f.symbol)
+
+	var tickers []gateioTicker
+	if err := f.getJSON(ctx, u, nil, &tickers); err != nil {
+		return nil, err
+	}
+
+	if len(tickers) == 0 {
+		return nil, errors.Errorf("no ticker returned for currency pair %s, is it listed on Gate.io?", f.symbol)
+	}
+
+	price, err := decimal.NewFromString(tickers[0].Last)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to parse last price: %s", tickers[0].Last)
+	}
+
+	return f.priceData(price)
+}
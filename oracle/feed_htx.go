@@ -0,0 +1,67 @@
+package oracle
+
+import (
+	"context"
+	"net/url"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/shopspring/decimal"
+)
+
+const htxBaseURL = "https://api.huobi.pro"
+
+var _ PricePuller = &htxPriceFeed{}
+
+// htxPriceFeed pulls the close price of the merged market detail of a spot pair from HTX (Huobi).
+type htxPriceFeed struct {
+	*restPriceFeed
+
+	baseURL string
+}
+
+// NewHTXPriceFeed returns price puller for HTX spot tickers. HTX symbols are lowercase and concatenated
+// (e.g. btcusdt), by default derived from the ticker, unless set by the symbol config field.
+func NewHTXPriceFeed(cfg *FeedConfig) (PricePuller, error) {
+	defaultSymbol := strings.ToLower(strings.ReplaceAll(cfg.Ticker, "/", ""))
+
+	restFeed, err := newRestPriceFeed(FeedProviderHTX, cfg, defaultSymbol)
+	if err != nil {
+		return nil, err
+	}
+
+	restFeed.symbol = strings.ToLower(restFeed.symbol)
+
+	return &htxPriceFeed{
+		restPriceFeed: restFeed,
+		baseURL:       htxBaseURL,
+	}, nil
+}
+
+type htxResponse struct {
+	Status  string `json:"status"`
+	ErrCode string `json:"err-code"`
+	ErrMsg  string `json:"err-msg"`
+	Tick    *struct {
+		Close decimal.Decimal `json:"close"`
+	} `json:"tick"`
+}
+
+func (f *htxPriceFeed) PullPrice(ctx context.Context) (*PriceData, error) {
+	u := f.baseURL + "/market/detail/merged?symbol=" + url.QueryEscape(f.symbol)
+
+	var resp htxResponse
+	if err := f.getJSON(ctx, u, nil, &resp); err != nil {
+		return nil, err
+	}
+
+	if resp.Status != "ok" {
+		return nil, errors.Errorf("HTX API error %s: %s", resp.ErrCode, resp.ErrMsg)
+	}
+
+	if resp.Tick == nil {
+		return nil, errors.Errorf("no tick returned for symbol %s", f.symbol)
+	}
+
+	return f.priceData(resp.Tick.Close)
+}
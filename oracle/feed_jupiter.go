@@ -0,0 +1,58 @@
+package oracle
+
+import (
+	"context"
+	"net/url"
+
+	"github.com/shopspring/decimal"
+)
+
+const jupiterBaseURL = "https://price.jup.ag"
+
+var _ PricePuller = &jupiterPriceFeed{}
+
+// jupiterPriceFeed pulls the USDC price of a Solana SPL token from Jupiter Price API.
+type jupiterPriceFeed struct {
+	*restPriceFeed
+
+	baseURL string
+}
+
+// NewJupiterPriceFeed returns price puller for Jupiter prices. The symbol config field is required
+// and holds the token mint address.
+func NewJupiterPriceFeed(cfg *FeedConfig) (PricePuller, error) {
+	restFeed, err := newRestPriceFeed(FeedProviderJupiter, cfg, "")
+	if err != nil {
+		return nil, err
+	}
+
+	return &jupiterPriceFeed{
+		restPriceFeed: restFeed,
+		baseURL:       jupiterBaseURL,
+	}, nil
+}
+
+type jupiterResponse struct {
+	Data map[string]struct {
+		ID    string          `json:"id"`
+		Price decimal.Decimal `json:"price"`
+	} `json:"data"`
+}
+
+// PullPrice returns nil price data if Jupiter has no price for the mint, so the pull is skipped.
+func (f *jupiterPriceFeed) PullPrice(ctx context.Context) (*PriceData, error) {
+	u := f.baseURL + "/v6/price?ids=" + url.QueryEscape(f.symbol)
+
+	var resp jupiterResponse
+	if err := f.getJSON(ctx, u, nil, &resp); err != nil {
+		return nil, err
+	}
+
+	tokenPrice, ok := resp.Data[f.symbol]
+	if !ok {
+		f.logger.WithField("mint", f.symbol).Warningln("no price for mint, skipping")
+		return nil, nil
+	}
+
+	return f.priceData(tokenPrice.Price)
+}
@@ -0,0 +1,72 @@
+package oracle
+
+import (
+	"context"
+	"net/url"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/shopspring/decimal"
+)
+
+const (
+	kucoinBaseURL = "https://api.kucoin.com"
+	kucoinCodeOK  = "200000"
+)
+
+var _ PricePuller = &kucoinPriceFeed{}
+
+// kucoinPriceFeed pulls the last trade price of a spot pair from KuCoin level 1 orderbook.
+type kucoinPriceFeed struct {
+	*restPriceFeed
+
+	baseURL string
+}
+
+// NewKucoinPriceFeed returns price puller for KuCoin spot tickers. KuCoin pairs are dash-separated
+// (e.g. BTC-USDT), by default derived from the ticker, unless set by the symbol config field.
+func NewKucoinPriceFeed(cfg *FeedConfig) (PricePuller, error) {
+	defaultSymbol := strings.ToUpper(strings.ReplaceAll(cfg.Ticker, "/", "-"))
+
+	restFeed, err := newRestPriceFeed(FeedProviderKucoin, cfg, defaultSymbol)
+	if err != nil {
+		return nil, err
+	}
+
+	return &kucoinPriceFeed{
+		restPriceFeed: restFeed,
+		baseURL:       kucoinBaseURL,
+	}, nil
+}
+
+type kucoinResponse struct {
+	Code string `json:"code"`
+	Msg  string `json:"msg"`
+	Data *struct {
+		Price string `json:"price"`
+	} `json:"data"`
+}
+
+func (f *kucoinPriceFeed) PullPrice(ctx context.Context) (*PriceData, error) {
+	u := f.baseURL + "/api/v1/market/orderbook/level1?symbol=" + url.QueryEscape(f.symbol)
+
+	var resp kucoinResponse
+	if err := f.getJSON(ctx, u, nil, &resp); err != nil {
+		return nil, err
+	}
+
+	if resp.Code != kucoinCodeOK {
+		return nil, errors.Errorf("KuCoin API error %s: %s", resp.Code, resp.Msg)
+	}
+
+	if resp.Data == nil {
+		return nil, errors.Errorf("no data returned for symbol %s, is it listed on KuCoin?", f.symbol)
+	}
+
+	price, err := decimal.NewFromString(resp.Data.Price)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to parse price: %s", resp.Data.Price)
+	}
+
+	return f.priceData(price)
+}
@@ -0,0 +1,208 @@
+package oracle
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/InjectiveLabs/metrics"
+	oracletypes "github.com/InjectiveLabs/sdk-go/chain/oracle/types"
+	log "github.com/InjectiveLabs/suplog"
+	"github.com/pkg/errors"
+)
+
+// MultiPricePuller is implemented by price pullers yielding prices of many tickers in a single pull,
+// e.g. dynamic feeds with many pipeline outputs.
+type MultiPricePuller interface {
+	PricePuller
+
+	// PullPrices pulls prices of all tickers of the feed at once.
+	PullPrices(ctx context.Context) ([]*PriceData, error)
+}
+
+var _ MultiPricePuller = (*dynamicPriceFeed)(nil)
+
+// validateOutputs checks that every pipeline output is mapped to a distinct ticker, one of them the feed ticker.
+func (c *FeedConfig) validateOutputs() error {
+	if len(c.Outputs) == 0 {
+		return nil
+	} else if FeedProvider(c.ProviderName) == FeedProviderStork || c.OracleType == oracletypes.OracleType_Stork.String() {
+		return errors.New("outputs are not supported by Stork feeds")
+	} else if len(c.MinPrice) > 0 || len(c.MaxPrice) > 0 {
+		// outputs are usually prices of different assets, so a single band would fit none of them
+		return errors.New("minPrice and maxPrice are not supported by feeds with outputs")
+	}
+
+	outputsByTicker := make(map[string]string, len(c.Outputs))
+	for name, ticker := range c.Outputs {
+		if len(name) == 0 || len(ticker) == 0 {
+			return errors.Errorf("output %q must be mapped to a ticker", name)
+		} else if other, ok := outputsByTicker[ticker]; ok {
+			return errors.Errorf("outputs %s and %s are mapped to the same ticker %s", other, name, ticker)
+		}
+
+		outputsByTicker[ticker] = name
+	}
+
+	// the feed ticker keeps pulls of a single price working, e.g. when probing or submitting the feed
+	if _, ok := outputsByTicker[c.Ticker]; !ok {
+		return errors.Errorf("one of the outputs must be mapped to the feed ticker %s", c.Ticker)
+	}
+
+	return nil
+}
+
+// outputTickers returns tickers of the feed outputs, other than the feed ticker itself.
+func (c *FeedConfig) outputTickers() []string {
+	tickers := make([]string, 0, len(c.Outputs))
+	for _, ticker := range c.Outputs {
+		if ticker != c.Ticker {
+			tickers = append(tickers, ticker)
+		}
+	}
+
+	sort.Strings(tickers)
+	return tickers
+}
+
+// PullPrices runs the pipeline once and returns the price of every output, or the single price
+// of the feed if it has no outputs.
+func (f *dynamicPriceFeed) PullPrices(ctx context.Context) ([]*PriceData, error) {
+	if len(f.outputs) == 0 {
+		priceData, err := f.PullPrice(ctx)
+		if err != nil || priceData == nil {
+			return nil, err
+		}
+
+		return []*PriceData{priceData}, nil
+	}
+
+	metrics.ReportFuncCall(f.svcTags)
+	doneFn := metrics.ReportFuncTiming(f.svcTags)
+	defer doneFn()
+
+	ts := time.Now()
+
+	runLogger := f.logger.WithFields(log.Fields{
+		"ticker": f.ticker,
+	})
+
+	value, err := f.runPipeline(ctx, runLogger)
+	if err != nil {
+		return nil, err
+	}
+
+	prices, err := f.outputPrices(value)
+	if err != nil {
+		return nil, err
+	}
+
+	runLogger.WithField("outputs", len(prices)).Infoln("PullPrices (pipeline run) done in", time.Since(ts))
+
+	return prices, nil
+}
+
+// outputPrices converts a pipeline result map of many outputs into the price data of their tickers,
+// sorted by ticker. Every output of the map must be mapped to a ticker, and every mapped output must be present.
+func (f *dynamicPriceFeed) outputPrices(value interface{}) ([]*PriceData, error) {
+	result, ok := value.(map[string]interface{})
+	if !ok {
+		err := errors.Errorf("expected pipeline result to be a map of outputs, but got %T", value)
+		return nil, feedConfigError(err)
+	}
+
+	for name := range result {
+		if _, ok := f.outputs[name]; !ok {
+			err := errors.Errorf("pipeline output %s is not mapped to a ticker", name)
+			return nil, feedConfigError(err)
+		}
+	}
+
+	prices := make([]*PriceData, 0, len(f.outputs))
+	for name, ticker := range f.outputs {
+		outputValue, ok := result[name]
+		if !ok {
+			err := errors.Errorf("expected pipeline result map to have the %s output", name)
+			return nil, feedConfigError(err)
+		}
+
+		priceData, err := f.priceData(ticker, outputValue)
+		if err != nil {
+			return nil, errors.Wrapf(err, "output %s", name)
+		}
+
+		prices = append(prices, priceData)
+	}
+
+	sort.Slice(prices, func(i, j int) bool {
+		return prices[i].Ticker < prices[j].Ticker
+	})
+
+	return prices, nil
+}
+
+// pullPrices pulls prices of all tickers of the puller, waiting for a free slot first if the number
+// of concurrent pulls is limited. Pullers of a single ticker yield at most one price.
+func (s *oracleSvc) pullPrices(ctx context.Context, pricePuller PricePuller) ([]*PriceData, error) {
+	multiPuller, ok := pricePuller.(MultiPricePuller)
+	if !ok {
+		priceData, err := s.pullPrice(ctx, pricePuller)
+		if err != nil || priceData == nil {
+			return nil, err
+		}
+
+		return []*PriceData{priceData}, nil
+	}
+
+	release, err := s.acquirePullSlot(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	return multiPuller.PullPrices(ctx)
+}
+
+// registerOutputTickers applies submit settings of the feed to tickers of its outputs, since their prices
+// are submitted the same way as the feed price. Price bounds are not set along with outputs.
+func (s *oracleSvc) registerOutputTickers(feedCfg *FeedConfig) {
+	for _, ticker := range feedCfg.outputTickers() {
+		s.submitPrecisions[ticker] = s.submitPrecisions[feedCfg.Ticker]
+
+		if priceScale, ok := s.priceScales[feedCfg.Ticker]; ok {
+			s.priceScales[ticker] = priceScale
+		}
+
+		if minSubmitInterval, ok := s.minSubmitIntervals[feedCfg.Ticker]; ok {
+			s.minSubmitIntervals[ticker] = minSubmitInterval
+		}
+
+		if oracleTypes, ok := s.extraOracleTypes[feedCfg.Ticker]; ok {
+			s.extraOracleTypes[ticker] = oracleTypes
+		}
+	}
+}
+
+// checkOutputTickers ensures no two feeds submit prices of the same ticker, either as their own ticker
+// or as a pipeline output.
+func checkOutputTickers(feedConfigs map[string]*FeedConfig) error {
+	feedTickers := make(map[string]struct{}, len(feedConfigs))
+	for _, feedCfg := range feedConfigs {
+		feedTickers[feedCfg.Ticker] = struct{}{}
+	}
+
+	outputFeeds := make(map[string]string)
+	for _, feedCfg := range feedConfigs {
+		for _, ticker := range feedCfg.outputTickers() {
+			if _, ok := feedTickers[ticker]; ok {
+				return errors.Errorf("output ticker %s of feed %s is also a feed ticker", ticker, feedCfg.Ticker)
+			} else if other, ok := outputFeeds[ticker]; ok {
+				return errors.Errorf("output ticker %s is yielded by both feeds %s and %s", ticker, other, feedCfg.Ticker)
+			}
+
+			outputFeeds[ticker] = feedCfg.Ticker
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,102 @@
+package oracle
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/shopspring/decimal"
+)
+
+func TestDynamicPriceFeedOutputs(t *testing.T) {
+	outputs := map[string]string{
+		"bid": "INJ/USDT",
+		"ask": "INJ/USDT-ASK",
+	}
+
+	feedCfg := &FeedConfig{
+		ProviderName:      "test",
+		Ticker:            "INJ/USDT",
+		ObservationSource: `result [type=merge left=<{"bid": "25.1"}> right=<{"ask": {"price": "25.3"}}>]`,
+		Outputs:           outputs,
+	}
+
+	if err := feedCfg.validateOutputs(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	pricePuller, err := NewPricePuller(feedCfg, nil)
+	if err != nil {
+		t.Fatalf("failed to init feed: %v", err)
+	}
+
+	prices, err := pricePuller.(MultiPricePuller).PullPrices(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if len(prices) != 2 {
+		t.Fatalf("expected 2 prices, got %d", len(prices))
+	}
+
+	for i, expected := range []struct {
+		ticker string
+		price  string
+	}{
+		{ticker: "INJ/USDT", price: "25.1"},
+		{ticker: "INJ/USDT-ASK", price: "25.3"},
+	} {
+		if string(prices[i].Ticker) != expected.ticker || !prices[i].Price.Equal(decimal.RequireFromString(expected.price)) {
+			t.Errorf("expected %s price %s, got %s price %s", expected.ticker, expected.price, prices[i].Ticker, prices[i].Price)
+		}
+	}
+
+	// a single price pull yields the output of the feed ticker
+	priceData, err := pricePuller.PullPrice(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if priceData.Ticker != "INJ/USDT" || !priceData.Price.Equal(decimal.RequireFromString("25.1")) {
+		t.Errorf("expected INJ/USDT price 25.1, got %s price %s", priceData.Ticker, priceData.Price)
+	}
+
+	// outputs of the pipeline must match the configured ones
+	for _, observationSource := range []string{
+		`result [type=merge left=<{"bid": "25.1"}> right=<{"last": "25.2"}>]`,
+		`result [type=merge left=<{"bid": "25.1"}> right=<{}>]`,
+	} {
+		feed, err := NewDynamicPriceFeed(&FeedConfig{
+			ProviderName:      "test",
+			Ticker:            "INJ/USDT",
+			ObservationSource: observationSource,
+			Outputs:           outputs,
+		})
+		if err != nil {
+			t.Fatalf("failed to init feed: %v", err)
+		}
+
+		if _, err := feed.(MultiPricePuller).PullPrices(context.Background()); !errors.Is(err, ErrFeedConfig) {
+			t.Errorf("expected feed config error for %s, got %v", observationSource, err)
+		}
+	}
+
+	for name, cfg := range map[string]*FeedConfig{
+		"output without ticker":      {Ticker: "INJ/USDT", Outputs: map[string]string{"bid": "INJ/USDT", "ask": ""}},
+		"outputs of the same ticker": {Ticker: "INJ/USDT", Outputs: map[string]string{"bid": "INJ/USDT", "ask": "INJ/USDT"}},
+		"no output of feed ticker":   {Ticker: "INJ/USDT", Outputs: map[string]string{"ask": "INJ/USDT-ASK"}},
+		"Stork feed":                 {ProviderName: "stork", Ticker: "INJ/USDT", Outputs: map[string]string{"bid": "INJ/USDT"}},
+		"price bounds":               {Ticker: "INJ/USDT", Outputs: outputs, MinPrice: "1", MaxPrice: "100"},
+	} {
+		if err := cfg.validateOutputs(); err == nil {
+			t.Errorf("expected error for %s", name)
+		}
+	}
+
+	if _, err := NewPricePuller(&FeedConfig{ProviderName: "binance", Ticker: "INJ/USDT", Outputs: outputs}, nil); err == nil {
+		t.Errorf("expected error for outputs of a native provider")
+	}
+
+	if err := checkOutputTickers(map[string]*FeedConfig{
+		"inj.toml": feedCfg,
+		"ask.toml": {Ticker: "INJ/USDT-ASK"},
+	}); err == nil {
+		t.Errorf("expected error for output ticker of another feed")
+	}
+}
@@ -0,0 +1,78 @@
+package oracle
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/shopspring/decimal"
+)
+
+const (
+	redstoneBaseURL      = "https://api.redstone.finance"
+	redstoneDataProvider = "redstone"
+	redstoneAPIKeyHeader = "X-Api-Key"
+)
+
+var _ PricePuller = &redstonePriceFeed{}
+
+// redstonePriceFeed pulls the latest price of a token from RedStone REST API.
+type redstonePriceFeed struct {
+	*restPriceFeed
+
+	baseURL string
+}
+
+// NewRedstonePriceFeed returns price puller for RedStone prices. RedStone symbols are base tokens
+// (e.g. BTC), by default derived from the ticker, unless set by the symbol config field.
+// If apiKeyEnv is set, the API key is sent in the X-Api-Key header.
+func NewRedstonePriceFeed(cfg *FeedConfig) (PricePuller, error) {
+	base, _, _ := strings.Cut(cfg.Ticker, "/")
+	defaultSymbol := strings.ToUpper(base)
+
+	restFeed, err := newRestPriceFeed(FeedProviderRedstone, cfg, defaultSymbol)
+	if err != nil {
+		return nil, err
+	}
+
+	return &redstonePriceFeed{
+		restPriceFeed: restFeed,
+		baseURL:       redstoneBaseURL,
+	}, nil
+}
+
+type redstonePrice struct {
+	Symbol    string          `json:"symbol"`
+	Value     decimal.Decimal `json:"value"`
+	Timestamp int64           `json:"timestamp"`
+}
+
+func (f *redstonePriceFeed) PullPrice(ctx context.Context) (*PriceData, error) {
+	query := url.Values{
+		"symbol":   []string{f.symbol},
+		"provider": []string{redstoneDataProvider},
+		"limit":    []string{"1"},
+	}
+	u := f.baseURL + "/prices?" + query.Encode()
+
+	var header http.Header
+	if len(f.apiKey) > 0 {
+		header = http.Header{}
+		header.Set(redstoneAPIKeyHeader, f.apiKey)
+	}
+
+	var prices []redstonePrice
+	if err := f.getJSON(ctx, u, header, &prices); err != nil {
+		return nil, err
+	}
+
+	if len(prices) == 0 {
+		return nil, errors.Errorf("no price returned for symbol %s", f.symbol)
+	}
+
+	// timestamp is in milliseconds
+	return f.priceDataAt(prices[0].Value, time.UnixMilli(prices[0].Timestamp))
+}
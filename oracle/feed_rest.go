@@ -0,0 +1,261 @@
+package oracle
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/InjectiveLabs/metrics"
+	oracletypes "github.com/InjectiveLabs/sdk-go/chain/oracle/types"
+	log "github.com/InjectiveLabs/suplog"
+	"github.com/pkg/errors"
+	"github.com/shopspring/decimal"
+)
+
+// restPriceFeed holds the parts common to native price feeds of a single provider, mostly pulling from a REST API.
+type restPriceFeed struct {
+	provider     FeedProvider
+	providerName string
+	ticker       string
+	symbol       string
+	interval     time.Duration
+	oracleType   oracletypes.OracleType
+	apiKey       string
+	maxPriceAge  time.Duration
+	client       *http.Client
+
+	logger  log.Logger
+	svcTags metrics.Tags
+}
+
+var restHTTPClient = &http.Client{
+	Timeout: maxRespTime,
+	Transport: &http.Transport{
+		Proxy: http.ProxyFromEnvironment,
+		DialContext: (&net.Dialer{
+			Timeout: 10 * time.Second,
+		}).DialContext,
+		ResponseHeaderTimeout: maxRespHeadersTime,
+	},
+}
+
+// newRestPriceFeed parses the common part of the feed config. If the config has no symbol,
+// defaultSymbol is used, which is the provider-specific symbol derived from the ticker.
+func newRestPriceFeed(provider FeedProvider, cfg *FeedConfig, defaultSymbol string) (*restPriceFeed, error) {
+	pullInterval := 1 * time.Minute
+	if len(cfg.PullInterval) > 0 {
+		interval, err := time.ParseDuration(cfg.PullInterval)
+		if err != nil {
+			err = errors.Wrapf(err, "failed to parse pull interval: %s (expected format: 60s)", cfg.PullInterval)
+			return nil, err
+		}
+
+		if interval < 1*time.Second {
+			err = errors.Errorf("failed to parse pull interval: %s (minimum interval = 1s)", cfg.PullInterval)
+			return nil, err
+		}
+
+		pullInterval = interval
+	}
+
+	oracleType := oracletypes.OracleType_PriceFeed
+	if len(cfg.OracleType) > 0 {
+		tmpType, exist := oracletypes.OracleType_value[cfg.OracleType]
+		if !exist {
+			return nil, fmt.Errorf("oracle type does not exist: %s", cfg.OracleType)
+		}
+
+		oracleType = oracletypes.OracleType(tmpType)
+		if oracleType == oracletypes.OracleType_Stork {
+			return nil, errors.Errorf("oracle type %s is not supported by %s provider", cfg.OracleType, provider)
+		}
+	}
+
+	symbol := cfg.Symbol
+	if len(symbol) == 0 {
+		symbol = defaultSymbol
+	}
+
+	if len(symbol) == 0 {
+		return nil, errors.Errorf("symbol must be set for %s provider", provider)
+	}
+
+	var apiKey string
+	if len(cfg.APIKeyEnv) > 0 {
+		if apiKey = os.Getenv(cfg.APIKeyEnv); len(apiKey) == 0 {
+			return nil, errors.Errorf("API key env variable %s is not set", cfg.APIKeyEnv)
+		}
+	}
+
+	var maxPriceAge time.Duration
+	if len(cfg.MaxPriceAge) > 0 {
+		age, err := time.ParseDuration(cfg.MaxPriceAge)
+		if err != nil || age <= 0 {
+			return nil, errors.Errorf("failed to parse max price age: %s (expected positive duration, e.g. 5m)", cfg.MaxPriceAge)
+		}
+
+		maxPriceAge = age
+	}
+
+	providerName := cfg.ProviderName
+	if len(providerName) == 0 {
+		providerName = provider.String()
+	}
+
+	return &restPriceFeed{
+		provider:     provider,
+		providerName: providerName,
+		ticker:       cfg.Ticker,
+		symbol:       symbol,
+		interval:     pullInterval,
+		oracleType:   oracleType,
+		apiKey:       apiKey,
+		maxPriceAge:  maxPriceAge,
+		client:       restHTTPClient,
+
+		logger: log.WithFields(log.Fields{
+			"svc":      "oracle",
+			"provider": providerName,
+			"ticker":   cfg.Ticker,
+		}),
+
+		svcTags: metrics.Tags{
+			"provider": providerName,
+		},
+	}, nil
+}
+
+func (f *restPriceFeed) Interval() time.Duration {
+	return f.interval
+}
+
+func (f *restPriceFeed) Symbol() string {
+	return f.symbol
+}
+
+func (f *restPriceFeed) Provider() FeedProvider {
+	return f.provider
+}
+
+func (f *restPriceFeed) ProviderName() string {
+	return f.providerName
+}
+
+func (f *restPriceFeed) OracleType() oracletypes.OracleType {
+	return f.oracleType
+}
+
+// priceData wraps the pulled price into PriceData of the feed, validating it first.
+func (f *restPriceFeed) priceData(price decimal.Decimal) (*PriceData, error) {
+	if err := validatePrice(price, f.oracleType); err != nil {
+		return nil, err
+	}
+
+	return &PriceData{
+		Ticker:       Ticker(f.ticker),
+		ProviderName: f.ProviderName(),
+		Symbol:       f.Symbol(),
+		Price:        price,
+		Timestamp:    time.Now(),
+		OracleType:   f.OracleType(),
+	}, nil
+}
+
+// priceDataAt is like priceData, but for prices reported with a provider timestamp,
+// rejecting them if older than the max price age of the feed.
+func (f *restPriceFeed) priceDataAt(price decimal.Decimal, timestamp time.Time) (*PriceData, error) {
+	if f.maxPriceAge > 0 {
+		if age := time.Since(timestamp); age > f.maxPriceAge {
+			return nil, errors.Errorf("price is stale: reported at %s, %s ago (max age %s)",
+				timestamp.UTC().Format(time.RFC3339), age.Truncate(time.Second), f.maxPriceAge)
+		}
+	}
+
+	return f.priceData(price)
+}
+
+// httpStatusError is returned when the provider API responds with a non-2xx status.
+type httpStatusError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *httpStatusError) Error() string {
+	return fmt.Sprintf("unexpected response status %d: %s", e.StatusCode, e.Body)
+}
+
+// getJSON sends a GET request and decodes the JSON response into v. Non-2xx responses
+// are returned as *httpStatusError, so providers can handle specific statuses.
+func (f *restPriceFeed) getJSON(ctx context.Context, url string, header http.Header, v interface{}) error {
+	return f.doJSON(ctx, http.MethodGet, url, header, nil, v)
+}
+
+// postJSON sends a POST request with body encoded as JSON and decodes the JSON response into v,
+// handling errors the same way as getJSON.
+func (f *restPriceFeed) postJSON(ctx context.Context, url string, header http.Header, body, v interface{}) error {
+	return f.doJSON(ctx, http.MethodPost, url, header, body, v)
+}
+
+func (f *restPriceFeed) doJSON(ctx context.Context, method, url string, header http.Header, reqBody, v interface{}) error {
+	metrics.ReportFuncCall(f.svcTags)
+	doneFn := metrics.ReportFuncTiming(f.svcTags)
+	defer doneFn()
+
+	var bodyReader io.Reader
+	if reqBody != nil {
+		data, err := json.Marshal(reqBody)
+		if err != nil {
+			return errors.Wrap(err, "failed to marshal request body")
+		}
+
+		bodyReader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, url, bodyReader)
+	if err != nil {
+		return errors.Wrap(err, "failed to create request")
+	}
+
+	for key, values := range header {
+		req.Header[key] = values
+	}
+	req.Header.Set("Accept", "application/json")
+	if reqBody != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		metrics.ReportFuncError(f.svcTags)
+		return errors.Wrapf(err, "failed to %s %s", method, url)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxRespBytes))
+	if err != nil {
+		metrics.ReportFuncError(f.svcTags)
+		return errors.Wrap(err, "failed to read response body")
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		metrics.ReportFuncError(f.svcTags)
+		return &httpStatusError{
+			StatusCode: resp.StatusCode,
+			Body:       strings.TrimSpace(string(body)),
+		}
+	}
+
+	if err := json.Unmarshal(body, v); err != nil {
+		metrics.ReportFuncError(f.svcTags)
+		return errors.Wrap(err, "failed to unmarshal response body")
+	}
+
+	return nil
+}
@@ -0,0 +1,632 @@
+package oracle
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// restFeedTestCase is a native feed pulling from a mock API responding with body.
+type restFeedTestCase struct {
+	name     string
+	cfg      *FeedConfig
+	newFeed  func(cfg *FeedConfig, baseURL string) (PricePuller, error)
+	path     string
+	status   int
+	body     string
+	expected string
+	wantErr  bool
+	wantSkip bool
+}
+
+func runRestFeedTestCases(t *testing.T, cases []restFeedTestCase) {
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var gotPath string
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				gotPath = r.URL.RequestURI()
+
+				status := tc.status
+				if status == 0 {
+					status = http.StatusOK
+				}
+
+				w.WriteHeader(status)
+				_, _ = w.Write([]byte(tc.body))
+			}))
+			defer srv.Close()
+
+			feed, err := tc.newFeed(tc.cfg, srv.URL)
+			if err != nil {
+				t.Fatalf("failed to init feed: %v", err)
+			}
+
+			priceData, err := feed.PullPrice(context.Background())
+			if tc.path != "" && gotPath != tc.path {
+				t.Errorf("expected request to %s, got %s", tc.path, gotPath)
+			}
+
+			if tc.wantSkip {
+				if err != nil || priceData != nil {
+					t.Fatalf("expected pull to be skipped, got %v, %v", priceData, err)
+				}
+				return
+			}
+
+			if tc.wantErr {
+				if err == nil {
+					t.Fatalf("expected error, got price %s", priceData.Price)
+				}
+				return
+			} else if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if priceData.Price.String() != tc.expected {
+				t.Errorf("expected price %s, got %s", tc.expected, priceData.Price)
+			}
+		})
+	}
+}
+
+func newTestGateioFeed(cfg *FeedConfig, baseURL string) (PricePuller, error) {
+	feed, err := NewGateioPriceFeed(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	feed.(*gateioPriceFeed).baseURL = baseURL
+	return feed, nil
+}
+
+func TestBinancePriceFeedRegion(t *testing.T) {
+	tests := []struct {
+		name            string
+		cfg             *FeedConfig
+		expectedBaseURL string
+		wantErr         bool
+	}{
+		{
+			name:            "Global spot",
+			cfg:             &FeedConfig{Ticker: "BTC/USDT"},
+			expectedBaseURL: binanceSpotBaseURL,
+		},
+		{
+			name:            "US spot",
+			cfg:             &FeedConfig{Ticker: "BTC/USD", Region: BinanceRegionUS},
+			expectedBaseURL: binanceUSSpotBaseURL,
+		},
+		{
+			name:    "US futures",
+			cfg:     &FeedConfig{Ticker: "BTC/USDT", Market: BinanceMarketFutures, Region: BinanceRegionUS},
+			wantErr: true,
+		},
+		{
+			name:    "Unknown region",
+			cfg:     &FeedConfig{Ticker: "BTC/USDT", Region: "eu"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			feed, err := NewBinancePriceFeed(tt.cfg)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("expected error")
+				}
+				return
+			} else if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if baseURL := feed.(*binancePriceFeed).baseURL; baseURL != tt.expectedBaseURL {
+				t.Errorf("expected base URL %s, got %s", tt.expectedBaseURL, baseURL)
+			}
+		})
+	}
+
+	cfg := &FeedConfig{ProviderName: "binance"}
+	cfg.SetDefaultRegion(map[string]string{"binance": BinanceRegionUS})
+	if cfg.Region != BinanceRegionUS {
+		t.Errorf("expected default region %s, got %q", BinanceRegionUS, cfg.Region)
+	}
+}
+
+func TestGateioPriceFeed(t *testing.T) {
+	runRestFeedTestCases(t, []restFeedTestCase{{
+		name:     "last price",
+		cfg:      &FeedConfig{ProviderName: "gateio", Ticker: "BTC/USDT"},
+		newFeed:  newTestGateioFeed,
+		path:     "/api/v4/spot/tickers?currency_pair=BTC_USDT",
+		body:     `[{"currency_pair":"BTC_USDT","last":"64123.5"}]`,
+		expected: "64123.5",
+	}, {
+		name:     "symbol override",
+		cfg:      &FeedConfig{ProviderName: "gateio", Ticker: "INJ/USD", Symbol: "INJ_USDT"},
+		newFeed:  newTestGateioFeed,
+		path:     "/api/v4/spot/tickers?currency_pair=INJ_USDT",
+		body:     `[{"currency_pair":"INJ_USDT","last":"25.1"}]`,
+		expected: "25.1",
+	}, {
+		name:    "unknown pair",
+		cfg:     &FeedConfig{ProviderName: "gateio", Ticker: "FOO/BAR"},
+		newFeed: newTestGateioFeed,
+		body:    `[]`,
+		wantErr: true,
+	}, {
+		name:    "error status",
+		cfg:     &FeedConfig{ProviderName: "gateio", Ticker: "FOO/BAR"},
+		newFeed: newTestGateioFeed,
+		status:  http.StatusBadRequest,
+		body:    `{"label":"INVALID_CURRENCY","message":"Invalid currency"}`,
+		wantErr: true,
+	}})
+}
+
+func newTestKucoinFeed(cfg *FeedConfig, baseURL string) (PricePuller, error) {
+	feed, err := NewKucoinPriceFeed(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	feed.(*kucoinPriceFeed).baseURL = baseURL
+	return feed, nil
+}
+
+func TestKucoinPriceFeed(t *testing.T) {
+	runRestFeedTestCases(t, []restFeedTestCase{{
+		name:     "last price",
+		cfg:      &FeedConfig{ProviderName: "kucoin", Ticker: "BTC/USDT"},
+		newFeed:  newTestKucoinFeed,
+		path:     "/api/v1/market/orderbook/level1?symbol=BTC-USDT",
+		body:     `{"code":"200000","data":{"time":1700000000000,"price":"64123.5"}}`,
+		expected: "64123.5",
+	}, {
+		name:    "invalid symbol",
+		cfg:     &FeedConfig{ProviderName: "kucoin", Ticker: "FOO/BAR"},
+		newFeed: newTestKucoinFeed,
+		body:    `{"code":"200000","data":null}`,
+		wantErr: true,
+	}, {
+		name:    "error code",
+		cfg:     &FeedConfig{ProviderName: "kucoin", Ticker: "BTC/USDT"},
+		newFeed: newTestKucoinFeed,
+		body:    `{"code":"400100","msg":"Invalid request"}`,
+		wantErr: true,
+	}})
+}
+
+func newTestHTXFeed(cfg *FeedConfig, baseURL string) (PricePuller, error) {
+	feed, err := NewHTXPriceFeed(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	feed.(*htxPriceFeed).baseURL = baseURL
+	return feed, nil
+}
+
+func TestHTXPriceFeed(t *testing.T) {
+	runRestFeedTestCases(t, []restFeedTestCase{{
+		name:     "close price",
+		cfg:      &FeedConfig{ProviderName: "htx", Ticker: "BTC/USDT"},
+		newFeed:  newTestHTXFeed,
+		path:     "/market/detail/merged?symbol=btcusdt",
+		body:     `{"ch":"market.btcusdt.detail.merged","status":"ok","tick":{"close":64123.5,"open":63000}}`,
+		expected: "64123.5",
+	}, {
+		name:     "symbol override is lowercased",
+		cfg:      &FeedConfig{ProviderName: "htx", Ticker: "INJ/USD", Symbol: "INJUSDT"},
+		newFeed:  newTestHTXFeed,
+		path:     "/market/detail/merged?symbol=injusdt",
+		body:     `{"status":"ok","tick":{"close":25.1}}`,
+		expected: "25.1",
+	}, {
+		name:    "invalid symbol",
+		cfg:     &FeedConfig{ProviderName: "htx", Ticker: "FOO/BAR"},
+		newFeed: newTestHTXFeed,
+		body:    `{"status":"error","err-code":"invalid-parameter","err-msg":"invalid symbol"}`,
+		wantErr: true,
+	}})
+}
+
+func newTestBitfinexFeed(cfg *FeedConfig, baseURL string) (PricePuller, error) {
+	feed, err := NewBitfinexPriceFeed(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	feed.(*bitfinexPriceFeed).baseURL = baseURL
+	return feed, nil
+}
+
+func TestBitfinexPriceFeed(t *testing.T) {
+	runRestFeedTestCases(t, []restFeedTestCase{{
+		name:     "last price",
+		cfg:      &FeedConfig{ProviderName: "bitfinex", Ticker: "BTC/USD"},
+		newFeed:  newTestBitfinexFeed,
+		path:     "/v2/ticker/tBTCUSD",
+		body:     `[64120,10.5,64125,8.2,-500,-0.0077,64123.5,1200.3,65000,63000]`,
+		expected: "64123.5",
+	}, {
+		name:     "symbol override gets prefixed",
+		cfg:      &FeedConfig{ProviderName: "bitfinex", Ticker: "INJ/USD", Symbol: "INJUSD"},
+		newFeed:  newTestBitfinexFeed,
+		path:     "/v2/ticker/tINJUSD",
+		body:     `[25,1,25.2,1,0,0,25.1,100,26,24]`,
+		expected: "25.1",
+	}, {
+		name:    "error array",
+		cfg:     &FeedConfig{ProviderName: "bitfinex", Ticker: "FOO/BAR"},
+		newFeed: newTestBitfinexFeed,
+		status:  http.StatusInternalServerError,
+		body:    `["error",10020,"symbol: invalid"]`,
+		wantErr: true,
+	}, {
+		name:    "empty array",
+		cfg:     &FeedConfig{ProviderName: "bitfinex", Ticker: "FOO/BAR"},
+		newFeed: newTestBitfinexFeed,
+		body:    `[]`,
+		wantErr: true,
+	}})
+}
+
+func TestParseBitfinexError(t *testing.T) {
+	err := parseBitfinexError([]byte(`["error",10020,"symbol: invalid"]`))
+	if err == nil || err.Error() != "Bitfinex API error 10020: symbol: invalid" {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	if err := parseBitfinexError([]byte(`[1,2,3]`)); err != nil {
+		t.Errorf("expected no error for a non-error array, got %v", err)
+	}
+}
+
+func newTestDeribitFeed(cfg *FeedConfig, baseURL string) (PricePuller, error) {
+	feed, err := NewDeribitPriceFeed(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	feed.(*deribitPriceFeed).baseURL = baseURL
+	return feed, nil
+}
+
+func TestDeribitPriceFeed(t *testing.T) {
+	runRestFeedTestCases(t, []restFeedTestCase{{
+		name:     "index price",
+		cfg:      &FeedConfig{ProviderName: "deribit", Ticker: "BTC/USD"},
+		newFeed:  newTestDeribitFeed,
+		path:     "/api/v2/public/get_index_price?index_name=btc_usd",
+		body:     `{"jsonrpc":"2.0","result":{"index_price":64123.5,"estimated_delivery_price":64123.5}}`,
+		expected: "64123.5",
+	}, {
+		name:     "index name from symbol",
+		cfg:      &FeedConfig{ProviderName: "deribit", Ticker: "ETH/USD", Symbol: "eth_usdc"},
+		newFeed:  newTestDeribitFeed,
+		path:     "/api/v2/public/get_index_price?index_name=eth_usdc",
+		body:     `{"jsonrpc":"2.0","result":{"index_price":3100.25}}`,
+		expected: "3100.25",
+	}, {
+		name:    "JSON-RPC error",
+		cfg:     &FeedConfig{ProviderName: "deribit", Ticker: "FOO/BAR"},
+		newFeed: newTestDeribitFeed,
+		status:  http.StatusBadRequest,
+		body:    `{"jsonrpc":"2.0","error":{"code":-32602,"message":"Invalid params","data":{"param":"index_name"}}}`,
+		wantErr: true,
+	}})
+}
+
+func newTestDIAFeed(cfg *FeedConfig, baseURL string) (PricePuller, error) {
+	feed, err := NewDIAPriceFeed(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	feed.(*diaPriceFeed).baseURL = baseURL
+	return feed, nil
+}
+
+func TestDIAPriceFeed(t *testing.T) {
+	const usdc = "Ethereum:0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48"
+
+	runRestFeedTestCases(t, []restFeedTestCase{{
+		name:     "asset quotation",
+		cfg:      &FeedConfig{ProviderName: "dia", Ticker: "USDC/USD", Symbol: usdc},
+		newFeed:  newTestDIAFeed,
+		path:     "/v1/assetQuotation/Ethereum/0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48",
+		body:     `{"Symbol":"USDC","Name":"USD Coin","Price":0.99995,"Time":"2024-01-01T00:00:00Z"}`,
+		expected: "0.99995",
+	}, {
+		name:    "unknown asset",
+		cfg:     &FeedConfig{ProviderName: "dia", Ticker: "FOO/USD", Symbol: "Ethereum:0x0"},
+		newFeed: newTestDIAFeed,
+		status:  http.StatusNotFound,
+		body:    `{"error":"not found"}`,
+		wantErr: true,
+	}})
+
+	for _, symbol := range []string{"", "Ethereum", "Ethereum:", ":0x0"} {
+		if _, err := NewDIAPriceFeed(&FeedConfig{ProviderName: "dia", Ticker: "FOO/USD", Symbol: symbol}); err == nil {
+			t.Errorf("expected error for symbol %q", symbol)
+		}
+	}
+}
+
+func newTestRedstoneFeed(cfg *FeedConfig, baseURL string) (PricePuller, error) {
+	feed, err := NewRedstonePriceFeed(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	feed.(*redstonePriceFeed).baseURL = baseURL
+	return feed, nil
+}
+
+func TestRedstonePriceFeed(t *testing.T) {
+	now := time.Now().UnixMilli()
+	stale := time.Now().Add(-time.Hour).UnixMilli()
+
+	runRestFeedTestCases(t, []restFeedTestCase{{
+		name:     "latest price",
+		cfg:      &FeedConfig{ProviderName: "redstone", Ticker: "BTC/USD", MaxPriceAge: "5m"},
+		newFeed:  newTestRedstoneFeed,
+		path:     "/prices?limit=1&provider=redstone&symbol=BTC",
+		body:     fmt.Sprintf(`[{"symbol":"BTC","value":64123.5,"timestamp":%d}]`, now),
+		expected: "64123.5",
+	}, {
+		name:     "stale price without max age",
+		cfg:      &FeedConfig{ProviderName: "redstone", Ticker: "BTC/USD"},
+		newFeed:  newTestRedstoneFeed,
+		body:     fmt.Sprintf(`[{"symbol":"BTC","value":64123.5,"timestamp":%d}]`, stale),
+		expected: "64123.5",
+	}, {
+		name:    "stale price",
+		cfg:     &FeedConfig{ProviderName: "redstone", Ticker: "BTC/USD", MaxPriceAge: "5m"},
+		newFeed: newTestRedstoneFeed,
+		body:    fmt.Sprintf(`[{"symbol":"BTC","value":64123.5,"timestamp":%d}]`, stale),
+		wantErr: true,
+	}, {
+		name:    "unknown symbol",
+		cfg:     &FeedConfig{ProviderName: "redstone", Ticker: "FOO/USD"},
+		newFeed: newTestRedstoneFeed,
+		body:    `[]`,
+		wantErr: true,
+	}})
+}
+
+func TestRedstonePriceFeedAPIKey(t *testing.T) {
+	var gotKey string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotKey = r.Header.Get(redstoneAPIKeyHeader)
+		_, _ = fmt.Fprintf(w, `[{"symbol":"BTC","value":1,"timestamp":%d}]`, time.Now().UnixMilli())
+	}))
+	defer srv.Close()
+
+	cfg := &FeedConfig{ProviderName: "redstone", Ticker: "BTC/USD", APIKeyEnv: "TEST_REDSTONE_API_KEY"}
+	if _, err := NewRedstonePriceFeed(cfg); err == nil {
+		t.Fatalf("expected error for unset API key env")
+	}
+
+	t.Setenv("TEST_REDSTONE_API_KEY", "secret")
+
+	feed, err := newTestRedstoneFeed(cfg, srv.URL)
+	if err != nil {
+		t.Fatalf("failed to init feed: %v", err)
+	}
+
+	if _, err := feed.PullPrice(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if gotKey != "secret" {
+		t.Errorf("expected API key header to be sent, got %q", gotKey)
+	}
+}
+
+func newTestAPI3Feed(cfg *FeedConfig, baseURL string) (PricePuller, error) {
+	cfg.RPCEndpoint = baseURL
+	return NewAPI3PriceFeed(cfg)
+}
+
+// api3ReadResult ABI-encodes (int224 value, uint32 timestamp) as returned by read() of a dAPI proxy.
+func api3ReadResult(value string, timestamp int64) string {
+	return fmt.Sprintf(`{"jsonrpc":"2.0","id":1,"result":"0x%064s%064x"}`, value, timestamp)
+}
+
+func TestAPI3PriceFeed(t *testing.T) {
+	const proxy = "0x5b0cf2b36a65a6BB085D501B971e4c102B9Cd473"
+
+	now := time.Now().Unix()
+
+	runRestFeedTestCases(t, []restFeedTestCase{{
+		name:    "dAPI value",
+		cfg:     &FeedConfig{ProviderName: "api3", Ticker: "ETH/USD", Symbol: proxy},
+		newFeed: newTestAPI3Feed,
+		// 3100.25 * 1e18
+		body:     api3ReadResult("a8109c952be8c90000", now),
+		expected: "3100.25",
+	}, {
+		name:    "stale value",
+		cfg:     &FeedConfig{ProviderName: "api3", Ticker: "ETH/USD", Symbol: proxy, MaxPriceAge: "1h"},
+		newFeed: newTestAPI3Feed,
+		body:    api3ReadResult("a8109c952be8c90000", now-7200),
+		wantErr: true,
+	}, {
+		name:    "eth_call error",
+		cfg:     &FeedConfig{ProviderName: "api3", Ticker: "ETH/USD", Symbol: proxy},
+		newFeed: newTestAPI3Feed,
+		body:    `{"jsonrpc":"2.0","id":1,"error":{"code":-32000,"message":"execution reverted"}}`,
+		wantErr: true,
+	}})
+
+	if _, err := NewAPI3PriceFeed(&FeedConfig{ProviderName: "api3", Ticker: "ETH/USD", Symbol: "ETH", RPCEndpoint: "http://localhost"}); err == nil {
+		t.Errorf("expected error for non-address symbol")
+	}
+
+	if _, err := NewAPI3PriceFeed(&FeedConfig{ProviderName: "api3", Ticker: "ETH/USD", Symbol: proxy}); err == nil {
+		t.Errorf("expected error for missing rpcEndpoint")
+	}
+}
+
+func TestDecodeAPI3Read(t *testing.T) {
+	// -1e18 sign-extended to 256 bits
+	value, timestamp, err := decodeAPI3Read("0xfffffffffffffffffffffffffffffffffffffffffffffffff21f494c589c0000" +
+		"0000000000000000000000000000000000000000000000000000000065920080")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if value.String() != "-1" {
+		t.Errorf("expected value -1, got %s", value)
+	}
+
+	if timestamp.Unix() != 1704067200 {
+		t.Errorf("expected timestamp 1704067200, got %d", timestamp.Unix())
+	}
+
+	if _, _, err := decodeAPI3Read("0x"); err == nil {
+		t.Errorf("expected error for empty result")
+	}
+}
+
+func newTestSwitchboardFeed(cfg *FeedConfig, baseURL string) (PricePuller, error) {
+	feed, err := NewSwitchboardPriceFeed(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	feed.(*switchboardPriceFeed).baseURL = baseURL
+	return feed, nil
+}
+
+func TestSwitchboardPriceFeed(t *testing.T) {
+	const feedHash = "0x4cd1cad962425681af07b9254b7d804de3ca3446fbfd1371bb258d2c75059812"
+
+	now := time.Now().Unix()
+
+	runRestFeedTestCases(t, []restFeedTestCase{{
+		name:     "median of results",
+		cfg:      &FeedConfig{ProviderName: "switchboard", Ticker: "BTC/USD", Symbol: feedHash},
+		newFeed:  newTestSwitchboardFeed,
+		path:     "/simulate/" + feedHash,
+		body:     fmt.Sprintf(`[{"feedHash":"%s","results":["64120","64123.5","64130"]}]`, feedHash[2:]),
+		expected: "64123.5",
+	}, {
+		name:     "fresh timestamp",
+		cfg:      &FeedConfig{ProviderName: "switchboard", Ticker: "BTC/USD", Symbol: feedHash, MaxPriceAge: "1m"},
+		newFeed:  newTestSwitchboardFeed,
+		body:     fmt.Sprintf(`[{"feedHash":"%s","results":[64120, 64130],"timestamp":%d}]`, feedHash, now),
+		expected: "64125",
+	}, {
+		name:    "stale timestamp",
+		cfg:     &FeedConfig{ProviderName: "switchboard", Ticker: "BTC/USD", Symbol: feedHash, MaxPriceAge: "1m"},
+		newFeed: newTestSwitchboardFeed,
+		body:    fmt.Sprintf(`[{"feedHash":"%s","results":[64120],"timestamp":%d}]`, feedHash, now-3600),
+		wantErr: true,
+	}, {
+		name:    "no results",
+		cfg:     &FeedConfig{ProviderName: "switchboard", Ticker: "BTC/USD", Symbol: feedHash},
+		newFeed: newTestSwitchboardFeed,
+		body:    fmt.Sprintf(`[{"feedHash":"%s","results":[]}]`, feedHash),
+		wantErr: true,
+	}})
+}
+
+func newTestJupiterFeed(cfg *FeedConfig, baseURL string) (PricePuller, error) {
+	feed, err := NewJupiterPriceFeed(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	feed.(*jupiterPriceFeed).baseURL = baseURL
+	return feed, nil
+}
+
+func TestJupiterPriceFeed(t *testing.T) {
+	const mint = "JUPyiwrYJFskUPiHa7hkeR8VUtAeFoSYbKedZNsDvCN"
+
+	runRestFeedTestCases(t, []restFeedTestCase{{
+		name:     "mint price",
+		cfg:      &FeedConfig{ProviderName: "jupiter", Ticker: "JUP/USDC", Symbol: mint},
+		newFeed:  newTestJupiterFeed,
+		path:     "/v6/price?ids=" + mint,
+		body:     `{"data":{"` + mint + `":{"id":"` + mint + `","mintSymbol":"JUP","price":0.8123}},"timeTaken":0.001}`,
+		expected: "0.8123",
+	}, {
+		name:     "unpriced mint",
+		cfg:      &FeedConfig{ProviderName: "jupiter", Ticker: "FOO/USDC", Symbol: mint},
+		newFeed:  newTestJupiterFeed,
+		body:     `{"data":{},"timeTaken":0.001}`,
+		wantSkip: true,
+	}})
+}
+
+func newTestBinanceFeed(cfg *FeedConfig, baseURL string) (PricePuller, error) {
+	feed, err := NewBinancePriceFeed(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	feed.(*binancePriceFeed).baseURL = baseURL
+	return feed, nil
+}
+
+func TestBinancePriceFeed(t *testing.T) {
+	runRestFeedTestCases(t, []restFeedTestCase{{
+		name:     "spot price",
+		cfg:      &FeedConfig{ProviderName: "binance", Ticker: "BTC/USDT"},
+		newFeed:  newTestBinanceFeed,
+		path:     "/api/v3/ticker/price?symbol=BTCUSDT",
+		body:     `{"symbol":"BTCUSDT","price":"64123.50000000"}`,
+		expected: "64123.5",
+	}, {
+		name:     "futures mark price",
+		cfg:      &FeedConfig{ProviderName: "binance", Ticker: "BTC/USDT", Market: "futures"},
+		newFeed:  newTestBinanceFeed,
+		path:     "/fapi/v1/premiumIndex?symbol=BTCUSDT",
+		body:     `{"symbol":"BTCUSDT","markPrice":"64110.12000000","indexPrice":"64100.00000000","lastFundingRate":"0.0001"}`,
+		expected: "64110.12",
+	}, {
+		name:    "invalid symbol",
+		cfg:     &FeedConfig{ProviderName: "binance", Ticker: "FOO/BAR"},
+		newFeed: newTestBinanceFeed,
+		status:  http.StatusBadRequest,
+		body:    `{"code":-1121,"msg":"Invalid symbol."}`,
+		wantErr: true,
+	}})
+
+	if _, err := NewBinancePriceFeed(&FeedConfig{ProviderName: "binance", Ticker: "BTC/USDT", Market: "margin"}); err == nil {
+		t.Errorf("expected error for unsupported market")
+	}
+}
+
+func TestConstantPriceFeed(t *testing.T) {
+	feed, err := NewPricePuller(&FeedConfig{
+		ProviderName: "constant",
+		Ticker:       "USDC/USD",
+		Value:        "1",
+	}, nil)
+	if err != nil {
+		t.Fatalf("failed to init feed: %v", err)
+	} else if feed.Provider() != FeedProviderConstant {
+		t.Fatalf("expected %s provider, got %s", FeedProviderConstant, feed.Provider())
+	}
+
+	priceData, err := feed.PullPrice(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if priceData.Price.String() != "1" {
+		t.Errorf("expected price 1, got %s", priceData.Price)
+	}
+
+	for _, value := range []string{"", "one", "0", "-1"} {
+		if _, err := NewConstantPriceFeed(&FeedConfig{Ticker: "USDC/USD", Value: value}); err == nil {
+			t.Errorf("expected error for value %q", value)
+		}
+	}
+}
@@ -3,7 +3,9 @@ package oracle
 import (
 	"context"
 	"fmt"
+	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/InjectiveLabs/metrics"
@@ -16,6 +18,12 @@ import (
 
 var _ PricePuller = &storkPriceFeed{}
 
+// assetPairReleaser is implemented by feeds skipping signed asset pairs they've already handed over,
+// so pairs that are not submitted can be handed over again.
+type assetPairReleaser interface {
+	releaseAssetPair(pair *oracletypes.AssetPair)
+}
+
 type storkPriceFeed struct {
 	storkFetcher StorkFetcher
 	providerName string
@@ -23,6 +31,17 @@ type storkPriceFeed struct {
 	tickers      []string
 	interval     time.Duration
 
+	// maxSignedPrices caps the signed prices submitted per asset pair, zero means no limit.
+	maxSignedPrices int
+
+	// lastPair is the last asset pair handed over for submission, used to skip re-submitting the very same
+	// signed prices. lastSent is the handed over pair, truncated to maxSignedPrices. Both are cleared if it's
+	// not submitted, skipped before broadcast or its broadcast failed, so the same signed prices are submitted
+	// again on the next pull.
+	lastPair   *oracletypes.AssetPair
+	lastSent   *oracletypes.AssetPair
+	lastPairMu sync.Mutex
+
 	logger  log.Logger
 	svcTags metrics.Tags
 
@@ -50,13 +69,17 @@ func NewStorkPriceFeed(storkFetcher StorkFetcher, cfg *FeedConfig) (PricePuller,
 		}
 
 		if interval < time.Second {
-			err = errors.Wrapf(err, "failed to parse pull interval: %s (minimum interval = 30s)", cfg.PullInterval)
+			err = errors.Errorf("failed to parse pull interval: %s (minimum interval = 1s)", cfg.PullInterval)
 			return nil, err
 		}
 
 		pullInterval = interval
 	}
 
+	if cfg.MaxSignedPrices < 0 {
+		return nil, errors.Errorf("max signed prices cannot be negative: %d", cfg.MaxSignedPrices)
+	}
+
 	var oracleType oracletypes.OracleType
 	if cfg.OracleType == "" {
 		oracleType = oracletypes.OracleType_Stork
@@ -76,6 +99,8 @@ func NewStorkPriceFeed(storkFetcher StorkFetcher, cfg *FeedConfig) (PricePuller,
 		interval:     pullInterval,
 		oracleType:   oracleType,
 
+		maxSignedPrices: cfg.MaxSignedPrices,
+
 		logger: log.WithFields(log.Fields{
 			"svc":      "oracle",
 			"dynamic":  true,
@@ -123,6 +148,27 @@ func (f *storkPriceFeed) PullPrice(_ context.Context) (
 		return nil, nil
 	}
 
+	f.lastPairMu.Lock()
+	defer f.lastPairMu.Unlock()
+
+	if !assetPairChanged(f.lastPair, pair) {
+		f.logger.WithField("ticker", f.ticker).Debugln("asset pair has not changed since last pull, skipping")
+		return nil, nil
+	}
+	f.lastPair = pair
+
+	if f.maxSignedPrices > 0 && len(pair.SignedPrices) > f.maxSignedPrices {
+		f.logger.WithFields(log.Fields{
+			"ticker":        f.ticker,
+			"signed_prices": len(pair.SignedPrices),
+			"max":           f.maxSignedPrices,
+		}).Infoln("truncating signed prices of asset pair")
+
+		pair = truncateSignedPrices(pair, f.maxSignedPrices)
+	}
+
+	f.lastSent = pair
+
 	return &PriceData{
 		Ticker:       Ticker(f.ticker),
 		ProviderName: f.ProviderName(),
@@ -133,6 +179,54 @@ func (f *storkPriceFeed) PullPrice(_ context.Context) (
 	}, nil
 }
 
+// releaseAssetPair forgets the asset pair handed over for submission if it's not submitted,
+// unless a newer pair has been handed over since.
+func (f *storkPriceFeed) releaseAssetPair(pair *oracletypes.AssetPair) {
+	f.lastPairMu.Lock()
+	defer f.lastPairMu.Unlock()
+
+	if f.lastSent == pair {
+		f.lastPair = nil
+		f.lastSent = nil
+	}
+}
+
+// assetPairChanged reports whether next asset pair carries different signed prices than prev.
+func assetPairChanged(prev, next *oracletypes.AssetPair) bool {
+	if prev == nil || next == nil {
+		return prev != next
+	}
+
+	if prev.AssetId != next.AssetId || len(prev.SignedPrices) != len(next.SignedPrices) {
+		return true
+	}
+
+	for i := range next.SignedPrices {
+		p, n := prev.SignedPrices[i], next.SignedPrices[i]
+		if p.PublisherKey != n.PublisherKey || p.Timestamp != n.Timestamp || !p.Price.Equal(n.Price) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// truncateSignedPrices returns a copy of the asset pair with at most max signed prices, keeping the
+// freshest ones. Signed prices of the same timestamp keep their order, which is the publisher priority.
+func truncateSignedPrices(pair *oracletypes.AssetPair, max int) *oracletypes.AssetPair {
+	signedPrices := make([]*oracletypes.SignedPriceOfAssetPair, len(pair.SignedPrices))
+	copy(signedPrices, pair.SignedPrices)
+
+	sort.SliceStable(signedPrices, func(i, j int) bool {
+		return signedPrices[i].Timestamp > signedPrices[j].Timestamp
+	})
+
+	return &oracletypes.AssetPair{
+		AssetId:      pair.AssetId,
+		SignedPrices: signedPrices[:max],
+	}
+}
+
 // ConvertDataToAssetPair converts data get from websocket to list of asset pairs
 func ConvertDataToAssetPair(data Data, assetId string, refTimestamp uint64) (result oracletypes.AssetPair) {
 	var signedPricesOfAssetPair []*oracletypes.SignedPriceOfAssetPair
@@ -164,16 +258,20 @@ func ConvertSignedPrice(signeds SignedPrice) oracletypes.SignedPriceOfAssetPair
 	return signedPriceOfAssetPair
 }
 
+// ConvertTimestampToSecond normalizes a unix timestamp in seconds, milliseconds,
+// microseconds or nanoseconds to seconds, detecting the unit by its magnitude.
+// Second-precision timestamps stay below 1e12 until year 33658, so every unit
+// is identified by the number of digits it has.
 func ConvertTimestampToSecond(timestamp uint64) uint64 {
 	switch {
 	// nanosecond
-	case timestamp > 1e18:
+	case timestamp >= 1e18:
 		return timestamp / uint64(1_000_000_000)
 	// microsecond
-	case timestamp > 1e15:
+	case timestamp >= 1e15:
 		return timestamp / uint64(1_000_000)
 	// millisecond
-	case timestamp > 1e12:
+	case timestamp >= 1e12:
 		return timestamp / uint64(1_000)
 	// second
 	default:
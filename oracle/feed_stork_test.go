@@ -1,7 +1,18 @@
 package oracle
 
 import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
+
+	cosmtypes "github.com/cosmos/cosmos-sdk/types"
+	"github.com/gorilla/websocket"
+	"github.com/pkg/errors"
+
+	oracletypes "github.com/InjectiveLabs/sdk-go/chain/oracle/types"
 )
 
 func TestConvertTimestampToSecond(t *testing.T) {
@@ -30,6 +41,46 @@ func TestConvertTimestampToSecond(t *testing.T) {
 			timestamp: 1738013701534503470, // nanoseconds
 			expected:  1738013701,
 		},
+		{
+			name:      "Keep seconds just below milliseconds boundary",
+			timestamp: 999_999_999_999, // seconds
+			expected:  999_999_999_999,
+		},
+		{
+			name:      "Convert milliseconds at boundary to seconds",
+			timestamp: 1_000_000_000_000, // milliseconds
+			expected:  1_000_000_000,
+		},
+		{
+			name:      "Convert milliseconds just below microseconds boundary",
+			timestamp: 999_999_999_999_999, // milliseconds
+			expected:  999_999_999_999,
+		},
+		{
+			name:      "Convert microseconds at boundary to seconds",
+			timestamp: 1_000_000_000_000_000, // microseconds
+			expected:  1_000_000_000,
+		},
+		{
+			name:      "Convert microseconds just below nanoseconds boundary",
+			timestamp: 999_999_999_999_999_999, // microseconds
+			expected:  999_999_999_999,
+		},
+		{
+			name:      "Convert nanoseconds at boundary to seconds",
+			timestamp: 1_000_000_000_000_000_000, // nanoseconds
+			expected:  1_000_000_000,
+		},
+		{
+			name:      "Keep seconds as is",
+			timestamp: 1738013701, // seconds
+			expected:  1738013701,
+		},
+		{
+			name:      "Convert milliseconds to seconds",
+			timestamp: 1738013701534, // milliseconds
+			expected:  1738013701,
+		},
 	}
 
 	for _, tt := range tests {
@@ -41,3 +92,255 @@ func TestConvertTimestampToSecond(t *testing.T) {
 		})
 	}
 }
+
+type staticStorkFetcher struct {
+	pair *oracletypes.AssetPair
+}
+
+func (f *staticStorkFetcher) Start(context.Context, *websocket.Conn) error { return nil }
+
+func (f *staticStorkFetcher) AssetPair(string) *oracletypes.AssetPair { return f.pair }
+
+func (f *staticStorkFetcher) Close() {}
+
+func TestStorkPriceFeedMaxSignedPrices(t *testing.T) {
+	pair := &oracletypes.AssetPair{
+		AssetId: "BTCUSD",
+		SignedPrices: []*oracletypes.SignedPriceOfAssetPair{
+			{PublisherKey: "0x1", Timestamp: 100},
+			{PublisherKey: "0x2", Timestamp: 101},
+			{PublisherKey: "0x3", Timestamp: 100},
+			{PublisherKey: "0x4", Timestamp: 101},
+		},
+	}
+
+	feed, err := NewStorkPriceFeed(&staticStorkFetcher{pair: pair}, &FeedConfig{
+		ProviderName:    "stork",
+		Ticker:          "BTCUSD",
+		MaxSignedPrices: 3,
+	})
+	if err != nil {
+		t.Fatalf("failed to init feed: %v", err)
+	}
+
+	priceData, err := feed.PullPrice(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var publishers []string
+	for _, signedPrice := range priceData.AssetPair.SignedPrices {
+		publishers = append(publishers, signedPrice.PublisherKey)
+	}
+
+	if expected := []string{"0x2", "0x4", "0x1"}; len(publishers) != len(expected) ||
+		publishers[0] != expected[0] || publishers[1] != expected[1] || publishers[2] != expected[2] {
+		t.Errorf("expected publishers %v, got %v", expected, publishers)
+	}
+
+	if len(pair.SignedPrices) != 4 || pair.SignedPrices[0].PublisherKey != "0x1" {
+		t.Error("expected the fetched asset pair to be left intact")
+	}
+
+	// the same asset pair must not be submitted again, despite being truncated
+	if priceData, err := feed.PullPrice(context.Background()); err != nil || priceData != nil {
+		t.Errorf("expected unchanged asset pair to be skipped, got %+v, %v", priceData, err)
+	}
+}
+
+func TestStorkFetcherInvalidMessages(t *testing.T) {
+	const invalid = `{"type":"invalid_message","data":"bad frame"}`
+
+	tests := []struct {
+		name     string
+		messages []string
+		expected error
+	}{
+		{
+			name:     "Invalid messages within tolerance",
+			messages: []string{invalid, invalid, `{"type":"subscribe"}`, invalid, invalid},
+		},
+		{
+			name:     "Too many invalid messages in a row",
+			messages: []string{invalid, invalid, invalid},
+			expected: ErrInvalidMessage,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				conn, err := (&websocket.Upgrader{}).Upgrade(w, r, nil)
+				if err != nil {
+					return
+				}
+				defer conn.Close()
+
+				for _, msg := range tt.messages {
+					if err := conn.WriteMessage(websocket.TextMessage, []byte(msg)); err != nil {
+						return
+					}
+				}
+
+				_ = conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""))
+			}))
+			defer srv.Close()
+
+			conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(srv.URL, "http"), nil)
+			if err != nil {
+				t.Fatalf("failed to dial: %v", err)
+			}
+
+			fetcher := NewStorkFetcher(&StorkConfig{
+				Message:            `{"type":"subscribe","data":["%s"]}`,
+				MaxInvalidMessages: 2,
+			}, []string{"BTCUSD"})
+
+			err = fetcher.Start(context.Background(), conn)
+			if tt.expected != nil && !errors.Is(err, tt.expected) {
+				t.Errorf("expected error %v, got %v", tt.expected, err)
+			} else if tt.expected == nil && errors.Is(err, ErrInvalidMessage) {
+				t.Errorf("expected invalid messages to be tolerated, got %v", err)
+			}
+		})
+	}
+}
+
+func TestStorkPriceFeedReleasedOnFailedBroadcast(t *testing.T) {
+	fetcher := &staticStorkFetcher{pair: &oracletypes.AssetPair{
+		AssetId:      "BTCUSD",
+		SignedPrices: []*oracletypes.SignedPriceOfAssetPair{{PublisherKey: "0x1", Timestamp: 100}},
+	}}
+
+	client := &broadcastStubClient{
+		stubChainClient: stubChainClient{from: cosmtypes.AccAddress("sender______________")},
+		err:             errors.New("node is down"),
+	}
+
+	svc, err := NewService(context.Background(), client, nil, nil, map[string]*FeedConfig{
+		"btc.toml": {ProviderName: "stork", Ticker: "BTCUSD", OracleType: "Stork"},
+	}, fetcher, ServiceConfig{})
+	if err != nil {
+		t.Fatalf("failed to init service: %v", err)
+	}
+
+	oracleSvc := svc.(*oracleSvc)
+	feed := oracleSvc.pricePullers["BTCUSD"]
+
+	broadcastPulled := func() {
+		priceData, err := feed.PullPrice(context.Background())
+		if err != nil || priceData == nil {
+			t.Fatalf("expected the asset pair to be pulled, got %+v, %v", priceData, err)
+		}
+
+		oracleSvc.broadcastBatch(map[string]*PriceData{"Stork:BTCUSD": priceData}, false)
+	}
+
+	// the signed prices of a failed broadcast are pulled again
+	broadcastPulled()
+	client.err = nil
+	broadcastPulled()
+
+	if priceData, err := feed.PullPrice(context.Background()); err != nil || priceData != nil {
+		t.Errorf("expected the asset pair of a successful broadcast to be skipped, got %+v, %v", priceData, err)
+	}
+}
+
+func TestStorkPriceFeedReleasedWhenSkipped(t *testing.T) {
+	fetcher := &staticStorkFetcher{pair: &oracletypes.AssetPair{
+		AssetId:      "BTCUSD",
+		SignedPrices: []*oracletypes.SignedPriceOfAssetPair{{PublisherKey: "0x1", Timestamp: 100}},
+	}}
+
+	client := &broadcastStubClient{stubChainClient: stubChainClient{from: cosmtypes.AccAddress("sender______________")}}
+
+	svc, err := NewService(context.Background(), client, nil, nil, map[string]*FeedConfig{
+		"btc.toml": {ProviderName: "stork", Ticker: "BTCUSD", OracleType: "Stork", MinSubmitInterval: "1h"},
+	}, fetcher, ServiceConfig{})
+	if err != nil {
+		t.Fatalf("failed to init service: %v", err)
+	}
+
+	oracleSvc := svc.(*oracleSvc)
+	oracleSvc.lastSubmitted["BTCUSD"] = SubmittedPrice{Timestamp: time.Now()}
+	feed := oracleSvc.pricePullers["BTCUSD"]
+
+	priceData, err := feed.PullPrice(context.Background())
+	if err != nil || priceData == nil {
+		t.Fatalf("expected the asset pair to be pulled, got %+v, %v", priceData, err)
+	}
+
+	// the price is skipped by the min submit interval in the commit loop
+	dataC := make(chan *PriceData)
+	done := make(chan struct{})
+	go func() {
+		oracleSvc.commitSetPrices(dataC)
+		close(done)
+	}()
+
+	dataC <- priceData
+	close(dataC)
+	<-done
+
+	if len(client.msgs) != 0 {
+		t.Fatalf("expected the skipped price not to be broadcast, got %v", client.msgs)
+	}
+
+	if priceData, err := feed.PullPrice(context.Background()); err != nil || priceData == nil {
+		t.Errorf("expected the asset pair of a skipped price to be pulled again, got %+v, %v", priceData, err)
+	}
+}
+
+func TestStorkFetcherCloseInterruptsStart(t *testing.T) {
+	subscribed := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := (&websocket.Upgrader{}).Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		// keep the connection open without sending prices, until the client closes it
+		if _, _, err := conn.ReadMessage(); err != nil {
+			return
+		}
+		close(subscribed)
+		_, _, _ = conn.ReadMessage()
+	}))
+	defer srv.Close()
+
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(srv.URL, "http"), nil)
+	if err != nil {
+		t.Fatalf("failed to dial: %v", err)
+	}
+
+	fetcher := NewStorkFetcher(&StorkConfig{Message: `{"type":"subscribe","data":["%s"]}`}, []string{"BTCUSD"})
+
+	started := make(chan error, 1)
+	go func() {
+		started <- fetcher.Start(context.Background(), conn)
+	}()
+
+	<-subscribed
+	fetcher.mu.Lock()
+	fetcher.lastUpdates["BTCUSD"] = time.Now()
+	fetcher.mu.Unlock()
+
+	fetcher.Close()
+
+	select {
+	case err := <-started:
+		if !errors.Is(err, ErrFetcherClosed) {
+			t.Errorf("expected ErrFetcherClosed from the interrupted start, got %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("start is not interrupted by close")
+	}
+
+	fetcher.mu.RLock()
+	defer fetcher.mu.RUnlock()
+
+	if len(fetcher.lastUpdates) != 0 {
+		t.Errorf("expected last updates to be cleared on close, got %v", fetcher.lastUpdates)
+	}
+}
@@ -0,0 +1,91 @@
+package oracle
+
+import (
+	"context"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/shopspring/decimal"
+)
+
+const switchboardBaseURL = "https://crossbar.switchboard.xyz"
+
+var _ PricePuller = &switchboardPriceFeed{}
+
+// switchboardPriceFeed pulls the value of a Switchboard On-Demand feed, simulated by the Crossbar REST API.
+type switchboardPriceFeed struct {
+	*restPriceFeed
+
+	baseURL string
+}
+
+// NewSwitchboardPriceFeed returns price puller for Switchboard On-Demand feeds. The symbol config field
+// is required and holds the feed hash.
+func NewSwitchboardPriceFeed(cfg *FeedConfig) (PricePuller, error) {
+	restFeed, err := newRestPriceFeed(FeedProviderSwitchboard, cfg, "")
+	if err != nil {
+		return nil, err
+	}
+
+	return &switchboardPriceFeed{
+		restPriceFeed: restFeed,
+		baseURL:       switchboardBaseURL,
+	}, nil
+}
+
+type switchboardSimulation struct {
+	FeedHash string            `json:"feedHash"`
+	Results  []decimal.Decimal `json:"results"`
+
+	// Timestamp is the unix time in seconds the results were produced at, if reported
+	Timestamp int64 `json:"timestamp"`
+}
+
+func (f *switchboardPriceFeed) PullPrice(ctx context.Context) (*PriceData, error) {
+	u := f.baseURL + "/simulate/" + url.PathEscape(f.symbol)
+
+	var simulations []switchboardSimulation
+	if err := f.getJSON(ctx, u, nil, &simulations); err != nil {
+		return nil, err
+	}
+
+	var simulation *switchboardSimulation
+	for i := range simulations {
+		if strings.EqualFold(strings.TrimPrefix(simulations[i].FeedHash, "0x"), strings.TrimPrefix(f.symbol, "0x")) {
+			simulation = &simulations[i]
+			break
+		}
+	}
+
+	if simulation == nil {
+		return nil, errors.Errorf("no simulation returned for feed %s", f.symbol)
+	} else if len(simulation.Results) == 0 {
+		return nil, errors.Errorf("simulation of feed %s returned no results", f.symbol)
+	}
+
+	price := medianDecimal(simulation.Results)
+	if simulation.Timestamp == 0 {
+		return f.priceData(price)
+	}
+
+	return f.priceDataAt(price, time.Unix(simulation.Timestamp, 0))
+}
+
+// medianDecimal returns the median of non-empty values, the mean of middle values for even length.
+func medianDecimal(values []decimal.Decimal) decimal.Decimal {
+	sorted := make([]decimal.Decimal, len(values))
+	copy(sorted, values)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].LessThan(sorted[j])
+	})
+
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 1 {
+		return sorted[mid]
+	}
+
+	return sorted[mid-1].Add(sorted[mid]).Div(decimal.NewFromInt(2))
+}
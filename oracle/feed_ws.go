@@ -0,0 +1,217 @@
+package oracle
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/InjectiveLabs/metrics"
+	"github.com/gorilla/websocket"
+	"github.com/pkg/errors"
+	"github.com/shopspring/decimal"
+
+	"github.com/InjectiveLabs/injective-price-oracle/pipeline"
+)
+
+const (
+	// wsReadTimeout is the maximum time to wait for the next message, before reconnecting
+	wsReadTimeout = 1 * time.Minute
+
+	// wsMaxReconnectBackoff caps the delay between reconnects of a failing stream
+	wsMaxReconnectBackoff = 1 * time.Minute
+
+	// wsFirstPriceTimeout is the maximum time the first pull waits for the stream to deliver a price
+	wsFirstPriceTimeout = 30 * time.Second
+)
+
+var _ PricePuller = &genericWSPriceFeed{}
+
+// genericWSPriceFeed streams prices from a websocket configured by the feed config, caching
+// the latest price extracted from messages, which is returned on pulls.
+type genericWSPriceFeed struct {
+	*restPriceFeed
+
+	wsURL            string
+	subscribeMessage string
+	jsonPath         string
+	compression      bool
+	readLimit        int64
+
+	startOnce sync.Once
+	cancelFn  context.CancelFunc
+	ready     chan struct{}
+	readyOnce sync.Once
+
+	mu       sync.RWMutex
+	latest   *decimal.Decimal
+	latestAt time.Time
+}
+
+// NewGenericWSPriceFeed returns price puller for a websocket stream. The wsUrl config field is
+// the stream to connect to, subscribeMessage is sent once connected (if set), and jsonPath is
+// a comma-separated path to the price in messages, same as of the jsonparse task. Messages
+// without the path (e.g. subscription acks) are ignored. The stream is connected on the first pull.
+func NewGenericWSPriceFeed(cfg *FeedConfig) (PricePuller, error) {
+	restFeed, err := newRestPriceFeed(FeedProviderWebsocket, cfg, cfg.Ticker)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(cfg.WsURL) == 0 {
+		return nil, errors.Errorf("wsUrl must be set for %s provider", FeedProviderWebsocket)
+	} else if len(cfg.JSONPath) == 0 {
+		return nil, errors.Errorf("jsonPath must be set for %s provider", FeedProviderWebsocket)
+	} else if cfg.WsReadLimit < 0 {
+		return nil, errors.Errorf("wsReadLimit must not be negative, got %d", cfg.WsReadLimit)
+	}
+
+	return &genericWSPriceFeed{
+		restPriceFeed:    restFeed,
+		wsURL:            cfg.WsURL,
+		subscribeMessage: cfg.SubscribeMessage,
+		jsonPath:         cfg.JSONPath,
+		compression:      cfg.WsCompression == nil || *cfg.WsCompression,
+		readLimit:        cfg.WsReadLimit,
+		ready:            make(chan struct{}),
+	}, nil
+}
+
+// PullPrice returns the latest streamed price. The first pull connects the stream and waits
+// for the first price to arrive.
+func (f *genericWSPriceFeed) PullPrice(ctx context.Context) (*PriceData, error) {
+	f.startOnce.Do(func() {
+		var streamCtx context.Context
+		streamCtx, f.cancelFn = context.WithCancel(context.Background())
+		go f.run(streamCtx)
+	})
+
+	firstPriceTimer := time.NewTimer(wsFirstPriceTimeout)
+	defer firstPriceTimer.Stop()
+
+	select {
+	case <-f.ready:
+	case <-ctx.Done():
+		return nil, errors.Wrap(ctx.Err(), "no price streamed yet")
+	case <-firstPriceTimer.C:
+		return nil, errors.New("no price streamed yet")
+	}
+
+	f.mu.RLock()
+	price, updatedAt := *f.latest, f.latestAt
+	f.mu.RUnlock()
+
+	metrics.CustomReport(func(s metrics.Statter, tagSpec []string) {
+		s.Gauge("feed_provider.websocket.last_update_age", time.Since(updatedAt).Seconds(), tagSpec, 1)
+	}, f.svcTags.With("ticker", f.ticker))
+
+	return f.priceDataAt(price, updatedAt)
+}
+
+// Close stops streaming.
+func (f *genericWSPriceFeed) Close() {
+	f.startOnce.Do(func() {})
+	if f.cancelFn != nil {
+		f.cancelFn()
+	}
+}
+
+// run keeps the stream connected until ctx is done, reconnecting with exponential backoff.
+func (f *genericWSPriceFeed) run(ctx context.Context) {
+	backoff := time.Second
+	for {
+		conn, err := pipeline.ConnectWebSocket(ctx, f.wsURL, "", nil, MaxRetriesReConnectWebSocket, f.compression, 0, f.readLimit)
+		if err == nil {
+			var streamed bool
+			streamed, err = f.stream(ctx, conn)
+			if streamed {
+				backoff = time.Second
+			}
+		}
+
+		if ctx.Err() != nil {
+			return
+		}
+
+		metrics.ReportFuncError(f.svcTags)
+		f.logger.WithError(err).Warningf("websocket stream failed, reconnecting in %s", backoff)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+
+		if backoff *= 2; backoff > wsMaxReconnectBackoff {
+			backoff = wsMaxReconnectBackoff
+		}
+	}
+}
+
+// stream subscribes and reads messages of the connection until it fails or ctx is done,
+// reporting whether any price has been streamed.
+func (f *genericWSPriceFeed) stream(ctx context.Context, conn *websocket.Conn) (streamed bool, err error) {
+	stopFn := context.AfterFunc(ctx, func() {
+		conn.Close()
+	})
+	defer stopFn()
+	defer conn.Close()
+
+	if len(f.subscribeMessage) > 0 {
+		if err := conn.WriteMessage(websocket.TextMessage, []byte(f.subscribeMessage)); err != nil {
+			return false, errors.Wrap(err, "failed to write subscribe message")
+		}
+	}
+
+	for {
+		if err := conn.SetReadDeadline(time.Now().Add(wsReadTimeout)); err != nil {
+			return streamed, err
+		}
+
+		_, message, err := conn.ReadMessage()
+		if err != nil {
+			return streamed, errors.Wrap(err, "failed to read message")
+		}
+
+		price, ok, err := f.extractPrice(ctx, message)
+		if err != nil {
+			f.logger.WithError(err).Warningln("failed to extract price from message")
+			continue
+		} else if !ok {
+			f.logger.Debugln("ignoring message without price:", string(message))
+			continue
+		}
+
+		f.mu.Lock()
+		f.latest = &price
+		f.latestAt = time.Now()
+		f.mu.Unlock()
+
+		f.readyOnce.Do(func() {
+			close(f.ready)
+		})
+
+		streamed = true
+	}
+}
+
+// extractPrice parses the price at the json path of the message, ok is false if the message has no such path.
+func (f *genericWSPriceFeed) extractPrice(ctx context.Context, message []byte) (price decimal.Decimal, ok bool, err error) {
+	task := &pipeline.JSONParseTask{
+		Path: f.jsonPath,
+		Lax:  "true",
+	}
+
+	result, _ := task.Run(ctx, f.logger, pipeline.NewVarsFrom(nil), []pipeline.Result{{Value: string(message)}})
+	if result.Error != nil {
+		return price, false, result.Error
+	} else if result.Value == nil {
+		return price, false, nil
+	}
+
+	var param pipeline.DecimalParam
+	if err := param.UnmarshalPipelineParam(result.Value); err != nil {
+		return price, false, errors.Wrapf(err, "failed to parse price at path %s", f.jsonPath)
+	}
+
+	return param.Decimal(), true, nil
+}
@@ -0,0 +1,82 @@
+package oracle
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+func TestGenericWSPriceFeed(t *testing.T) {
+	subscribed := make(chan string, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := (&websocket.Upgrader{}).Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		_, msg, err := conn.ReadMessage()
+		if err != nil {
+			return
+		}
+		subscribed <- string(msg)
+
+		for _, msg := range []string{
+			`{"event":"subscribed"}`,
+			`not a json`,
+			`{"data":[{"p":"64123.5"}]}`,
+		} {
+			if err := conn.WriteMessage(websocket.TextMessage, []byte(msg)); err != nil {
+				return
+			}
+		}
+
+		// keep the connection open until the client closes it
+		_, _, _ = conn.ReadMessage()
+	}))
+	defer srv.Close()
+
+	feed, err := NewGenericWSPriceFeed(&FeedConfig{
+		ProviderName:     "test_ws",
+		Ticker:           "BTC/USDT",
+		WsURL:            "ws" + strings.TrimPrefix(srv.URL, "http"),
+		SubscribeMessage: `{"op":"subscribe","args":["BTCUSDT"]}`,
+		JSONPath:         "data,0,p",
+	})
+	if err != nil {
+		t.Fatalf("failed to init feed: %v", err)
+	}
+	defer feed.(*genericWSPriceFeed).Close()
+
+	ctx, cancelFn := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancelFn()
+
+	priceData, err := feed.PullPrice(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if priceData.Price.String() != "64123.5" {
+		t.Errorf("expected price 64123.5, got %s", priceData.Price)
+	}
+
+	if msg := <-subscribed; msg != `{"op":"subscribe","args":["BTCUSDT"]}` {
+		t.Errorf("unexpected subscribe message: %s", msg)
+	}
+}
+
+func TestNewGenericWSPriceFeedValidation(t *testing.T) {
+	for _, cfg := range []*FeedConfig{
+		{ProviderName: "websocket", Ticker: "BTC/USDT", JSONPath: "p"},
+		{ProviderName: "websocket", Ticker: "BTC/USDT", WsURL: "ws://localhost"},
+	} {
+		if _, err := NewGenericWSPriceFeed(cfg); err == nil {
+			t.Errorf("expected error for config %+v", cfg)
+		}
+	}
+}
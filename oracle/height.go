@@ -0,0 +1,89 @@
+package oracle
+
+import (
+	"context"
+	"time"
+
+	"github.com/InjectiveLabs/metrics"
+)
+
+// relayerTags returns fresh tags of the relayer account, since Tags.With adds to the service tags in place.
+func (s *oracleSvc) relayerTags() metrics.Tags {
+	return metrics.Tags{
+		"svc":     "price_oracle",
+		"relayer": s.cosmosClient.FromAddress().String(),
+	}
+}
+
+// tickerTags returns fresh tags of the ticker, since Tags.With adds to the service tags in place
+// and feeds report concurrently.
+func tickerTags(ticker Ticker) metrics.Tags {
+	return metrics.Tags{
+		"svc":    "price_oracle",
+		"ticker": string(ticker),
+	}
+}
+
+// reportSubmittedHeight records the height of the latest successful submission and reports it as a gauge.
+func (s *oracleSvc) reportSubmittedHeight(height int64) {
+	if height <= 0 {
+		return
+	}
+
+	s.lastSubmittedHeight.Store(height)
+
+	metrics.CustomReport(func(s metrics.Statter, tagSpec []string) {
+		s.Gauge("price_oracle.submission.height", float64(height), tagSpec, 1)
+	}, s.relayerTags())
+}
+
+// watchHeightLag periodically reports the lag between the latest chain height, as seen by the node of the relayer,
+// and the height of the latest successful submission. A growing lag means submissions have stopped landing,
+// e.g. because the node fell behind.
+func (s *oracleSvc) watchHeightLag(done <-chan struct{}) {
+	ticker := time.NewTicker(s.heightLagInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+		}
+
+		submittedHeight := s.lastSubmittedHeight.Load()
+		if submittedHeight == 0 {
+			// nothing submitted yet
+			continue
+		}
+
+		ctx, cancelFn := context.WithTimeout(context.Background(), maxRespTime)
+		res, err := s.cosmosClient.FetchLatestBlock(ctx)
+		cancelFn()
+		if err != nil {
+			s.logger.WithError(err).Warningln("failed to fetch the latest block height")
+			continue
+		}
+
+		var latestHeight int64
+		if block := res.GetSdkBlock(); block != nil {
+			latestHeight = block.Header.Height
+		} else if block := res.GetBlock(); block != nil {
+			latestHeight = block.Header.Height
+		}
+
+		if latestHeight == 0 {
+			continue
+		}
+
+		lag := latestHeight - submittedHeight
+		if lag < 0 {
+			// heights may come from different nodes behind a load balancer
+			lag = 0
+		}
+
+		metrics.CustomReport(func(s metrics.Statter, tagSpec []string) {
+			s.Gauge("price_oracle.submission.height_lag", float64(lag), tagSpec, 1)
+		}, s.relayerTags())
+	}
+}
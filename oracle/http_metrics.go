@@ -0,0 +1,39 @@
+package oracle
+
+import (
+	"github.com/InjectiveLabs/metrics"
+
+	"github.com/InjectiveLabs/injective-price-oracle/pipeline"
+)
+
+// HTTPTaskMetrics reports body sizes of http tasks of dynamic feed pipelines as price_oracle.pipeline.http
+// metrics, tagged by the job and the task dotID. Set it with pipeline.SetHTTPMetricsReporter.
+type HTTPTaskMetrics struct{}
+
+var _ pipeline.HTTPMetricsReporter = HTTPTaskMetrics{}
+
+func (HTTPTaskMetrics) RequestBodySize(job, dotID string, size int) {
+	metrics.CustomReport(func(s metrics.Statter, tagSpec []string) {
+		s.Histogram("price_oracle.pipeline.http.request.bytes", float64(size), tagSpec, 1)
+	}, httpTaskTags(job, dotID))
+}
+
+func (HTTPTaskMetrics) ResponseBodySize(job, dotID string, size int) {
+	metrics.CustomReport(func(s metrics.Statter, tagSpec []string) {
+		s.Histogram("price_oracle.pipeline.http.response.bytes", float64(size), tagSpec, 1)
+	}, httpTaskTags(job, dotID))
+}
+
+func (HTTPTaskMetrics) ResponseTooLarge(job, dotID string) {
+	metrics.CustomReport(func(s metrics.Statter, tagSpec []string) {
+		s.Count("price_oracle.pipeline.http.response.too_large.size", 1, tagSpec, 1)
+	}, httpTaskTags(job, dotID))
+}
+
+func httpTaskTags(job, dotID string) metrics.Tags {
+	return metrics.Tags{
+		"svc":   "price_oracle",
+		"job":   job,
+		"dotID": dotID,
+	}
+}
@@ -0,0 +1,49 @@
+package oracle
+
+import (
+	"time"
+
+	"github.com/InjectiveLabs/metrics"
+)
+
+// feedInventoryInterval is how often the feed inventory gauges are reported again, so dashboards
+// keep tracking them between changes.
+const feedInventoryInterval = time.Minute
+
+// reportFeedInventory reports the number of running price pullers and of those whose latest pull failed.
+func (s *oracleSvc) reportFeedInventory() {
+	activeFeeds := s.activeFeeds.Load()
+	failingFeeds := s.failingFeeds.Load()
+
+	metrics.CustomReport(func(s metrics.Statter, tagSpec []string) {
+		s.Gauge("price_oracle.feeds.active", float64(activeFeeds), tagSpec, 1)
+		s.Gauge("price_oracle.feeds.failing", float64(failingFeeds), tagSpec, 1)
+	}, s.svcTags)
+}
+
+// setFeedFailing marks a feed as failing after its first failed pull, or as recovered
+// after its first successful one, and reports the change.
+func (s *oracleSvc) setFeedFailing(failing bool) {
+	if failing {
+		s.failingFeeds.Add(1)
+	} else {
+		s.failingFeeds.Add(-1)
+	}
+
+	s.reportFeedInventory()
+}
+
+// watchFeedInventory periodically reports the feed inventory gauges until done is closed.
+func (s *oracleSvc) watchFeedInventory(done <-chan struct{}) {
+	ticker := time.NewTicker(feedInventoryInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+			s.reportFeedInventory()
+		}
+	}
+}
@@ -0,0 +1,142 @@
+package oracle
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/InjectiveLabs/metrics"
+	oracletypes "github.com/InjectiveLabs/sdk-go/chain/oracle/types"
+	chainclient "github.com/InjectiveLabs/sdk-go/client/chain"
+	log "github.com/InjectiveLabs/suplog"
+	cosmtypes "github.com/cosmos/cosmos-sdk/types"
+	"github.com/pkg/errors"
+)
+
+// MirrorNetwork is another network every price batch is submitted to along with the main one, e.g. testnet
+// alongside mainnet for parity checks. It has its own chain client and key, so its account sequence and
+// relayer authorization are independent of the main network, and its failures don't affect the main one.
+type MirrorNetwork struct {
+	// Name tells the network apart in logs, alerts and metrics, e.g. "testnet".
+	Name   string
+	Client chainclient.ChainClient
+}
+
+type mirrorNetwork struct {
+	MirrorNetwork
+
+	failures atomic.Int32
+	logger   log.Logger
+}
+
+func newMirrorNetworks(networks []MirrorNetwork) ([]*mirrorNetwork, error) {
+	mirrors := make([]*mirrorNetwork, 0, len(networks))
+	names := make(map[string]struct{}, len(networks))
+	for _, network := range networks {
+		if len(network.Name) == 0 {
+			return nil, errors.New("mirror network name must be set")
+		} else if network.Client == nil {
+			return nil, errors.Errorf("mirror network %s has no chain client", network.Name)
+		} else if _, ok := names[network.Name]; ok {
+			return nil, errors.Errorf("duplicate mirror network %s", network.Name)
+		}
+		names[network.Name] = struct{}{}
+
+		mirrors = append(mirrors, &mirrorNetwork{
+			MirrorNetwork: network,
+			logger: log.WithFields(log.Fields{
+				"svc":     "oracle",
+				"network": network.Name,
+			}),
+		})
+	}
+
+	return mirrors, nil
+}
+
+// tags returns fresh tags of the network and its relayer account.
+func (m *mirrorNetwork) tags() metrics.Tags {
+	return metrics.Tags{
+		"svc":     "price_oracle",
+		"network": m.Name,
+		"relayer": m.Client.FromAddress().String(),
+	}
+}
+
+// broadcastToMirrors submits the messages composed for the main network to every mirror network in parallel,
+// signed by the relayer of each. Returns a func waiting for all the broadcasts to finish.
+func (s *oracleSvc) broadcastToMirrors(msgs []cosmtypes.Msg, batchMeta map[string]int) (wait func()) {
+	var wg sync.WaitGroup
+	for _, mirror := range s.mirrors {
+		wg.Add(1)
+		go func(mirror *mirrorNetwork) {
+			defer wg.Done()
+			s.broadcastToMirror(mirror, msgs, batchMeta)
+		}(mirror)
+	}
+
+	return wg.Wait
+}
+
+func (s *oracleSvc) broadcastToMirror(mirror *mirrorNetwork, msgs []cosmtypes.Msg, batchMeta map[string]int) {
+	tags := mirror.tags()
+	batchLog := mirror.logger.WithField("batch_size", len(msgs))
+
+	ts := time.Now()
+	txResp, err := mirror.Client.SyncBroadcastMsg(withSender(msgs, mirror.Client.FromAddress().String())...)
+	if err == nil && txResp.TxResponse != nil && txResp.TxResponse.Code != 0 {
+		err = errors.Errorf("Tx %s error code %d: %s", txResp.TxResponse.TxHash, txResp.TxResponse.Code, txResp.TxResponse.RawLog)
+	}
+
+	if err != nil {
+		metrics.CustomReport(func(s metrics.Statter, tagSpec []string) {
+			s.Count("price_oracle.mirror.broadcast.failed.size", 1, tagSpec, 1)
+		}, tags)
+		batchLog.WithError(err).Errorln("failed to broadcast to mirror network")
+
+		s.alerts.failed(fmt.Sprintf("broadcast to %s", mirror.Name), int(mirror.failures.Add(1)), err)
+		return
+	} else if txResp.TxResponse == nil {
+		return
+	}
+
+	mirror.failures.Store(0)
+
+	metrics.CustomReport(func(s metrics.Statter, tagSpec []string) {
+		for oracleType, count := range batchMeta {
+			s.Count(fmt.Sprintf("price_oracle.mirror.%s.submitted.price.size", strings.ToLower(oracleType)), int64(count), tagSpec, 1)
+		}
+		s.Gauge("price_oracle.mirror.submission.height", float64(txResp.TxResponse.Height), tagSpec, 1)
+	}, tags)
+
+	batchLog.WithField("height", txResp.TxResponse.Height).
+		WithField("hash", txResp.TxResponse.TxHash).
+		Infoln("sent Tx to mirror network in", time.Since(ts))
+}
+
+// withSender returns copies of the composed messages, signed by the sender instead.
+func withSender(msgs []cosmtypes.Msg, sender string) []cosmtypes.Msg {
+	result := make([]cosmtypes.Msg, 0, len(msgs))
+	for _, msg := range msgs {
+		switch msg := msg.(type) {
+		case *oracletypes.MsgRelayPriceFeedPrice:
+			copied := *msg
+			copied.Sender = sender
+			result = append(result, &copied)
+		case *oracletypes.MsgRelayProviderPrices:
+			copied := *msg
+			copied.Sender = sender
+			result = append(result, &copied)
+		case *oracletypes.MsgRelayStorkPrices:
+			copied := *msg
+			copied.Sender = sender
+			result = append(result, &copied)
+		default:
+			result = append(result, msg)
+		}
+	}
+
+	return result
+}
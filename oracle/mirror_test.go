@@ -0,0 +1,95 @@
+package oracle
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	oracletypes "github.com/InjectiveLabs/sdk-go/chain/oracle/types"
+	cosmtypes "github.com/cosmos/cosmos-sdk/types"
+	txtypes "github.com/cosmos/cosmos-sdk/types/tx"
+	"github.com/shopspring/decimal"
+)
+
+type broadcastStubClient struct {
+	stubChainClient
+
+	err  error
+	mu   sync.Mutex
+	msgs []cosmtypes.Msg
+}
+
+func (c *broadcastStubClient) SyncBroadcastMsg(msgs ...cosmtypes.Msg) (*txtypes.BroadcastTxResponse, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.msgs = append(c.msgs, msgs...)
+	if c.err != nil {
+		return nil, c.err
+	}
+
+	return &txtypes.BroadcastTxResponse{TxResponse: &cosmtypes.TxResponse{Height: 100, TxHash: "ABCD"}}, nil
+}
+
+func TestBroadcastToMirrorNetworks(t *testing.T) {
+	newClient := func(from string, err error) *broadcastStubClient {
+		return &broadcastStubClient{
+			stubChainClient: stubChainClient{from: cosmtypes.AccAddress(from)},
+			err:             err,
+		}
+	}
+
+	mainnet := newClient("mainnet_sender______", nil)
+	testnet := newClient("testnet_sender______", nil)
+	devnet := newClient("devnet_sender_______", errors.New("devnet is down"))
+
+	svc, err := NewService(context.Background(), mainnet, nil, nil, map[string]*FeedConfig{}, nil, ServiceConfig{
+		MirrorNetworks: []MirrorNetwork{
+			{Name: "testnet", Client: testnet},
+			{Name: "devnet", Client: devnet},
+		},
+	})
+	if err != nil {
+		t.Fatalf("failed to init service: %v", err)
+	}
+
+	oracleSvc := svc.(*oracleSvc)
+	oracleSvc.broadcastBatch(map[string]*PriceData{
+		"PriceFeed:INJ/USDT": {
+			Ticker:     "INJ/USDT",
+			Symbol:     "INJ/USDT",
+			Price:      decimal.RequireFromString("25.5"),
+			OracleType: oracletypes.OracleType_PriceFeed,
+			Timestamp:  time.Now(),
+		},
+	}, false)
+
+	for client, sender := range map[*broadcastStubClient]cosmtypes.AccAddress{
+		mainnet: mainnet.from,
+		testnet: testnet.from,
+		devnet:  devnet.from,
+	} {
+		if len(client.msgs) != 1 {
+			t.Fatalf("expected a message broadcast to every network, got %d", len(client.msgs))
+		} else if msg := client.msgs[0].(*oracletypes.MsgRelayPriceFeedPrice); msg.Sender != sender.String() {
+			t.Errorf("expected message signed by %s, got %s", sender, msg.Sender)
+		}
+	}
+
+	// a failing mirror network doesn't fail the main network
+	if _, ok := oracleSvc.lastSubmittedPrice("INJ/USDT"); !ok {
+		t.Error("expected the price submitted to the main network")
+	} else if failures := oracleSvc.broadcastFailures.Load(); failures != 0 {
+		t.Errorf("expected no broadcast failures of the main network, got %d", failures)
+	} else if failures := oracleSvc.mirrors[1].failures.Load(); failures != 1 {
+		t.Errorf("expected a broadcast failure of the devnet, got %d", failures)
+	}
+
+	if _, err := NewService(context.Background(), mainnet, nil, nil, map[string]*FeedConfig{}, nil, ServiceConfig{
+		MirrorNetworks: []MirrorNetwork{{Name: "testnet", Client: testnet}, {Name: "testnet", Client: devnet}},
+	}); err == nil {
+		t.Error("expected error for duplicate mirror networks")
+	}
+}
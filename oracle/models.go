@@ -29,6 +29,9 @@ type PriceData struct {
 	// Timestamp of the report
 	Timestamp time.Time
 
+	// PulledAt is when the oracle pulled the price, while Timestamp may be set by the upstream source
+	PulledAt time.Time
+
 	OracleType oracletypes.OracleType
 }
 
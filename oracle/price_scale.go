@@ -0,0 +1,77 @@
+package oracle
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	log "github.com/InjectiveLabs/suplog"
+	"github.com/pkg/errors"
+	"github.com/shopspring/decimal"
+
+	exchangetypes "github.com/InjectiveLabs/sdk-go/chain/exchange/types"
+	oracletypes "github.com/InjectiveLabs/sdk-go/chain/oracle/types"
+)
+
+// maxPriceScale bounds the price scale exponent, so scaled prices stay within 18 places of on-chain decimals.
+const maxPriceScale = 18
+
+func (c *FeedConfig) priceScale() (int32, error) {
+	if c.PriceScale == nil {
+		return 0, nil
+	}
+
+	if *c.PriceScale < -maxPriceScale || *c.PriceScale > maxPriceScale {
+		return 0, errors.Errorf("price scale must be between %d and %d: %d", -maxPriceScale, maxPriceScale, *c.PriceScale)
+	}
+
+	return int32(*c.PriceScale), nil
+}
+
+// scaledPriceFeedPrice returns the price relayed with MsgRelayPriceFeedPrice, scaled by 10^priceScale of the feed
+// and rounded to on-chain decimals.
+func (s *oracleSvc) scaledPriceFeedPrice(priceData *PriceData) decimal.Decimal {
+	scale, ok := s.priceScales[string(priceData.Ticker)]
+	if !ok {
+		return priceData.Price
+	}
+
+	return priceData.Price.Shift(scale).Round(maxSubmitPrecision)
+}
+
+// checkPriceScales warns about feeds with a price scale relayed to derivative markets that scale oracle prices
+// by their own oracle scale factor, so the price would be scaled twice. Markets are queried from the chain
+// if the exchange query client is available, a failed query is not fatal.
+func (s *oracleSvc) checkPriceScales() {
+	if s.exchangeQueryClient == nil || len(s.priceScales) == 0 {
+		return
+	}
+
+	ctx, cancelFn := context.WithTimeout(context.Background(), time.Minute)
+	defer cancelFn()
+
+	res, err := s.exchangeQueryClient.DerivativeMarkets(ctx, &exchangetypes.QueryDerivativeMarketsRequest{
+		Status: exchangetypes.MarketStatus_Active.String(),
+	})
+	if err != nil {
+		s.logger.WithError(err).Warningln("failed to query derivative markets, price scales are not checked")
+		return
+	}
+
+	for _, fullMarket := range res.Markets {
+		market := fullMarket.Market
+		if market == nil || market.OracleType != oracletypes.OracleType_PriceFeed || market.OracleScaleFactor == 0 {
+			continue
+		}
+
+		ticker := fmt.Sprintf("%s/%s", market.OracleBase, market.OracleQuote)
+		if scale, ok := s.priceScales[ticker]; ok {
+			s.logger.WithFields(log.Fields{
+				"ticker":              ticker,
+				"market":              market.Ticker,
+				"price_scale":         scale,
+				"oracle_scale_factor": market.OracleScaleFactor,
+			}).Warningln("market scales oracle prices by its oracle scale factor too, the price of the feed is scaled twice")
+		}
+	}
+}
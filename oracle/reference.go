@@ -0,0 +1,119 @@
+package oracle
+
+import (
+	"context"
+
+	"github.com/InjectiveLabs/metrics"
+	log "github.com/InjectiveLabs/suplog"
+	"github.com/pkg/errors"
+	"github.com/shopspring/decimal"
+
+	"github.com/InjectiveLabs/injective-price-oracle/pipeline"
+)
+
+// referenceCheck cross-checks feed prices against an independent reference price source.
+type referenceCheck struct {
+	pricePuller  PricePuller
+	maxDeviation decimal.Decimal
+}
+
+// validateReferenceSource validates the optional reference source of the feed config.
+func (c *FeedConfig) validateReferenceSource() error {
+	if len(c.ReferenceSource) == 0 {
+		if len(c.ReferenceMaxDeviation) > 0 {
+			return errors.New("reference max deviation is set, but reference source is empty")
+		}
+
+		return nil
+	}
+
+	if c.ProviderName == FeedProviderStork.String() {
+		return errors.New("reference source is not supported for Stork feeds")
+	}
+
+	if _, err := pipeline.Parse(c.ReferenceSource); err != nil {
+		return errors.Wrap(err, "reference source pipeline parse error")
+	}
+
+	maxDeviation, err := decimal.NewFromString(c.ReferenceMaxDeviation)
+	if err != nil || !maxDeviation.IsPositive() {
+		return errors.Errorf("reference max deviation must be a positive number, got %q", c.ReferenceMaxDeviation)
+	}
+
+	return nil
+}
+
+// newReferenceCheck inits a reference check of the feed, returns nil if the feed has no reference source.
+func newReferenceCheck(feedCfg *FeedConfig) (*referenceCheck, error) {
+	if len(feedCfg.ReferenceSource) == 0 {
+		return nil, nil
+	}
+
+	if err := feedCfg.validateReferenceSource(); err != nil {
+		return nil, err
+	}
+
+	pricePuller, err := NewDynamicPriceFeed(&FeedConfig{
+		ProviderName:      feedCfg.ProviderName + "_reference",
+		Ticker:            feedCfg.Ticker,
+		PullInterval:      feedCfg.PullInterval,
+		ObservationSource: feedCfg.ReferenceSource,
+		OracleType:        feedCfg.OracleType,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to init reference price feed")
+	}
+
+	return &referenceCheck{
+		pricePuller:  pricePuller,
+		maxDeviation: decimal.RequireFromString(feedCfg.ReferenceMaxDeviation),
+	}, nil
+}
+
+// matchesReferencePrice pulls the reference price of the feed and reports whether the pulled price
+// is within the max deviation from it. If the reference can't be pulled, the price is accepted,
+// so an unavailable reference doesn't stop the feed.
+func (s *oracleSvc) matchesReferencePrice(ctx context.Context, priceData *PriceData) bool {
+	check, ok := s.referenceChecks[string(priceData.Ticker)]
+	if !ok {
+		return true
+	}
+
+	tags := tickerTags(priceData.Ticker)
+	checkLogger := s.logger.WithFields(log.Fields{
+		"ticker":   priceData.Ticker,
+		"provider": priceData.ProviderName,
+	})
+
+	reference, err := check.pricePuller.PullPrice(ctx)
+	if err == nil && (reference == nil || !reference.Price.IsPositive()) {
+		err = errors.New("got empty or non-positive reference price")
+	}
+
+	if err != nil {
+		metrics.CustomReport(func(s metrics.Statter, tagSpec []string) {
+			s.Count("price_oracle.reference_price.error.size", 1, tagSpec, 1)
+		}, tags)
+
+		checkLogger.WithError(err).Warningln("failed to pull reference price, accepting price unchecked")
+		return true
+	}
+
+	deviation := priceData.Price.Sub(reference.Price).Abs().Div(reference.Price)
+	if deviation.GreaterThan(check.maxDeviation) {
+		metrics.CustomReport(func(s metrics.Statter, tagSpec []string) {
+			s.Count("price_oracle.reference_price.rejected.size", 1, tagSpec, 1)
+		}, tags)
+
+		checkLogger.WithFields(log.Fields{
+			"price":           priceData.Price.String(),
+			"reference_price": reference.Price.String(),
+			"deviation":       deviation.String(),
+			"max_deviation":   check.maxDeviation.String(),
+		}).Warningln("price diverges from the reference price, rejecting it")
+
+		return false
+	}
+
+	return true
+}
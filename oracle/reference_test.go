@@ -0,0 +1,51 @@
+package oracle
+
+import (
+	"context"
+	"testing"
+
+	"github.com/InjectiveLabs/metrics"
+	log "github.com/InjectiveLabs/suplog"
+	"github.com/shopspring/decimal"
+)
+
+func TestMatchesReferencePrice(t *testing.T) {
+	check, err := newReferenceCheck(&FeedConfig{
+		ProviderName:          "test",
+		Ticker:                "INJ/USDT",
+		ObservationSource:     `price [type=memo value="10"]`,
+		ReferenceSource:       `a [type=memo value="10"]; b [type=multiply times=1]; a -> b`,
+		ReferenceMaxDeviation: "0.05",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	svc := &oracleSvc{
+		referenceChecks: map[string]*referenceCheck{"INJ/USDT": check},
+		logger:          log.WithField("svc", "oracle"),
+		svcTags:         metrics.Tags{},
+	}
+
+	for price, expected := range map[string]bool{
+		"9.4":  false,
+		"9.5":  true,
+		"10":   true,
+		"10.5": true,
+		"10.6": false,
+	} {
+		priceData := &PriceData{
+			Ticker: "INJ/USDT",
+			Price:  decimal.RequireFromString(price),
+		}
+
+		if got := svc.matchesReferencePrice(context.Background(), priceData); got != expected {
+			t.Errorf("expected matchesReferencePrice(%s) = %v, got %v", price, expected, got)
+		}
+	}
+
+	// feeds without a reference source are not checked
+	if !svc.matchesReferencePrice(context.Background(), &PriceData{Ticker: "ATOM/USDT"}) {
+		t.Errorf("expected price of a feed without reference source to be accepted")
+	}
+}
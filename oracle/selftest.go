@@ -0,0 +1,70 @@
+package oracle
+
+import (
+	"bytes"
+	"strings"
+
+	chainclient "github.com/InjectiveLabs/sdk-go/client/chain"
+	"github.com/cosmos/cosmos-sdk/types/tx/signing"
+	"github.com/pkg/errors"
+)
+
+var selfTestPayload = []byte("injective-price-oracle self-test")
+
+// selfTest checks that price feeds are loaded, the relayer key can sign, and the relayer is authorized
+// in at least one of the loaded price feeds, so a wrong key or an unauthorized relayer fails the start
+// right away, instead of after minutes of rejected Txs. Authorization is checked only if some feeds
+// are of the PriceFeed oracle type, since relayers of other oracle types are not queryable this way.
+func (s *oracleSvc) selfTest() error {
+	if len(s.pricePullers) == 0 {
+		return errors.New("self-test: no price feeds loaded")
+	} else if s.cosmosClient == nil {
+		return errors.New("self-test: no chain client")
+	}
+
+	if err := checkRelayerSigns(s.cosmosClient); err != nil {
+		return errors.Wrap(err, "self-test")
+	}
+
+	for _, mirror := range s.mirrors {
+		if err := checkRelayerSigns(mirror.Client); err != nil {
+			return errors.Wrapf(err, "self-test: mirror network %s", mirror.Name)
+		}
+	}
+
+	priceFeedTickers := s.priceFeedTickers()
+
+	if len(priceFeedTickers) == 0 {
+		s.logger.Infoln("self-test: no PriceFeed oracle type feeds loaded, skipping the relayer authorization check")
+		return nil
+	}
+
+	enabledFeeds := s.getEnabledFeeds()
+	if enabledFeeds == nil {
+		return errors.New("self-test: failed to query relayers of price feeds")
+	} else if len(enabledFeeds) == 0 {
+		return errors.Errorf("self-test: relayer %s is not authorized in any of the loaded price feeds: %s",
+			s.cosmosClient.FromAddress(), strings.Join(priceFeedTickers, ", "))
+	}
+
+	s.logger.Infof("self-test passed, relayer %s is authorized in %d of %d loaded price feeds", s.cosmosClient.FromAddress(), len(enabledFeeds), len(priceFeedTickers))
+	return nil
+}
+
+// checkRelayerSigns signs a payload with the relayer key, checking the signature is of the relayer address.
+func checkRelayerSigns(cosmosClient chainclient.ChainClient) error {
+	clientCtx := cosmosClient.ClientContext()
+	if clientCtx.Keyring == nil {
+		return errors.New("no keyring to sign with")
+	}
+
+	from := cosmosClient.FromAddress()
+	signature, pubKey, err := clientCtx.Keyring.SignByAddress(from, selfTestPayload, signing.SignMode_SIGN_MODE_DIRECT)
+	if err != nil {
+		return errors.Wrapf(err, "relayer %s failed to sign", from)
+	} else if !bytes.Equal(pubKey.Address(), from) || !pubKey.VerifySignature(selfTestPayload, signature) {
+		return errors.Errorf("relayer %s key produced an invalid signature", from)
+	}
+
+	return nil
+}
@@ -0,0 +1,88 @@
+package oracle
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	oracletypes "github.com/InjectiveLabs/sdk-go/chain/oracle/types"
+	"github.com/cosmos/cosmos-sdk/client"
+	"github.com/cosmos/cosmos-sdk/codec"
+	codectypes "github.com/cosmos/cosmos-sdk/codec/types"
+	cryptocodec "github.com/cosmos/cosmos-sdk/crypto/codec"
+	"github.com/cosmos/cosmos-sdk/crypto/hd"
+	"github.com/cosmos/cosmos-sdk/crypto/keyring"
+	cosmtypes "github.com/cosmos/cosmos-sdk/types"
+)
+
+type keyringStubClient struct {
+	stubChainClient
+	clientCtx client.Context
+}
+
+func (c *keyringStubClient) ClientContext() client.Context { return c.clientCtx }
+
+func TestServiceSelfTest(t *testing.T) {
+	registry := codectypes.NewInterfaceRegistry()
+	cryptocodec.RegisterInterfaces(registry)
+
+	kr := keyring.NewInMemory(codec.NewProtoCodec(registry))
+	record, _, err := kr.NewMnemonic("relayer", keyring.English, cosmtypes.FullFundraiserPath, keyring.DefaultBIP39Passphrase, hd.Secp256k1)
+	if err != nil {
+		t.Fatalf("failed to create key: %v", err)
+	}
+
+	relayer, err := record.GetAddress()
+	if err != nil {
+		t.Fatalf("failed to get key address: %v", err)
+	}
+
+	feedConfigs := map[string]*FeedConfig{
+		"inj.toml": {
+			ProviderName:      "test",
+			Ticker:            "INJ/USDT",
+			OracleType:        "PriceFeed",
+			ObservationSource: `price [type=memo value="25.5"]`,
+		},
+	}
+
+	selfTest := func(client *keyringStubClient, relayers []string, feedConfigs map[string]*FeedConfig) error {
+		queryClient := &stubOracleQueryClient{
+			priceStates: []*oracletypes.PriceFeedState{
+				{Base: "INJ", Quote: "USDT", Relayers: relayers},
+			},
+		}
+
+		svc, err := NewService(context.Background(), client, nil, queryClient, feedConfigs, nil, ServiceConfig{SelfTest: true})
+		if err != nil {
+			t.Fatalf("failed to init service: %v", err)
+		}
+
+		return svc.(*oracleSvc).selfTest()
+	}
+
+	client := &keyringStubClient{
+		stubChainClient: stubChainClient{from: relayer},
+		clientCtx:       client.Context{}.WithKeyring(kr),
+	}
+
+	if err := selfTest(client, []string{relayer.String()}, feedConfigs); err != nil {
+		t.Errorf("expected self-test to pass, got %v", err)
+	}
+
+	if err := selfTest(client, []string{"inj1other"}, feedConfigs); err == nil || !strings.Contains(err.Error(), "not authorized") {
+		t.Errorf("expected unauthorized relayer error, got %v", err)
+	}
+
+	if err := selfTest(client, []string{relayer.String()}, map[string]*FeedConfig{}); err == nil || !strings.Contains(err.Error(), "no price feeds") {
+		t.Errorf("expected no price feeds error, got %v", err)
+	}
+
+	wrongKey := &keyringStubClient{
+		stubChainClient: stubChainClient{from: cosmtypes.AccAddress("wrong_key___________")},
+		clientCtx:       client.clientCtx,
+	}
+	if err := selfTest(wrongKey, []string{wrongKey.from.String()}, feedConfigs); err == nil || !strings.Contains(err.Error(), "failed to sign") {
+		t.Errorf("expected signing error of a key missing from the keyring, got %v", err)
+	}
+}
@@ -4,7 +4,10 @@ import (
 	"context"
 	"fmt"
 	"runtime/debug"
+	"sort"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"cosmossdk.io/math"
@@ -22,6 +25,8 @@ import (
 
 type Service interface {
 	Start() error
+	Simulate(ctx context.Context) (*SimulationResult, error)
+	Submit(ctx context.Context, ticker string, price *decimal.Decimal) (txHash string, err error)
 	Close()
 }
 
@@ -38,11 +43,171 @@ type PricePuller interface {
 }
 
 type FeedConfig struct {
-	ProviderName      string `toml:"provider"`
-	Ticker            string `toml:"ticker"`
-	PullInterval      string `toml:"pullInterval"`
-	ObservationSource string `toml:"observationSource"`
-	OracleType        string `toml:"oracleType"`
+	ProviderName      string `toml:"provider" yaml:"provider" json:"provider"`
+	Ticker            string `toml:"ticker" yaml:"ticker" json:"ticker"`
+	PullInterval      string `toml:"pullInterval" yaml:"pullInterval" json:"pullInterval"`
+	ObservationSource string `toml:"observationSource" yaml:"observationSource" json:"observationSource"`
+	OracleType        string `toml:"oracleType" yaml:"oracleType" json:"oracleType"`
+
+	// OracleTypes submits the price of the feed to each of the listed oracle types at once, e.g. as both
+	// PriceFeed and Provider prices, instead of the single OracleType. Stork is not supported.
+	OracleTypes []string `toml:"oracleTypes" yaml:"oracleTypes" json:"oracleTypes"`
+
+	// Symbol is the provider-specific symbol of the ticker, used by native provider feeds.
+	// If empty, it's derived from the ticker.
+	Symbol string `toml:"symbol" yaml:"symbol" json:"symbol"`
+
+	// APIKeyEnv is the name of the env variable holding the provider API key, used by native provider
+	// feeds that support authentication. The key itself is never put into feed configs.
+	APIKeyEnv string `toml:"apiKeyEnv" yaml:"apiKeyEnv" json:"apiKeyEnv"`
+
+	// MaxPriceAge rejects prices with a provider timestamp older than this duration, used by native
+	// provider feeds that report the price timestamp, and by dynamic feeds whose pipeline yields one.
+	// Empty means no staleness check.
+	MaxPriceAge string `toml:"maxPriceAge" yaml:"maxPriceAge" json:"maxPriceAge"`
+
+	// RPCEndpoint is the EVM JSON-RPC endpoint, used by native provider feeds reading on-chain data.
+	RPCEndpoint string `toml:"rpcEndpoint" yaml:"rpcEndpoint" json:"rpcEndpoint"`
+
+	// MinPrice and MaxPrice bound the prices accepted for submission, in the same units as submitted.
+	// Prices out of the band are dropped as likely upstream glitches. Empty means no bound.
+	MinPrice string `toml:"minPrice" yaml:"minPrice" json:"minPrice"`
+	MaxPrice string `toml:"maxPrice" yaml:"maxPrice" json:"maxPrice"`
+
+	// ReferenceSource is an optional observation source of an independent reference price. If set,
+	// pulled prices diverging from the reference by more than ReferenceMaxDeviation (e.g. 0.05 = 5%)
+	// are not submitted. Not supported by Stork feeds.
+	ReferenceSource       string `toml:"referenceSource" yaml:"referenceSource" json:"referenceSource"`
+	ReferenceMaxDeviation string `toml:"referenceMaxDeviation" yaml:"referenceMaxDeviation" json:"referenceMaxDeviation"`
+
+	// CrossCheckTicker is the ticker of a Stork feed of the same asset. If set, prices of either feed diverging
+	// from the latest price of the other by more than CrossCheckMaxDivergence (e.g. 0.02 = 2%) are not submitted.
+	// CrossCheckWeight (0 to 1, 0 if empty) weighs the Stork price in the weighted median submitted for this feed.
+	// Not supported by Stork feeds.
+	CrossCheckTicker        string `toml:"crossCheckTicker" yaml:"crossCheckTicker" json:"crossCheckTicker"`
+	CrossCheckMaxDivergence string `toml:"crossCheckMaxDivergence" yaml:"crossCheckMaxDivergence" json:"crossCheckMaxDivergence"`
+	CrossCheckWeight        string `toml:"crossCheckWeight" yaml:"crossCheckWeight" json:"crossCheckWeight"`
+
+	// SubscribeMessage overrides the global Stork websocket subscribe message template for Stork feeds.
+	// For generic websocket feeds, it's the message sent once connected.
+	SubscribeMessage string `toml:"subscribeMessage" yaml:"subscribeMessage" json:"subscribeMessage"`
+
+	// MaxSignedPrices caps the number of publisher signed prices submitted per Stork asset pair, keeping
+	// the freshest ones, so large pairs don't bloat the Tx. Zero means no limit.
+	MaxSignedPrices int `toml:"maxSignedPrices" yaml:"maxSignedPrices" json:"maxSignedPrices"`
+
+	// MinSubmitInterval is the minimum time between submissions of the feed, independent of the pull interval,
+	// so a source can be pulled often for monitoring but submitted less often. Empty means no minimum.
+	MinSubmitInterval string `toml:"minSubmitInterval" yaml:"minSubmitInterval" json:"minSubmitInterval"`
+
+	// Retries is the number of times a failed pull is retried within the pull interval, 3 if unset.
+	// RetryBackoff is the delay before the first retry, doubled on every next one, 1s if empty.
+	Retries      *int   `toml:"retries" yaml:"retries" json:"retries"`
+	RetryBackoff string `toml:"retryBackoff" yaml:"retryBackoff" json:"retryBackoff"`
+
+	// SubmitPrecision rounds prices to this number of decimal places before they're submitted, up to 18
+	// places of on-chain decimals. Unset means 18 places. Not applied to Stork signed prices.
+	SubmitPrecision *int `toml:"submitPrecision" yaml:"submitPrecision" json:"submitPrecision"`
+
+	// PriceScale multiplies prices relayed with MsgRelayPriceFeedPrice by 10^PriceScale, for markets expecting
+	// the price in another scale, e.g. in chain units of the quote denom. Between -18 and 18, unset means 0.
+	PriceScale *int `toml:"priceScale" yaml:"priceScale" json:"priceScale"`
+
+	// Outputs maps names of a pipeline result map to tickers, so a single observation source yields prices
+	// of many tickers in one pull, e.g. bid and ask. Outputs share the submit settings of the feed, e.g. the
+	// min submit interval, but not MinPrice and MaxPrice, which can't be set along with outputs.
+	Outputs map[string]string `toml:"outputs" yaml:"outputs" json:"outputs"`
+
+	// Market selects the market of providers listing both spot and futures, e.g. spot or futures for Binance.
+	Market string `toml:"market" yaml:"market" json:"market"`
+
+	// Region selects regional API endpoints of providers restricted in some regions, e.g. us for Binance.US.
+	// Empty means the global endpoints.
+	Region string `toml:"region" yaml:"region" json:"region"`
+
+	// Value is the fixed price of constant feeds.
+	Value string `toml:"value" yaml:"value" json:"value"`
+
+	// FallbackProvider is pulled instead of the feed's own provider once it has failed for FailoverAfter
+	// pull intervals in a row, until it recovers. Declared as a nested feed config, Stork is not supported.
+	FallbackProvider *FeedConfig `toml:"fallbackProvider" yaml:"fallbackProvider" json:"fallbackProvider"`
+	FailoverAfter    int         `toml:"failoverAfter" yaml:"failoverAfter" json:"failoverAfter"`
+
+	// Sources are the prioritized sources of fallback feeds, each declared as a nested feed config.
+	Sources []*FeedConfig `toml:"sources" yaml:"sources" json:"sources"`
+
+	// Base and Peg are the sources of conversion feeds, each declared as a nested feed config.
+	// The base price is multiplied by the peg price, e.g. BTC/USDT by USDT/USD for BTC/USD.
+	Base *FeedConfig `toml:"base" yaml:"base" json:"base"`
+	Peg  *FeedConfig `toml:"peg" yaml:"peg" json:"peg"`
+
+	// WsURL and JSONPath configure generic websocket feeds: the stream to connect to and
+	// the comma-separated path to the price in its messages.
+	WsURL    string `toml:"wsUrl" yaml:"wsUrl" json:"wsUrl"`
+	JSONPath string `toml:"jsonPath" yaml:"jsonPath" json:"jsonPath"`
+
+	// WsCompression toggles permessage-deflate of generic websocket feeds, enabled if unset.
+	WsCompression *bool `toml:"wsCompression" yaml:"wsCompression" json:"wsCompression"`
+
+	// WsReadLimit is the maximum size in bytes of a message of generic websocket feeds, larger messages
+	// drop the connection, which is re-established. pipeline.DefaultWebSocketReadLimit if unset.
+	WsReadLimit int64 `toml:"wsReadLimit" yaml:"wsReadLimit" json:"wsReadLimit"`
+}
+
+// ServiceConfig holds tunables of the oracle service main loop.
+type ServiceConfig struct {
+	// MaxConcurrentPulls caps the number of PullPrice calls running at once across all feeds,
+	// zero means no limit.
+	MaxConcurrentPulls int
+
+	// PullJitter is a fraction of the pull interval used to randomly spread feed pulls,
+	// so feeds with the same interval don't hit shared endpoints at once. Zero disables jitter.
+	PullJitter float64
+
+	// StartDelay is the delay of the first pull of each feed after start, defaults to 5s.
+	StartDelay time.Duration
+
+	// StartDelayWindow randomly spreads the first pulls of feeds over this window after StartDelay,
+	// capped at the feed pull interval. It takes precedence over PullJitter for the first pull, zero falls back to it.
+	StartDelayWindow time.Duration
+
+	// StateFile persists the last submitted price per ticker across restarts, so feeds submitted
+	// recently before a restart don't all submit again at once. Empty disables persistence.
+	StateFile string
+
+	// AuditLog is the path of an append-only JSON lines file recording every broadcast price batch.
+	// Empty disables the audit log.
+	AuditLog string
+
+	// MaxConcurrentBroadcasts is the number of price batches broadcast at once, defaults to 1.
+	MaxConcurrentBroadcasts int
+
+	// CommitStuckThreshold reports the commit loop as stuck when it hasn't processed a price or a batch
+	// timer tick, or a broadcast hasn't returned, within this duration. Zero disables the watchdog.
+	CommitStuckThreshold time.Duration
+
+	// HeightLagInterval is how often the lag between the latest chain height and the height of the latest
+	// successful submission is reported. Zero disables the lag gauge, the submission height is reported anyway.
+	HeightLagInterval time.Duration
+
+	// StrictFeeds fails the service init if no price feeds are loaded, or the relayer is authorized in none
+	// of the loaded PriceFeed feeds, instead of running idle.
+	StrictFeeds bool
+
+	// SelfTest checks the relayer can sign and is authorized in a loaded price feed before the main loop starts.
+	SelfTest bool
+
+	// Alert configures webhook alerts on repeated feed or broadcast failures.
+	Alert AlertConfig
+
+	// FeeBump configures rebroadcasts of Txs rejected for insufficient fees with bumped gas prices.
+	FeeBump FeeBumpConfig
+
+	// MirrorNetworks are submitted every price batch as well, in parallel with the main network.
+	MirrorNetworks []MirrorNetwork
+
+	// PriceTransform is applied to every pulled price before it's batched.
+	PriceTransform PriceTransformConfig
 }
 
 type oracleSvc struct {
@@ -51,7 +216,38 @@ type oracleSvc struct {
 	cosmosClient        chainclient.ChainClient
 	exchangeQueryClient exchangetypes.QueryClient
 	oracleQueryClient   oracletypes.QueryClient
+	storkFetcher        StorkFetcher
 	config              *StorkConfig
+	priceBounds         map[string]priceBounds
+	submitPrecisions    map[string]int32
+	priceScales         map[string]int32
+	minSubmitIntervals  map[string]time.Duration
+	retryPolicies       map[string]retryPolicy
+	extraOracleTypes    map[string][]oracletypes.OracleType
+	referenceChecks     map[string]*referenceCheck
+	crossChecks         map[string]*crossCheck
+	pullSem             chan struct{}
+	pullJitter          float64
+	startDelay          time.Duration
+	startDelayWindow    time.Duration
+	stateFile           string
+	lastSubmitted       map[string]SubmittedPrice
+	submittedMu         sync.RWMutex
+	auditLog            *auditLog
+	alerts              *alertNotifier
+	feeBump             *feeBumper
+	broadcastMu         sync.Mutex
+	mirrors             []*mirrorNetwork
+	runSelfTest         bool
+	transform           *priceTransform
+
+	maxConcurrentBroadcasts int
+	broadcastFailures       atomic.Int32
+	commitStuckThreshold    time.Duration
+	lastSubmittedHeight     atomic.Int64
+	heightLagInterval       time.Duration
+	activeFeeds             atomic.Int32
+	failingFeeds            atomic.Int32
 
 	logger  log.Logger
 	svcTags metrics.Tags
@@ -59,10 +255,12 @@ type oracleSvc struct {
 
 const (
 	maxRespTime                  = 15 * time.Second
+	defaultStartDelay            = 5 * time.Second
 	maxRespHeadersTime           = 15 * time.Second
 	maxRespBytes                 = 10 * 1024 * 1024
 	maxTxStatusRetries           = 3
 	maxRetriesPerInterval        = 3
+	defaultRetryBackoff          = time.Second
 	MaxRetriesReConnectWebSocket = 5
 )
 
@@ -77,9 +275,23 @@ func (f FeedProvider) String() string {
 }
 
 const (
-	FeedProviderDynamic FeedProvider = "_"
-	FeedProviderBinance FeedProvider = "binance"
-	FeedProviderStork   FeedProvider = "stork"
+	FeedProviderDynamic     FeedProvider = "_"
+	FeedProviderBinance     FeedProvider = "binance"
+	FeedProviderGateio      FeedProvider = "gateio"
+	FeedProviderKucoin      FeedProvider = "kucoin"
+	FeedProviderHTX         FeedProvider = "htx"
+	FeedProviderBitfinex    FeedProvider = "bitfinex"
+	FeedProviderDeribit     FeedProvider = "deribit"
+	FeedProviderDIA         FeedProvider = "dia"
+	FeedProviderRedstone    FeedProvider = "redstone"
+	FeedProviderAPI3        FeedProvider = "api3"
+	FeedProviderSwitchboard FeedProvider = "switchboard"
+	FeedProviderJupiter     FeedProvider = "jupiter"
+	FeedProviderWebsocket   FeedProvider = "websocket"
+	FeedProviderFallback    FeedProvider = "fallback"
+	FeedProviderConversion  FeedProvider = "conversion"
+	FeedProviderConstant    FeedProvider = "constant"
+	FeedProviderStork       FeedProvider = "stork"
 
 	// TODO: add your native implementations here
 )
@@ -93,11 +305,17 @@ type PriceFeedConfig struct {
 
 // getEnabledFeeds returns a mapping between ticker and price feeder config, this will query
 // the chain to fetch only those configs where current Cosmos sender is authorized as a relayer.
+// Returns nil if the chain can't be queried, e.g. when the service has no cosmos client.
 func (s *oracleSvc) getEnabledFeeds() map[string]PriceFeedConfig {
 	metrics.ReportFuncCall(s.svcTags)
 	doneFn := metrics.ReportFuncTiming(s.svcTags)
 	defer doneFn()
 
+	if s.cosmosClient == nil || s.oracleQueryClient == nil {
+		s.logger.Warningln("no oracle query client, cannot check which price feeds the sender is authorized in")
+		return nil
+	}
+
 	ctx, cancelFn := context.WithTimeout(context.Background(), 1*time.Minute)
 	defer cancelFn()
 
@@ -107,6 +325,7 @@ func (s *oracleSvc) getEnabledFeeds() map[string]PriceFeedConfig {
 	res, err := s.oracleQueryClient.PriceFeedPriceStates(ctx, &oracletypes.QueryPriceFeedPriceStatesRequest{})
 	if err != nil {
 		metrics.ReportFuncError(s.svcTags)
+		s.logger.WithError(err).Warningln("failed to query price feed states")
 		return nil
 	}
 
@@ -137,6 +356,59 @@ func (s *oracleSvc) getEnabledFeeds() map[string]PriceFeedConfig {
 	return feeds
 }
 
+// priceFeedTickers returns the sorted tickers of loaded feeds of the PriceFeed oracle type,
+// the only ones the relayer authorization can be queried of.
+func (s *oracleSvc) priceFeedTickers() []string {
+	var tickers []string
+	for ticker, pricePuller := range s.pricePullers {
+		if pricePuller.OracleType() == oracletypes.OracleType_PriceFeed {
+			tickers = append(tickers, ticker)
+		}
+	}
+
+	sort.Strings(tickers)
+	return tickers
+}
+
+// checkAuthorizedFeeds checks the relayer is authorized in the loaded PriceFeed feeds, since prices of
+// the rest are rejected on chain. If it's authorized in none of them, or that can't be queried, it fails
+// in strict mode and warns otherwise. Feeds it's not authorized in are always only warned about.
+func (s *oracleSvc) checkAuthorizedFeeds(strict bool) error {
+	tickers := s.priceFeedTickers()
+	if len(tickers) == 0 || s.cosmosClient == nil || s.oracleQueryClient == nil {
+		return nil
+	}
+
+	enabledFeeds := s.getEnabledFeeds()
+	if enabledFeeds == nil {
+		if strict {
+			return errors.New("failed to query relayers of price feeds, cannot check the relayer is authorized in the loaded feeds")
+		}
+
+		return nil
+	}
+
+	var unauthorized []string
+	for _, ticker := range tickers {
+		if _, ok := enabledFeeds[ticker]; !ok {
+			unauthorized = append(unauthorized, ticker)
+		}
+	}
+
+	relayer := s.cosmosClient.FromAddress().String()
+	if len(unauthorized) == len(tickers) {
+		if strict {
+			return errors.Errorf("relayer %s is not authorized in any of the loaded price feeds: %s", relayer, strings.Join(tickers, ", "))
+		}
+
+		s.logger.WithField("relayer", relayer).Warningf("relayer is not authorized in any of the loaded price feeds, their prices will be rejected! Check the relayer key, or pass --strict-feeds to fail instead: %s", strings.Join(tickers, ", "))
+	} else if len(unauthorized) > 0 {
+		s.logger.WithField("relayer", relayer).Warningf("relayer is not authorized in %d of %d loaded price feeds, their prices will be rejected: %s", len(unauthorized), len(tickers), strings.Join(unauthorized, ", "))
+	}
+
+	return nil
+}
+
 func NewService(
 	_ context.Context,
 	cosmosClient chainclient.ChainClient,
@@ -144,11 +416,19 @@ func NewService(
 	oracleQueryClient oracletypes.QueryClient,
 	feedConfigs map[string]*FeedConfig,
 	storkFetcher StorkFetcher,
+	cfg ServiceConfig,
 ) (Service, error) {
 	svc := &oracleSvc{
 		cosmosClient:        cosmosClient,
 		exchangeQueryClient: exchangeQueryClient,
 		oracleQueryClient:   oracleQueryClient,
+		storkFetcher:        storkFetcher,
+		pullJitter:          cfg.PullJitter,
+		startDelay:          defaultStartDelay,
+		startDelayWindow:    cfg.StartDelayWindow,
+		stateFile:           cfg.StateFile,
+		lastSubmitted:       map[string]SubmittedPrice{},
+		alerts:              newAlertNotifier(cfg.Alert),
 
 		logger: log.WithField("svc", "oracle"),
 		svcTags: metrics.Tags{
@@ -156,6 +436,72 @@ func NewService(
 		},
 	}
 
+	// query clients default to ones over the gRPC connection of the cosmos client
+	if cosmosClient != nil {
+		if conn := cosmosClient.QueryClient(); conn != nil {
+			if svc.exchangeQueryClient == nil {
+				svc.exchangeQueryClient = exchangetypes.NewQueryClient(conn)
+			}
+
+			if svc.oracleQueryClient == nil {
+				svc.oracleQueryClient = oracletypes.NewQueryClient(conn)
+			}
+		}
+	}
+
+	if cfg.MaxConcurrentPulls > 0 {
+		svc.pullSem = make(chan struct{}, cfg.MaxConcurrentPulls)
+	}
+
+	if cfg.StartDelay > 0 {
+		svc.startDelay = cfg.StartDelay
+	}
+
+	svc.maxConcurrentBroadcasts = 1
+	if cfg.MaxConcurrentBroadcasts > 0 {
+		svc.maxConcurrentBroadcasts = cfg.MaxConcurrentBroadcasts
+	}
+
+	if cfg.CommitStuckThreshold > 0 && cfg.CommitStuckThreshold < 2*commitPriceBatchTimeLimit {
+		return nil, errors.Errorf("commit stuck threshold %s is too short, it must be at least %s", cfg.CommitStuckThreshold, 2*commitPriceBatchTimeLimit)
+	}
+	svc.commitStuckThreshold = cfg.CommitStuckThreshold
+	svc.runSelfTest = cfg.SelfTest
+	svc.heightLagInterval = cfg.HeightLagInterval
+
+	feeBump, err := newFeeBumper(cfg.FeeBump)
+	if err != nil {
+		return nil, err
+	}
+	svc.feeBump = feeBump
+
+	if svc.mirrors, err = newMirrorNetworks(cfg.MirrorNetworks); err != nil {
+		return nil, err
+	}
+
+	if svc.transform, err = newPriceTransform(cfg.PriceTransform); err != nil {
+		return nil, err
+	}
+
+	if len(cfg.StateFile) > 0 {
+		lastSubmitted, err := LoadSubmittedPrices(cfg.StateFile)
+		if err != nil {
+			return nil, err
+		}
+
+		svc.lastSubmitted = lastSubmitted
+		svc.logger.Infof("loaded last submitted prices of %d tickers", len(lastSubmitted))
+	}
+
+	if len(cfg.AuditLog) > 0 {
+		auditLog, err := openAuditLog(cfg.AuditLog)
+		if err != nil {
+			return nil, err
+		}
+
+		svc.auditLog = auditLog
+	}
+
 	// supportedPriceFeeds is a mapping between price ticker and its pricefeed config
 	svc.supportedPriceFeeds = map[string]PriceFeedConfig{}
 	for _, feedCfg := range feedConfigs {
@@ -165,49 +511,200 @@ func NewService(
 		}
 	}
 
+	if err := checkOutputTickers(feedConfigs); err != nil {
+		return nil, err
+	}
+
+	svc.priceBounds = map[string]priceBounds{}
+	svc.submitPrecisions = map[string]int32{}
+	svc.priceScales = map[string]int32{}
+	svc.minSubmitIntervals = map[string]time.Duration{}
+	svc.retryPolicies = map[string]retryPolicy{}
+	svc.extraOracleTypes = map[string][]oracletypes.OracleType{}
+	svc.referenceChecks = map[string]*referenceCheck{}
 	svc.pricePullers = map[string]PricePuller{}
 	for _, feedCfg := range feedConfigs {
-		switch feedCfg.ProviderName {
-		case FeedProviderStork.String():
-			ticker := feedCfg.Ticker
-			pricePuller, err := NewStorkPriceFeed(storkFetcher, feedCfg)
-			if err != nil {
-				err = errors.Wrapf(err, "failed to init stork price feed for ticker %s", ticker)
-				return nil, err
-			}
-			svc.pricePullers[ticker] = pricePuller
-		default: // TODO this should be replaced with correct providers
-			ticker := feedCfg.Ticker
-			pricePuller, err := NewDynamicPriceFeed(feedCfg)
-			if err != nil {
-				err = errors.Wrapf(err, "failed to init dynamic price feed for ticker %s", ticker)
-				return nil, err
-			}
-			svc.pricePullers[ticker] = pricePuller
+		bounds, err := feedCfg.priceBounds()
+		if err != nil {
+			err = errors.Wrapf(err, "invalid price bounds for ticker %s", feedCfg.Ticker)
+			return nil, err
 		}
+		svc.priceBounds[feedCfg.Ticker] = bounds
+
+		precision, err := feedCfg.submitPrecision()
+		if err != nil {
+			err = errors.Wrapf(err, "invalid submit precision for ticker %s", feedCfg.Ticker)
+			return nil, err
+		}
+		svc.submitPrecisions[feedCfg.Ticker] = precision
+
+		priceScale, err := feedCfg.priceScale()
+		if err != nil {
+			err = errors.Wrapf(err, "invalid price scale for ticker %s", feedCfg.Ticker)
+			return nil, err
+		} else if priceScale != 0 {
+			svc.priceScales[feedCfg.Ticker] = priceScale
+		}
+
+		minSubmitInterval, err := feedCfg.minSubmitInterval()
+		if err != nil {
+			err = errors.Wrapf(err, "invalid min submit interval for ticker %s", feedCfg.Ticker)
+			return nil, err
+		} else if minSubmitInterval > 0 {
+			svc.minSubmitIntervals[feedCfg.Ticker] = minSubmitInterval
+		}
+
+		retries, err := feedCfg.retryPolicy()
+		if err != nil {
+			err = errors.Wrapf(err, "invalid retry policy for ticker %s", feedCfg.Ticker)
+			return nil, err
+		}
+		svc.retryPolicies[feedCfg.Ticker] = retries
+
+		check, err := newReferenceCheck(feedCfg)
+		if err != nil {
+			err = errors.Wrapf(err, "invalid reference source for ticker %s", feedCfg.Ticker)
+			return nil, err
+		} else if check != nil {
+			svc.referenceChecks[feedCfg.Ticker] = check
+		}
+
+		oracleTypes, err := feedCfg.oracleTypes()
+		if err != nil {
+			err = errors.Wrapf(err, "invalid oracle types for ticker %s", feedCfg.Ticker)
+			return nil, err
+		} else if len(oracleTypes) > 1 {
+			svc.extraOracleTypes[feedCfg.Ticker] = oracleTypes[1:]
+		}
+
+		pricePuller, err := NewPricePuller(feedCfg, storkFetcher)
+		if err != nil {
+			err = errors.Wrapf(err, "failed to init %s price feed for ticker %s", feedCfg.ProviderName, feedCfg.Ticker)
+			return nil, err
+		}
+		svc.pricePullers[feedCfg.Ticker] = pricePuller
+		svc.registerOutputTickers(feedCfg)
+	}
+
+	if svc.crossChecks, err = newCrossChecks(feedConfigs, svc.pricePullers); err != nil {
+		return nil, err
+	}
+
+	if len(svc.pricePullers) == 0 {
+		if cfg.StrictFeeds {
+			return nil, errors.New("no price feeds loaded, check the feeds dir and the --only-feed tickers")
+		}
+
+		svc.logger.Warningln("no price feeds loaded, the oracle will stay idle and submit no prices! Check the feeds dir and the --only-feed tickers, or pass --strict-feeds to fail instead")
+		return svc, nil
+	}
+
+	if err := svc.checkAuthorizedFeeds(cfg.StrictFeeds); err != nil {
+		return nil, err
 	}
 
 	svc.logger.Infof("initialized %d price pullers", len(svc.pricePullers))
 	return svc, nil
 }
 
+// NewPricePuller inits a price puller for the feed config, picking the implementation by its provider.
+// Feeds of providers without a native implementation are run as dynamic feeds.
+func NewPricePuller(feedCfg *FeedConfig, storkFetcher StorkFetcher) (PricePuller, error) {
+	// the puller pulls for the first of many oracle types, the service copies its prices to the rest
+	if len(feedCfg.OracleTypes) > 0 && len(feedCfg.OracleType) == 0 {
+		primaryCfg := *feedCfg
+		primaryCfg.OracleType = feedCfg.OracleTypes[0]
+		feedCfg = &primaryCfg
+	}
+
+	pricePuller, err := newPricePuller(feedCfg, storkFetcher)
+	if err != nil {
+		return nil, err
+	}
+
+	// only pipelines yield prices of many tickers
+	if _, ok := pricePuller.(MultiPricePuller); len(feedCfg.Outputs) > 0 && !ok {
+		return nil, errors.Errorf("outputs are not supported by %s provider", feedCfg.ProviderName)
+	}
+
+	if feedCfg.FallbackProvider != nil {
+		return newFailoverPriceFeed(pricePuller, feedCfg)
+	}
+
+	return pricePuller, nil
+}
+
+func newPricePuller(feedCfg *FeedConfig, storkFetcher StorkFetcher) (PricePuller, error) {
+	switch FeedProvider(feedCfg.ProviderName) {
+	case FeedProviderStork:
+		return NewStorkPriceFeed(storkFetcher, feedCfg)
+	case FeedProviderBinance:
+		return NewBinancePriceFeed(feedCfg)
+	case FeedProviderGateio:
+		return NewGateioPriceFeed(feedCfg)
+	case FeedProviderKucoin:
+		return NewKucoinPriceFeed(feedCfg)
+	case FeedProviderHTX:
+		return NewHTXPriceFeed(feedCfg)
+	case FeedProviderBitfinex:
+		return NewBitfinexPriceFeed(feedCfg)
+	case FeedProviderDeribit:
+		return NewDeribitPriceFeed(feedCfg)
+	case FeedProviderDIA:
+		return NewDIAPriceFeed(feedCfg)
+	case FeedProviderRedstone:
+		return NewRedstonePriceFeed(feedCfg)
+	case FeedProviderAPI3:
+		return NewAPI3PriceFeed(feedCfg)
+	case FeedProviderSwitchboard:
+		return NewSwitchboardPriceFeed(feedCfg)
+	case FeedProviderJupiter:
+		return NewJupiterPriceFeed(feedCfg)
+	case FeedProviderWebsocket:
+		return NewGenericWSPriceFeed(feedCfg)
+	case FeedProviderFallback:
+		return NewFallbackPriceFeed(feedCfg)
+	case FeedProviderConversion:
+		return NewConversionPriceFeed(feedCfg)
+	case FeedProviderConstant:
+		return NewConstantPriceFeed(feedCfg)
+	default:
+		return NewDynamicPriceFeed(feedCfg)
+	}
+}
+
 func (s *oracleSvc) Start() (err error) {
 	defer s.panicRecover(&err)
 
+	if s.runSelfTest {
+		if err := s.selfTest(); err != nil {
+			return err
+		}
+	}
+
 	if len(s.pricePullers) > 0 {
+		s.checkPriceScales()
+
 		s.logger.Infoln("starting pullers for", len(s.pricePullers), "feeds")
 
 		dataC := make(chan *PriceData, len(s.pricePullers))
 
 		for ticker, pricePuller := range s.pricePullers {
 			switch pricePuller.Provider() {
-			case FeedProviderBinance, FeedProviderStork, FeedProviderDynamic:
+			case FeedProviderBinance, FeedProviderStork, FeedProviderDynamic,
+				FeedProviderGateio, FeedProviderKucoin, FeedProviderHTX, FeedProviderBitfinex,
+				FeedProviderDeribit, FeedProviderDIA, FeedProviderRedstone, FeedProviderAPI3,
+				FeedProviderSwitchboard, FeedProviderJupiter, FeedProviderWebsocket, FeedProviderFallback,
+				FeedProviderConversion, FeedProviderConstant:
+				s.activeFeeds.Add(1)
 				go s.processSetPriceFeed(ticker, pricePuller, dataC)
 			default:
 				s.logger.WithField("provider", pricePuller.Provider()).Warningln("unsupported price feed provider")
 			}
 		}
 
+		s.reportFeedInventory()
+
 		s.commitSetPrices(dataC)
 	}
 
@@ -222,45 +719,133 @@ func (s *oracleSvc) processSetPriceFeed(ticker string, pricePuller PricePuller,
 
 	symbol := pricePuller.Symbol()
 
-	t := time.NewTimer(5 * time.Second)
+	startIn := initialPullDelay(s.startDelay, s.startDelayWindow, pricePuller.Interval(), s.pullJitter)
+
+	// a price submitted recently before a restart is not due again until its interval elapses
+	if lastSubmitted, ok := s.lastSubmittedPrice(ticker); ok {
+		if dueIn := pricePuller.Interval() - time.Since(lastSubmitted.Timestamp); dueIn > startIn {
+			feedLogger.WithField("last_submitted", lastSubmitted.Timestamp).Infof("delaying first pull by %s", dueIn)
+			startIn = dueIn
+		}
+	}
+
+	// consecutive failed pulls, alerted on once the threshold is reached
+	var failures int
+
+	t := time.NewTimer(startIn)
 	for {
 		select {
 		case <-t.C:
-			ctx, cancelFn := context.WithTimeout(context.Background(), maxRespTime)
-			defer cancelFn()
-
-			result, err := pricePuller.PullPrice(ctx)
+			retries := s.retryPolicyOf(ticker)
 
+			results, err := s.pullPriceWithRetries(pricePuller, retries, feedLogger)
 			if err != nil {
 				metrics.ReportFuncError(s.svcTags)
-				feedLogger.WithError(err).Warningln("retrying PullPrice after error")
-
-				for i := 0; i < maxRetriesPerInterval; i++ {
-					if result, err = pricePuller.PullPrice(ctx); err != nil {
-						time.Sleep(time.Second)
-						continue
-					}
-					break
-				}
-
-				if err != nil {
-					metrics.ReportFuncError(s.svcTags)
+				if errors.Is(err, ErrFeedConfig) {
+					// retrying would fail the same way until the config is fixed
+					feedLogger.WithError(err).Errorln("failed to fetch price due to feed config error, not retrying")
+				} else {
 					feedLogger.WithFields(log.Fields{
 						"symbol":  symbol,
-						"retries": maxRetriesPerInterval,
+						"retries": retries.retries,
 					}).WithError(err).Errorln("failed to fetch price")
+				}
 
-					t.Reset(pricePuller.Interval())
-					continue
+				failures++
+				if failures == 1 {
+					s.setFeedFailing(true)
 				}
+				s.alerts.failed(fmt.Sprintf("feed %s (%s)", ticker, pricePuller.ProviderName()), failures, err)
+
+				t.Reset(withJitter(pricePuller.Interval(), s.pullJitter))
+				continue
 			}
 
-			if result != nil {
-				dataC <- result
+			if failures > 0 {
+				s.setFeedFailing(false)
 			}
+			failures = 0
+
+			for _, result := range results {
+				ctx, cancelFn := context.WithTimeout(context.Background(), maxRespTime)
+				matches := s.matchesReferencePrice(ctx, result)
+				cancelFn()
+
+				if matches {
+					for _, priceData := range s.withExtraOracleTypes(result) {
+						dataC <- priceData
+					}
+				} else {
+					s.reportSkippedPrice(result, skipReasonReferenceDeviation)
+					s.releaseAssetPairs([]*PriceData{result})
+				}
+			}
+
+			t.Reset(withJitter(pricePuller.Interval(), s.pullJitter))
+		}
+	}
+}
+
+// pullPriceWithRetries pulls the price, retrying a failed pull with the given policy. Every attempt
+// has its own timeout. Feed config errors are not retried, since they fail every pull the same way.
+func (s *oracleSvc) pullPriceWithRetries(pricePuller PricePuller, retries retryPolicy, feedLogger log.Logger) ([]*PriceData, error) {
+	backoff := retries.backoff
+
+	for retry := 0; ; retry++ {
+		ctx, cancelFn := context.WithTimeout(context.Background(), maxRespTime)
+		result, err := s.pullPrices(ctx, pricePuller)
+		cancelFn()
+
+		pulledAt := time.Now()
+		for _, priceData := range result {
+			priceData.PulledAt = pulledAt
+		}
 
-			t.Reset(pricePuller.Interval())
+		if err == nil || errors.Is(err, ErrFeedConfig) || retry >= retries.retries {
+			return result, err
 		}
+
+		metrics.ReportFuncError(s.svcTags)
+		feedLogger.WithError(err).Warningf("retrying PullPrice in %s after error", backoff)
+
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+}
+
+// retryPolicyOf returns the retry policy of the ticker, or the default one.
+func (s *oracleSvc) retryPolicyOf(ticker string) retryPolicy {
+	if retries, ok := s.retryPolicies[ticker]; ok {
+		return retries
+	}
+
+	return defaultRetryPolicy
+}
+
+// pullPrice runs PullPrice of the given puller, waiting for a free slot first
+// if the number of concurrent pulls is limited.
+func (s *oracleSvc) pullPrice(ctx context.Context, pricePuller PricePuller) (*PriceData, error) {
+	release, err := s.acquirePullSlot(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	return pricePuller.PullPrice(ctx)
+}
+
+// acquirePullSlot waits for a free pull slot if the number of concurrent pulls is limited,
+// returns a func releasing the slot.
+func (s *oracleSvc) acquirePullSlot(ctx context.Context) (release func(), err error) {
+	if s.pullSem == nil {
+		return func() {}, nil
+	}
+
+	select {
+	case s.pullSem <- struct{}{}:
+		return func() { <-s.pullSem }, nil
+	case <-ctx.Done():
+		return nil, errors.Wrap(ctx.Err(), "timed out waiting for a free pull slot")
 	}
 }
 
@@ -279,9 +864,15 @@ func (s *oracleSvc) composePriceFeedMsgs(priceBatch []*PriceData) (results []cos
 			continue
 		}
 
+		price := s.scaledPriceFeedPrice(priceData)
+		if price.IsZero() {
+			s.reportSkippedPrice(priceData, skipReasonZeroPrice)
+			continue
+		}
+
 		msg.Base = append(msg.Base, priceData.Ticker.Base())
 		msg.Quote = append(msg.Quote, priceData.Ticker.Quote())
-		msg.Price = append(msg.Price, math.LegacyMustNewDecFromStr(priceData.Price.String()))
+		msg.Price = append(msg.Price, math.LegacyMustNewDecFromStr(price.String()))
 	}
 
 	if len(msg.Base) > 0 {
@@ -359,6 +950,9 @@ func (s *oracleSvc) composeMsgs(priceBatch []*PriceData) (result []cosmtypes.Msg
 	return result
 }
 
+// commitSetPrices batches the pulled prices and hands the batches over to a pool of broadcast workers,
+// so a slow broadcast doesn't stall batching. A price is never in two batches being broadcast at once,
+// prices of a symbol in flight are held for the next batch, keeping only the latest one.
 func (s *oracleSvc) commitSetPrices(dataC <-chan *PriceData) {
 	metrics.ReportFuncCall(s.svcTags)
 	doneFn := metrics.ReportFuncTiming(s.svcTags)
@@ -366,108 +960,321 @@ func (s *oracleSvc) commitSetPrices(dataC <-chan *PriceData) {
 
 	expirationTimer := time.NewTimer(commitPriceBatchTimeLimit)
 	pricesBatch := make(map[string]*PriceData)
-	pricesMeta := make(map[string]int)
-
-	resetBatch := func() (map[string]*PriceData, map[string]int) {
-		expirationTimer.Reset(commitPriceBatchTimeLimit)
+	pool := newBroadcastPool(s, s.maxConcurrentBroadcasts)
 
-		prev := pricesBatch
-		prevMeta := pricesMeta
-		pricesBatch = make(map[string]*PriceData)
-		pricesMeta = make(map[string]int)
-		return prev, prevMeta
-	}
+	var lastTick atomic.Int64
+	lastTick.Store(time.Now().UnixNano())
 
-	submitBatch := func(currentBatch map[string]*PriceData, currentMeta map[string]int, timeout bool) {
-		if len(currentBatch) == 0 {
-			return
-		}
+	if s.commitStuckThreshold > 0 {
+		watchDone := make(chan struct{})
+		defer close(watchDone)
 
-		batchLog := s.logger.WithFields(log.Fields{
-			"batch_size": len(currentBatch),
-			"timeout":    timeout,
-		})
+		go s.watchCommitLoop(pool, &lastTick, watchDone)
+	}
 
-		var priceBatch []*PriceData
-		for _, msg := range currentBatch {
-			priceBatch = append(priceBatch, msg)
-		}
+	if s.heightLagInterval > 0 {
+		lagDone := make(chan struct{})
+		defer close(lagDone)
 
-		msgs := s.composeMsgs(priceBatch)
-		if len(msgs) == 0 {
-			batchLog.Debugf("pipeline composed no messages, so do nothing")
-			return
-		}
+		go s.watchHeightLag(lagDone)
+	}
 
-		ts := time.Now()
-		txResp, err := s.cosmosClient.SyncBroadcastMsg(msgs...)
-		if err != nil {
-			metrics.ReportFuncError(s.svcTags)
-			batchLog.WithError(err).Errorln("failed to SyncBroadcastMsg")
-			return
-		}
+	inventoryDone := make(chan struct{})
+	defer close(inventoryDone)
 
-		if txResp.TxResponse != nil {
-			if txResp.TxResponse.Code != 0 {
-				metrics.ReportFuncError(s.svcTags)
-				batchLog.WithFields(log.Fields{
-					"hash":     txResp.TxResponse.TxHash,
-					"err_code": txResp.TxResponse.Code,
-				}).Errorf("set price Tx error: %s", txResp.String())
+	go s.watchFeedInventory(inventoryDone)
 
-				return
-			}
-			for oracleType, count := range currentMeta {
-				metrics.CustomReport(func(s metrics.Statter, tagSpec []string) {
-					s.Count(fmt.Sprintf("price_oracle.%s.submitted.price.size", strings.ToLower(oracleType)), int64(count), tagSpec, 1)
-				}, s.svcTags)
-			}
-			batchLog.WithField("height", txResp.TxResponse.Height).
-				WithField("hash", txResp.TxResponse.TxHash).
-				Infoln("sent Tx in", time.Since(ts))
-		}
+	dispatchBatch := func(timeout, wait bool) {
+		expirationTimer.Reset(commitPriceBatchTimeLimit)
+		pool.dispatch(pricesBatch, timeout, wait)
 	}
 
 	for {
 		select {
 		case priceData, ok := <-dataC:
+			lastTick.Store(time.Now().UnixNano())
+
 			if !ok {
 				s.logger.Infoln("stopping committing prices")
-				prevBatch, prevMeta := resetBatch()
-				submitBatch(prevBatch, prevMeta, false)
+				dispatchBatch(false, true)
+				pool.close()
+
+				// prices held back while their symbol was in flight
+				s.broadcastBatch(pricesBatch, false)
 				return
 			}
-			if priceData.OracleType == oracletypes.OracleType_Stork {
-				if priceData.AssetPair == nil {
-					s.logger.WithFields(log.Fields{
-						"ticker":   priceData.Ticker,
-						"provider": priceData.ProviderName,
-					}).Debugln("got nil asset pair for stork oracle, skipping")
-					continue
-				}
-			} else {
-				if priceData.Price.IsZero() || priceData.Price.IsNegative() {
-					s.logger.WithFields(log.Fields{
-						"ticker":   priceData.Ticker,
-						"provider": priceData.ProviderName,
-					}).Debugln("got negative or zero price, skipping")
-					continue
-				}
+			priceData, skipReason := s.preparePrice(priceData)
+			if len(skipReason) > 0 {
+				s.reportSkippedPrice(priceData, skipReason)
+				s.releaseAssetPairs([]*PriceData{priceData})
+				continue
 			}
-			pricesMeta[priceData.OracleType.String()]++
 			pricesBatch[priceData.OracleType.String()+":"+priceData.Symbol] = priceData
 
 			if len(pricesBatch) >= commitPriceBatchSizeLimit {
-				prevBatch, prevMeta := resetBatch()
-				submitBatch(prevBatch, prevMeta, false)
+				dispatchBatch(false, true)
 			}
 		case <-expirationTimer.C:
-			prevBatch, prevMeta := resetBatch()
-			submitBatch(prevBatch, prevMeta, true)
+			lastTick.Store(time.Now().UnixNano())
+			dispatchBatch(true, false)
 		}
 	}
 }
 
+// releaseAssetPairs hands the Stork asset pairs of prices not submitted back to their feeds, either skipped
+// before broadcast or of a failed broadcast, so the same signed prices are submitted again instead of being
+// skipped as already sent.
+func (s *oracleSvc) releaseAssetPairs(priceBatch []*PriceData) {
+	for _, priceData := range priceBatch {
+		if priceData.AssetPair == nil {
+			continue
+		}
+
+		if releaser, ok := s.pricePullers[string(priceData.Ticker)].(assetPairReleaser); ok {
+			releaser.releaseAssetPair(priceData.AssetPair)
+		}
+	}
+}
+
+// preparePrice transforms, rounds and checks a pulled price before it's batched for submission.
+// Returns the price to submit, or the reason it's skipped.
+func (s *oracleSvc) preparePrice(priceData *PriceData) (*PriceData, string) {
+	if priceData.OracleType == oracletypes.OracleType_Stork {
+		if priceData.AssetPair == nil {
+			return priceData, skipReasonNoAssetPair
+		}
+	} else {
+		priceData = s.roundToSubmitPrecision(s.transformPrice(priceData))
+
+		if priceData.Price.IsZero() {
+			return priceData, skipReasonZeroPrice
+		} else if priceData.Price.IsNegative() {
+			return priceData, skipReasonNegative
+		}
+	}
+
+	if !s.withinPriceBounds(priceData) {
+		return priceData, skipReasonOutOfRange
+	}
+
+	crossChecked, consistent := s.crossCheckPrice(priceData)
+	if !consistent {
+		return priceData, skipReasonCrossCheckDivergence
+	}
+	priceData = crossChecked
+
+	if !s.submitDue(priceData) {
+		return priceData, skipReasonTooFrequent
+	}
+
+	return priceData, ""
+}
+
+// broadcastBatch composes messages of the price batch and broadcasts them in a single Tx, to the mirror networks as well.
+func (s *oracleSvc) broadcastBatch(currentBatch map[string]*PriceData, timeout bool) {
+	if len(currentBatch) == 0 {
+		return
+	}
+
+	batchLog := s.logger.WithFields(log.Fields{
+		"batch_size": len(currentBatch),
+		"timeout":    timeout,
+	})
+
+	var priceBatch []*PriceData
+	batchMeta := make(map[string]int)
+	for _, msg := range currentBatch {
+		priceBatch = append(priceBatch, msg)
+		batchMeta[msg.OracleType.String()]++
+	}
+
+	msgs := s.composeMsgs(priceBatch)
+	if len(msgs) == 0 {
+		batchLog.Debugf("pipeline composed no messages, so do nothing")
+		debugLogBatch(batchLog, priceBatch)
+		s.releaseAssetPairs(priceBatch)
+		return
+	}
+
+	debugLogMsgs(batchLog, msgs)
+
+	waitMirrors := s.broadcastToMirrors(msgs, batchMeta)
+	defer waitMirrors()
+
+	ts := time.Now()
+	txResp, err := s.broadcastMsgs(msgs)
+
+	if err != nil {
+		metrics.ReportFuncError(s.svcTags)
+		batchLog.WithError(err).Errorln("failed to SyncBroadcastMsg")
+		s.releaseAssetPairs(priceBatch)
+
+		s.alerts.failed("broadcast", int(s.broadcastFailures.Add(1)), err)
+		return
+	}
+
+	if txResp.TxResponse != nil {
+		s.auditBroadcast(priceBatch, txResp.TxResponse.TxHash, txResp.TxResponse.Height, txResp.TxResponse.Code, txResp.TxResponse.RawLog)
+
+		if txResp.TxResponse.Code != 0 {
+			metrics.ReportFuncError(s.svcTags)
+			batchLog.WithFields(log.Fields{
+				"hash":     txResp.TxResponse.TxHash,
+				"err_code": txResp.TxResponse.Code,
+			}).Errorf("set price Tx error: %s", txResp.String())
+			s.releaseAssetPairs(priceBatch)
+
+			s.alerts.failed("broadcast", int(s.broadcastFailures.Add(1)), errors.Errorf("Tx error code %d: %s", txResp.TxResponse.Code, txResp.TxResponse.RawLog))
+			return
+		}
+
+		s.broadcastFailures.Store(0)
+
+		for oracleType, count := range batchMeta {
+			metrics.CustomReport(func(s metrics.Statter, tagSpec []string) {
+				s.Count(fmt.Sprintf("price_oracle.%s.submitted.price.size", strings.ToLower(oracleType)), int64(count), tagSpec, 1)
+			}, s.svcTags)
+		}
+
+		// report the end-to-end latency from the price pull to on-chain inclusion
+		submittedAt := time.Now()
+		for _, priceData := range priceBatch {
+			latency := submittedAt.Sub(priceData.PulledAt)
+			metrics.CustomReport(func(s metrics.Statter, tagSpec []string) {
+				s.Timing("price_oracle.pull_to_submit.latency", latency, tagSpec, 1)
+			}, tickerTags(priceData.Ticker))
+		}
+
+		s.recordSubmittedPrices(priceBatch, submittedAt)
+		s.reportSubmittedHeight(txResp.TxResponse.Height)
+
+		batchLog.WithField("height", txResp.TxResponse.Height).
+			WithField("hash", txResp.TxResponse.TxHash).
+			Infoln("sent Tx in", time.Since(ts))
+	}
+}
+
+// submitDue checks whether the min submit interval of the feed has elapsed since its last submission.
+func (s *oracleSvc) submitDue(priceData *PriceData) bool {
+	minSubmitInterval, ok := s.minSubmitIntervals[string(priceData.Ticker)]
+	if !ok {
+		return true
+	}
+
+	lastSubmitted, ok := s.lastSubmittedPrice(string(priceData.Ticker))
+	if !ok {
+		return true
+	}
+
+	return time.Since(lastSubmitted.Timestamp) >= minSubmitInterval
+}
+
+// Reasons of prices skipped before entering the Tx batch.
+const (
+	skipReasonNoAssetPair          = "no_asset_pair"
+	skipReasonZeroPrice            = "zero_price"
+	skipReasonNegative             = "negative"
+	skipReasonOutOfRange           = "out_of_range"
+	skipReasonTooFrequent          = "too_frequent"
+	skipReasonReferenceDeviation   = "reference_deviation"
+	skipReasonCrossCheckDivergence = "cross_check_divergence"
+)
+
+// reportSkippedPrice logs and counts a pulled price that is not submitted, by the reason,
+// so it's visible why a feed isn't updating.
+func (s *oracleSvc) reportSkippedPrice(priceData *PriceData, reason string) {
+	// fresh tags, since Tags.With adds the reason to the service tags in place
+	tags := metrics.Tags{
+		"svc":    "price_oracle",
+		"ticker": string(priceData.Ticker),
+		"reason": reason,
+	}
+
+	metrics.CustomReport(func(s metrics.Statter, tagSpec []string) {
+		s.Count("price_oracle.submission.skipped.size", 1, tagSpec, 1)
+	}, tags)
+
+	s.logger.WithFields(log.Fields{
+		"ticker":   priceData.Ticker,
+		"provider": priceData.ProviderName,
+		"reason":   reason,
+	}).Debugln("skipping price submission")
+}
+
+// withExtraOracleTypes returns the price data along with its copies for the extra oracle types of the feed,
+// if it's submitted to many oracle types at once.
+func (s *oracleSvc) withExtraOracleTypes(priceData *PriceData) []*PriceData {
+	extraTypes := s.extraOracleTypes[string(priceData.Ticker)]
+
+	prices := make([]*PriceData, 0, 1+len(extraTypes))
+	prices = append(prices, priceData)
+	for _, oracleType := range extraTypes {
+		extraData := *priceData
+		extraData.OracleType = oracleType
+		prices = append(prices, &extraData)
+	}
+
+	return prices
+}
+
+// roundToSubmitPrecision returns the price data with the price rounded to the submit precision of its feed,
+// copying it if the price changes, since streaming feeds may hand out the same price data more than once.
+func (s *oracleSvc) roundToSubmitPrecision(priceData *PriceData) *PriceData {
+	precision, ok := s.submitPrecisions[string(priceData.Ticker)]
+	if !ok {
+		precision = maxSubmitPrecision
+	}
+
+	rounded := priceData.Price.Round(precision)
+	if rounded.Equal(priceData.Price) {
+		return priceData
+	}
+
+	roundedData := *priceData
+	roundedData.Price = rounded
+	return &roundedData
+}
+
+// withinPriceBounds checks the price against min/max bounds of its feed, loudly reporting prices out of the band.
+// For Stork, every signed price of the asset pair must be within the band.
+func (s *oracleSvc) withinPriceBounds(priceData *PriceData) bool {
+	bounds, ok := s.priceBounds[string(priceData.Ticker)]
+	if !ok || (bounds.min == nil && bounds.max == nil) {
+		return true
+	}
+
+	var prices []decimal.Decimal
+	if priceData.OracleType == oracletypes.OracleType_Stork {
+		for _, signedPrice := range priceData.AssetPair.SignedPrices {
+			prices = append(prices, decimal.RequireFromString(signedPrice.Price.String()))
+		}
+	} else {
+		prices = append(prices, priceData.Price)
+	}
+
+	for _, price := range prices {
+		if bounds.contains(price) {
+			continue
+		}
+
+		metrics.CustomReport(func(s metrics.Statter, tagSpec []string) {
+			s.Count("price_oracle.price_out_of_bounds.size", 1, tagSpec, 1)
+		}, tickerTags(priceData.Ticker))
+
+		s.logger.WithFields(log.Fields{
+			"ticker":    priceData.Ticker,
+			"provider":  priceData.ProviderName,
+			"price":     price.String(),
+			"min_price": bounds.min,
+			"max_price": bounds.max,
+		}).Warningln("price is out of the configured bounds, dropping it")
+
+		return false
+	}
+
+	return true
+}
+
 func (s *oracleSvc) panicRecover(err *error) {
 	if r := recover(); r != nil {
 		*err = errors.Errorf("%v", r)
@@ -482,5 +1289,20 @@ func (s *oracleSvc) panicRecover(err *error) {
 }
 
 func (s *oracleSvc) Close() {
-	// graceful shutdown if needed
+	if s.storkFetcher != nil {
+		s.storkFetcher.Close()
+	}
+
+	if s.auditLog != nil {
+		if err := s.auditLog.Close(); err != nil {
+			s.logger.WithError(err).Warningln("failed to close audit log")
+		}
+	}
+
+	// streaming feeds own their connections
+	for _, pricePuller := range s.pricePullers {
+		if closer, ok := pricePuller.(interface{ Close() }); ok {
+			closer.Close()
+		}
+	}
 }
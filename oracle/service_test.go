@@ -0,0 +1,307 @@
+package oracle
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	oracletypes "github.com/InjectiveLabs/sdk-go/chain/oracle/types"
+	chainclient "github.com/InjectiveLabs/sdk-go/client/chain"
+	cosmtypes "github.com/cosmos/cosmos-sdk/types"
+	"github.com/shopspring/decimal"
+	"google.golang.org/grpc"
+)
+
+func TestNewServiceWithoutFeeds(t *testing.T) {
+	if _, err := NewService(context.Background(), nil, nil, nil, map[string]*FeedConfig{}, nil, ServiceConfig{}); err != nil {
+		t.Fatalf("expected the service to start idle without feeds, got error: %v", err)
+	}
+
+	if _, err := NewService(context.Background(), nil, nil, nil, map[string]*FeedConfig{}, nil, ServiceConfig{StrictFeeds: true}); err == nil {
+		t.Errorf("expected error without feeds in strict mode")
+	}
+}
+
+func TestServiceMultipleOracleTypes(t *testing.T) {
+	svc, err := NewService(context.Background(), nil, nil, nil, map[string]*FeedConfig{
+		"inj.toml": {
+			ProviderName:      "test",
+			Ticker:            "INJ/USDT",
+			OracleTypes:       []string{"Provider", "PriceFeed"},
+			ObservationSource: `price [type=memo value="25.5"]`,
+		},
+	}, nil, ServiceConfig{})
+	if err != nil {
+		t.Fatalf("failed to init service: %v", err)
+	}
+
+	oracleSvc := svc.(*oracleSvc)
+	if oracleType := oracleSvc.pricePullers["INJ/USDT"].OracleType(); oracleType != oracletypes.OracleType_Provider {
+		t.Fatalf("expected the puller to pull for the first oracle type, got %s", oracleType)
+	}
+
+	prices := oracleSvc.withExtraOracleTypes(&PriceData{
+		Ticker:     "INJ/USDT",
+		Price:      decimal.RequireFromString("25.5"),
+		OracleType: oracletypes.OracleType_Provider,
+	})
+	if len(prices) != 2 {
+		t.Fatalf("expected price data for 2 oracle types, got %d", len(prices))
+	}
+
+	if prices[0].OracleType != oracletypes.OracleType_Provider || prices[1].OracleType != oracletypes.OracleType_PriceFeed {
+		t.Errorf("unexpected oracle types: %s, %s", prices[0].OracleType, prices[1].OracleType)
+	} else if !prices[1].Price.Equal(prices[0].Price) {
+		t.Errorf("expected the same price for all oracle types, got %s", prices[1].Price)
+	}
+
+	for name, cfg := range map[string]*FeedConfig{
+		"both oracleType and oracleTypes": {ProviderName: "test", OracleType: "PriceFeed", OracleTypes: []string{"Provider"}},
+		"stork oracle type":               {ProviderName: "test", OracleTypes: []string{"PriceFeed", "Stork"}},
+		"stork provider":                  {ProviderName: "stork", OracleTypes: []string{"PriceFeed"}},
+		"duplicate oracle type":           {ProviderName: "test", OracleTypes: []string{"PriceFeed", "PriceFeed"}},
+		"unknown oracle type":             {ProviderName: "test", OracleTypes: []string{"PriceFeed", "Chainlink2"}},
+	} {
+		if _, err := cfg.oracleTypes(); err == nil {
+			t.Errorf("expected error for %s", name)
+		}
+	}
+}
+
+func TestPullPriceSetsPulledAt(t *testing.T) {
+	upstreamAt := time.Now().Add(-time.Hour).Truncate(time.Second)
+	feedCfg := &FeedConfig{
+		ProviderName:      "test",
+		Ticker:            "INJ/USDT",
+		ObservationSource: fmt.Sprintf(`result [type=merge left=<{"price": "25.5", "timestamp": %d}> right=<{}>]`, upstreamAt.Unix()),
+	}
+
+	svc, err := NewService(context.Background(), nil, nil, nil, map[string]*FeedConfig{"inj.toml": feedCfg}, nil, ServiceConfig{})
+	if err != nil {
+		t.Fatalf("failed to init service: %v", err)
+	}
+
+	oracleSvc := svc.(*oracleSvc)
+	pullStart := time.Now()
+	prices, err := oracleSvc.pullPriceWithRetries(oracleSvc.pricePullers["INJ/USDT"], oracleSvc.retryPolicyOf("INJ/USDT"), oracleSvc.logger)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if len(prices) != 1 {
+		t.Fatalf("expected a single price, got %v", prices)
+	}
+
+	// the upstream timestamp is kept, while the pull time is measured by the oracle
+	if !prices[0].Timestamp.Equal(upstreamAt) {
+		t.Errorf("expected upstream timestamp %s, got %s", upstreamAt, prices[0].Timestamp)
+	}
+
+	if prices[0].PulledAt.Before(pullStart) || prices[0].PulledAt.After(time.Now()) {
+		t.Errorf("expected pulled at within the pull, got %s", prices[0].PulledAt)
+	}
+}
+
+func TestPullPriceWithRetries(t *testing.T) {
+	var requests atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) <= 2 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+
+		_, _ = w.Write([]byte(`{"price": "25.5"}`))
+	}))
+	defer srv.Close()
+
+	retries := 2
+	feedCfg := &FeedConfig{
+		ProviderName:      "test",
+		Ticker:            "INJ/USDT",
+		ObservationSource: fmt.Sprintf(`ticker [type=http method=GET url="%s"]; parse [type=jsonparse path="price"]; ticker -> parse`, srv.URL),
+		Retries:           &retries,
+		RetryBackoff:      "1ms",
+	}
+
+	svc, err := NewService(context.Background(), nil, nil, nil, map[string]*FeedConfig{"inj.toml": feedCfg}, nil, ServiceConfig{})
+	if err != nil {
+		t.Fatalf("failed to init service: %v", err)
+	}
+
+	oracleSvc := svc.(*oracleSvc)
+	policy := oracleSvc.retryPolicyOf("INJ/USDT")
+	if policy.retries != 2 || policy.backoff != time.Millisecond {
+		t.Fatalf("unexpected retry policy: %+v", policy)
+	}
+
+	prices, err := oracleSvc.pullPriceWithRetries(oracleSvc.pricePullers["INJ/USDT"], policy, oracleSvc.logger)
+	if err != nil {
+		t.Fatalf("expected the price after retries, got error: %v", err)
+	} else if len(prices) != 1 || !prices[0].Price.Equal(decimal.RequireFromString("25.5")) {
+		t.Errorf("expected a single price 25.5, got %v", prices)
+	}
+
+	requests.Store(0)
+	policy.retries = 1
+	if _, err := oracleSvc.pullPriceWithRetries(oracleSvc.pricePullers["INJ/USDT"], policy, oracleSvc.logger); err == nil {
+		t.Errorf("expected error once retries are exhausted")
+	}
+
+	if defaultPolicy := oracleSvc.retryPolicyOf("BTC/USDT"); defaultPolicy != defaultRetryPolicy {
+		t.Errorf("expected the default retry policy, got %+v", defaultPolicy)
+	}
+
+	for name, cfg := range map[string]*FeedConfig{
+		"negative retries":     {Retries: func() *int { v := -1; return &v }()},
+		"malformed backoff":    {RetryBackoff: "soon"},
+		"non-positive backoff": {RetryBackoff: "0s"},
+	} {
+		if _, err := cfg.retryPolicy(); err == nil {
+			t.Errorf("expected error for %s", name)
+		}
+	}
+}
+
+func TestScaledPriceFeedPrice(t *testing.T) {
+	scale := 6
+	svc, err := NewService(context.Background(), nil, nil, nil, map[string]*FeedConfig{
+		"btc.toml": {
+			ProviderName:      "test",
+			Ticker:            "BTC/USDT",
+			PriceScale:        &scale,
+			ObservationSource: `price [type=memo value="64000.5"]`,
+		},
+	}, nil, ServiceConfig{})
+	if err != nil {
+		t.Fatalf("failed to init service: %v", err)
+	}
+
+	oracleSvc := svc.(*oracleSvc)
+	for ticker, expected := range map[Ticker]string{
+		"BTC/USDT": "64000500000",
+		"ETH/USDT": "64000.5",
+	} {
+		price := oracleSvc.scaledPriceFeedPrice(&PriceData{
+			Ticker: ticker,
+			Price:  decimal.RequireFromString("64000.5"),
+		})
+		if !price.Equal(decimal.RequireFromString(expected)) {
+			t.Errorf("expected %s price %s, got %s", ticker, expected, price)
+		}
+	}
+
+	for _, scale := range []int{-19, 19} {
+		if _, err := (&FeedConfig{PriceScale: &scale}).priceScale(); err == nil {
+			t.Errorf("expected error for price scale %d", scale)
+		}
+	}
+}
+
+func TestInitialPullDelay(t *testing.T) {
+	for i := 0; i < 100; i++ {
+		if delay := initialPullDelay(5*time.Second, 10*time.Second, time.Minute, 0); delay < 5*time.Second || delay >= 15*time.Second {
+			t.Fatalf("expected delay within the window after the start delay, got %s", delay)
+		}
+
+		// spread of the window is capped at the pull interval
+		if delay := initialPullDelay(5*time.Second, time.Minute, time.Second, 0); delay > 6*time.Second {
+			t.Fatalf("expected delay capped at the interval after the start delay, got %s", delay)
+		}
+
+		// falls back to the startup jitter of the interval without a window
+		if delay := initialPullDelay(5*time.Second, 0, 10*time.Second, 0.5); delay < 5*time.Second || delay > 10*time.Second {
+			t.Fatalf("expected delay within the startup jitter after the start delay, got %s", delay)
+		}
+	}
+
+	if delay := initialPullDelay(5*time.Second, 0, time.Minute, 0); delay != 5*time.Second {
+		t.Errorf("expected the start delay without a window or jitter, got %s", delay)
+	}
+}
+
+type stubChainClient struct {
+	chainclient.ChainClient
+	from cosmtypes.AccAddress
+}
+
+func (c *stubChainClient) FromAddress() cosmtypes.AccAddress { return c.from }
+
+func (c *stubChainClient) QueryClient() *grpc.ClientConn { return nil }
+
+type stubOracleQueryClient struct {
+	oracletypes.QueryClient
+	priceStates []*oracletypes.PriceFeedState
+}
+
+func (c *stubOracleQueryClient) PriceFeedPriceStates(context.Context, *oracletypes.QueryPriceFeedPriceStatesRequest, ...grpc.CallOption) (*oracletypes.QueryPriceFeedPriceStatesResponse, error) {
+	return &oracletypes.QueryPriceFeedPriceStatesResponse{PriceStates: c.priceStates}, nil
+}
+
+func TestGetEnabledFeeds(t *testing.T) {
+	sender := cosmtypes.AccAddress("sender______________")
+	feedConfigs := map[string]*FeedConfig{
+		"inj.toml": {ProviderName: "test", Ticker: "INJ/USDT", ObservationSource: `price [type=memo value="25.5"]`},
+		"btc.toml": {ProviderName: "test", Ticker: "BTC/USDT", ObservationSource: `price [type=memo value="64000"]`},
+	}
+
+	svc, err := NewService(context.Background(), nil, nil, nil, feedConfigs, nil, ServiceConfig{})
+	if err != nil {
+		t.Fatalf("failed to init service: %v", err)
+	}
+
+	if feeds := svc.(*oracleSvc).getEnabledFeeds(); feeds != nil {
+		t.Errorf("expected no enabled feeds without query clients, got %v", feeds)
+	}
+
+	queryClient := &stubOracleQueryClient{
+		priceStates: []*oracletypes.PriceFeedState{
+			{Base: "INJ", Quote: "USDT", Relayers: []string{sender.String()}},
+			{Base: "BTC", Quote: "USDT", Relayers: []string{"inj1other"}},
+			{Base: "ETH", Quote: "USDT", Relayers: []string{sender.String()}},
+		},
+	}
+
+	svc, err = NewService(context.Background(), &stubChainClient{from: sender}, nil, queryClient, feedConfigs, nil, ServiceConfig{})
+	if err != nil {
+		t.Fatalf("failed to init service: %v", err)
+	}
+
+	feeds := svc.(*oracleSvc).getEnabledFeeds()
+	if _, ok := feeds["INJ/USDT"]; !ok || len(feeds) != 1 {
+		t.Errorf("expected only the authorized and configured INJ/USDT feed, got %v", feeds)
+	}
+}
+
+func TestNewServiceStrictFeedsUnauthorized(t *testing.T) {
+	sender := cosmtypes.AccAddress("sender______________")
+	feedConfigs := map[string]*FeedConfig{
+		"inj.toml": {ProviderName: "test", Ticker: "INJ/USDT", OracleType: "PriceFeed", ObservationSource: `price [type=memo value="25.5"]`},
+		"btc.toml": {ProviderName: "test", Ticker: "BTC/USDT", OracleType: "PriceFeed", ObservationSource: `price [type=memo value="64000"]`},
+	}
+
+	newService := func(strict bool, relayers ...string) error {
+		queryClient := &stubOracleQueryClient{
+			priceStates: []*oracletypes.PriceFeedState{
+				{Base: "INJ", Quote: "USDT", Relayers: relayers},
+				{Base: "BTC", Quote: "USDT", Relayers: []string{"inj1other"}},
+			},
+		}
+
+		_, err := NewService(context.Background(), &stubChainClient{from: sender}, nil, queryClient, feedConfigs, nil, ServiceConfig{StrictFeeds: strict})
+		return err
+	}
+
+	if err := newService(true, "inj1other"); err == nil {
+		t.Error("expected error in strict mode when the relayer is authorized in none of the loaded feeds")
+	}
+
+	if err := newService(false, "inj1other"); err != nil {
+		t.Errorf("expected only a warning without strict mode, got error: %v", err)
+	}
+
+	if err := newService(true, sender.String()); err != nil {
+		t.Errorf("expected no error in strict mode when the relayer is authorized in some of the loaded feeds, got: %v", err)
+	}
+}
@@ -0,0 +1,129 @@
+package oracle
+
+import (
+	"context"
+	"time"
+
+	log "github.com/InjectiveLabs/suplog"
+	cosmtypes "github.com/cosmos/cosmos-sdk/types"
+	"github.com/pkg/errors"
+)
+
+// SimulationResult is the outcome of a simulated price relay.
+type SimulationResult struct {
+	// Prices are the prices that made it into the composed messages, keyed by ticker
+	Prices map[string]*PriceData
+
+	// PullErrors are the errors of feeds that failed to pull, keyed by ticker
+	PullErrors map[string]error
+
+	Msgs    []cosmtypes.Msg
+	GasUsed uint64
+}
+
+// Simulate pulls price of every feed once, prepares and composes messages exactly as the main loop does
+// and runs a chain simulation of them, without broadcasting. Feeds that don't have a price yet
+// (e.g. Stork feeds waiting for a websocket update) are pulled again until ctx is done.
+func (s *oracleSvc) Simulate(ctx context.Context) (*SimulationResult, error) {
+	result := &SimulationResult{
+		Prices:     make(map[string]*PriceData),
+		PullErrors: make(map[string]error),
+	}
+
+	pending := make(map[string]PricePuller, len(s.pricePullers))
+	for ticker, pricePuller := range s.pricePullers {
+		pending[ticker] = pricePuller
+	}
+
+	// prices are never broadcast, so Stork asset pairs are handed back to their feeds
+	var pulled, priceBatch []*PriceData
+	defer func() { s.releaseAssetPairs(pulled) }()
+
+	t := time.NewTicker(500 * time.Millisecond)
+	defer t.Stop()
+
+	for len(pending) > 0 {
+		for ticker, pricePuller := range pending {
+			prices, err := s.pullPrices(ctx, pricePuller)
+			if err != nil {
+				result.PullErrors[ticker] = err
+				delete(pending, ticker)
+				continue
+			} else if len(prices) == 0 {
+				continue
+			}
+
+			delete(pending, ticker)
+			pulled = append(pulled, prices...)
+
+			// feeds with many pipeline outputs yield prices of many tickers
+			for _, priceData := range prices {
+				priceTicker := string(priceData.Ticker)
+
+				if !s.matchesReferencePrice(ctx, priceData) {
+					result.PullErrors[priceTicker] = errors.Errorf("price is not submitted: %s", skipReasonReferenceDeviation)
+					continue
+				}
+
+				prepared, err := s.prepareSimulatedPrices(priceData)
+				if err != nil {
+					result.PullErrors[priceTicker] = err
+					continue
+				}
+
+				result.Prices[priceTicker] = prepared[0]
+				priceBatch = append(priceBatch, prepared...)
+			}
+		}
+
+		if len(pending) == 0 {
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+			for ticker := range pending {
+				result.PullErrors[ticker] = errors.Wrap(ctx.Err(), "no price received")
+			}
+			pending = nil
+		case <-t.C:
+		}
+	}
+
+	result.Msgs = s.composeMsgs(priceBatch)
+	if len(result.Msgs) == 0 {
+		return result, errors.New("pulled prices composed no messages")
+	}
+
+	s.logger.WithFields(log.Fields{
+		"batch_size": len(priceBatch),
+		"msgs":       len(result.Msgs),
+	}).Infoln("simulating composed messages")
+
+	simRes, err := s.cosmosClient.SimulateMsg(s.cosmosClient.ClientContext(), result.Msgs...)
+	if err != nil {
+		return result, errors.Wrap(err, "failed to simulate messages")
+	}
+
+	if simRes.GasInfo != nil {
+		result.GasUsed = simRes.GasInfo.GasUsed
+	}
+
+	return result, nil
+}
+
+// prepareSimulatedPrices prepares the price and its copies for the extra oracle types of the feed as the main
+// loop does, returning an error with the reason if any of them would be skipped.
+func (s *oracleSvc) prepareSimulatedPrices(priceData *PriceData) ([]*PriceData, error) {
+	var prepared []*PriceData
+	for _, extraData := range s.withExtraOracleTypes(priceData) {
+		extraData, skipReason := s.preparePrice(extraData)
+		if len(skipReason) > 0 {
+			return nil, errors.Errorf("price is not submitted: %s", skipReason)
+		}
+
+		prepared = append(prepared, extraData)
+	}
+
+	return prepared, nil
+}
@@ -0,0 +1,74 @@
+package oracle
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	oracletypes "github.com/InjectiveLabs/sdk-go/chain/oracle/types"
+	chainclient "github.com/InjectiveLabs/sdk-go/client/chain"
+	"github.com/cosmos/cosmos-sdk/client"
+	cosmtypes "github.com/cosmos/cosmos-sdk/types"
+	txtypes "github.com/cosmos/cosmos-sdk/types/tx"
+)
+
+type simulateStubClient struct {
+	stubChainClient
+	msgs []cosmtypes.Msg
+}
+
+func (c *simulateStubClient) ClientContext() client.Context { return client.Context{} }
+
+func (c *simulateStubClient) SimulateMsg(_ client.Context, msgs ...cosmtypes.Msg) (*txtypes.SimulateResponse, error) {
+	c.msgs = msgs
+	return &txtypes.SimulateResponse{GasInfo: &cosmtypes.GasInfo{GasUsed: 100000}}, nil
+}
+
+var _ chainclient.ChainClient = (*simulateStubClient)(nil)
+
+func TestSimulatePreparesPrices(t *testing.T) {
+	client := &simulateStubClient{stubChainClient: stubChainClient{from: cosmtypes.AccAddress("sender______________")}}
+
+	svc, err := NewService(context.Background(), client, nil, nil, map[string]*FeedConfig{
+		"inj.toml": {
+			ProviderName:      "test",
+			Ticker:            "INJ/USDT",
+			OracleType:        "PriceFeed",
+			ObservationSource: `a [type=memo value="1"]; b [type=divide divisor=3 precision=24]; a -> b`,
+		},
+		"atom.toml": {
+			ProviderName:      "test",
+			Ticker:            "ATOM/USDT",
+			OracleType:        "PriceFeed",
+			ObservationSource: `a [type=memo value="8.5"]; b [type=multiply times=1]; a -> b`,
+			MinSubmitInterval: "1h",
+		},
+	}, nil, ServiceConfig{})
+	if err != nil {
+		t.Fatalf("failed to init service: %v", err)
+	}
+
+	oracleSvc := svc.(*oracleSvc)
+	oracleSvc.lastSubmitted["ATOM/USDT"] = SubmittedPrice{Timestamp: time.Now()}
+
+	ctx, cancelFn := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancelFn()
+
+	result, err := oracleSvc.Simulate(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// prices of more than 18 decimals are rounded to the on-chain decimals before they're composed
+	if priceData, ok := result.Prices["INJ/USDT"]; !ok || priceData.Price.String() != "0.333333333333333333" {
+		t.Errorf("expected INJ/USDT price rounded to 18 decimals, got %v", priceData)
+	} else if msg, ok := client.msgs[0].(*oracletypes.MsgRelayPriceFeedPrice); !ok || msg.Price[0].String() != "0.333333333333333333" {
+		t.Errorf("expected the rounded price composed, got %v", client.msgs[0])
+	}
+
+	if _, ok := result.PullErrors["ATOM/USDT"]; !ok {
+		t.Error("expected ATOM/USDT skipped as submitted too recently")
+	} else if len(client.msgs) != 1 {
+		t.Errorf("expected only the INJ/USDT price composed, got %d messages", len(client.msgs))
+	}
+}
@@ -0,0 +1,97 @@
+package oracle
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/shopspring/decimal"
+)
+
+// SubmittedPrice is the last price of a ticker included on chain.
+type SubmittedPrice struct {
+	Price     decimal.Decimal `json:"price"`
+	Timestamp time.Time       `json:"timestamp"`
+}
+
+// LoadSubmittedPrices reads the last submitted prices, keyed by ticker, from the state file.
+// A missing state file is not an error, since it's created on the first submission.
+func LoadSubmittedPrices(path string) (map[string]SubmittedPrice, error) {
+	prices := make(map[string]SubmittedPrice)
+
+	body, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return prices, nil
+	} else if err != nil {
+		return nil, errors.Wrap(err, "failed to read state file")
+	}
+
+	if err := json.Unmarshal(body, &prices); err != nil {
+		return nil, errors.Wrapf(err, "failed to unmarshal state file %s", path)
+	}
+
+	return prices, nil
+}
+
+// saveSubmittedPrices writes the last submitted prices to the state file, replacing it atomically,
+// so a crash mid-write doesn't leave a corrupted state behind.
+func saveSubmittedPrices(path string, prices map[string]SubmittedPrice) error {
+	body, err := json.MarshalIndent(prices, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal submitted prices")
+	}
+
+	tmpFile, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return errors.Wrap(err, "failed to create temp state file")
+	}
+	defer os.Remove(tmpFile.Name())
+
+	if _, err := tmpFile.Write(body); err != nil {
+		tmpFile.Close()
+		return errors.Wrap(err, "failed to write temp state file")
+	}
+
+	if err := tmpFile.Close(); err != nil {
+		return errors.Wrap(err, "failed to close temp state file")
+	}
+
+	if err := os.Rename(tmpFile.Name(), path); err != nil {
+		return errors.Wrap(err, "failed to replace state file")
+	}
+
+	return nil
+}
+
+// recordSubmittedPrices remembers prices of a batch included on chain and persists them,
+// if the state file is configured.
+func (s *oracleSvc) recordSubmittedPrices(priceBatch []*PriceData, submittedAt time.Time) {
+	s.submittedMu.Lock()
+	defer s.submittedMu.Unlock()
+
+	for _, priceData := range priceBatch {
+		s.lastSubmitted[string(priceData.Ticker)] = SubmittedPrice{
+			Price:     priceData.Price,
+			Timestamp: submittedAt,
+		}
+	}
+
+	if len(s.stateFile) == 0 {
+		return
+	}
+
+	if err := saveSubmittedPrices(s.stateFile, s.lastSubmitted); err != nil {
+		s.logger.WithError(err).Warningln("failed to persist submitted prices")
+	}
+}
+
+// lastSubmittedPrice returns the last price of the ticker included on chain, if any.
+func (s *oracleSvc) lastSubmittedPrice(ticker string) (SubmittedPrice, bool) {
+	s.submittedMu.RLock()
+	defer s.submittedMu.RUnlock()
+
+	price, ok := s.lastSubmitted[ticker]
+	return price, ok
+}
@@ -0,0 +1,72 @@
+package oracle
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	log "github.com/InjectiveLabs/suplog"
+	"github.com/shopspring/decimal"
+)
+
+func TestSubmittedPricesState(t *testing.T) {
+	stateFile := filepath.Join(t.TempDir(), "state.json")
+
+	prices, err := LoadSubmittedPrices(stateFile)
+	if err != nil || len(prices) != 0 {
+		t.Fatalf("expected empty state of missing file, got %v, %v", prices, err)
+	}
+
+	svc := &oracleSvc{
+		stateFile:     stateFile,
+		lastSubmitted: map[string]SubmittedPrice{},
+		logger:        log.WithField("svc", "oracle"),
+	}
+
+	submittedAt := time.Unix(1704067200, 0)
+	svc.recordSubmittedPrices([]*PriceData{{
+		Ticker: "INJ/USDT",
+		Price:  decimal.RequireFromString("25.5"),
+	}}, submittedAt)
+
+	prices, err = LoadSubmittedPrices(stateFile)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	last, ok := prices["INJ/USDT"]
+	if !ok || !last.Price.Equal(decimal.RequireFromString("25.5")) || !last.Timestamp.Equal(submittedAt) {
+		t.Errorf("unexpected persisted price: %+v", prices)
+	}
+}
+
+func TestSubmitDue(t *testing.T) {
+	svc := &oracleSvc{
+		lastSubmitted: map[string]SubmittedPrice{},
+		minSubmitIntervals: map[string]time.Duration{
+			"INJ/USDT": 5 * time.Minute,
+		},
+		logger: log.WithField("svc", "oracle"),
+	}
+
+	injPrice := &PriceData{Ticker: "INJ/USDT", Price: decimal.RequireFromString("25.5")}
+	btcPrice := &PriceData{Ticker: "BTC/USDT", Price: decimal.RequireFromString("64000")}
+
+	if !svc.submitDue(injPrice) {
+		t.Errorf("expected a never submitted feed to be due")
+	}
+
+	svc.recordSubmittedPrices([]*PriceData{injPrice, btcPrice}, time.Now().Add(-time.Minute))
+
+	if svc.submitDue(injPrice) {
+		t.Errorf("expected the feed not to be due within its min submit interval")
+	} else if !svc.submitDue(btcPrice) {
+		t.Errorf("expected a feed without min submit interval to be due")
+	}
+
+	svc.recordSubmittedPrices([]*PriceData{injPrice}, time.Now().Add(-6*time.Minute))
+
+	if !svc.submitDue(injPrice) {
+		t.Errorf("expected the feed to be due once its min submit interval elapsed")
+	}
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"net/http"
+	"sort"
 	"strings"
 	"sync"
 	"time"
@@ -23,11 +25,15 @@ const (
 	MaxStorkTimestampIntervalNano             = 500_000_000 // 5000ms
 )
 
-var ErrInvalidMessage = errors.New("received invalid message")
+var (
+	ErrInvalidMessage = errors.New("received invalid message")
+	ErrFetcherClosed  = errors.New("fetcher is closed")
+)
 
 type StorkFetcher interface {
 	Start(ctx context.Context, conn *websocket.Conn) error
 	AssetPair(ticker string) *oracletypes.AssetPair
+	Close()
 }
 
 type messageType string
@@ -40,13 +46,45 @@ type StorkConfig struct {
 	WebsocketUrl    string
 	WebsocketHeader string
 	Message         string
+
+	// WebsocketExtraHeader is sent along with the Basic auth of WebsocketHeader when connecting,
+	// e.g. for API key authentication.
+	WebsocketExtraHeader http.Header
+
+	// TickerMessages overrides the Message template for particular tickers. Tickers sharing
+	// the same template are subscribed with a single message.
+	TickerMessages map[string]string
+
+	// ReadTimeout is the maximum time to wait for the next message or pong,
+	// before the connection is considered dead. Zero disables the deadline.
+	ReadTimeout time.Duration
+
+	// Compression negotiates permessage-deflate with the websocket server.
+	Compression bool
+
+	// DialTimeout bounds every connection attempt, including the handshake.
+	// Zero means pipeline.DefaultWebSocketDialTimeout.
+	DialTimeout time.Duration
+
+	// ReadLimit is the maximum size of a message in bytes, the connection is re-established when exceeded.
+	// Zero means pipeline.DefaultWebSocketReadLimit.
+	ReadLimit int64
+
+	// MaxInvalidMessages is the number of consecutive invalid messages tolerated before the connection
+	// is torn down and re-established. Zero tears it down on the first invalid message.
+	MaxInvalidMessages int
 }
 
 type storkFetcher struct {
 	conn        *websocket.Conn
 	latestPairs map[string]*oracletypes.AssetPair
+	lastUpdates map[string]time.Time
 	tickers     []string
 	message     string
+	messages    map[string]string
+	readTimeout time.Duration
+	maxInvalid  int
+	closed      bool
 	mu          sync.RWMutex
 
 	logger  log.Logger
@@ -54,11 +92,15 @@ type storkFetcher struct {
 }
 
 // NewStorkFetcher returns a new StorkFetcher instance.
-func NewStorkFetcher(storkMessage string, storkTickers []string) *storkFetcher {
+func NewStorkFetcher(cfg *StorkConfig, storkTickers []string) *storkFetcher {
 	feed := &storkFetcher{
-		message:     storkMessage,
+		message:     cfg.Message,
+		messages:    cfg.TickerMessages,
+		readTimeout: cfg.ReadTimeout,
+		maxInvalid:  cfg.MaxInvalidMessages,
 		tickers:     storkTickers,
 		latestPairs: make(map[string]*oracletypes.AssetPair),
+		lastUpdates: make(map[string]time.Time),
 		logger: log.WithFields(log.Fields{
 			"svc":      "oracle",
 			"dynamic":  true,
@@ -73,44 +115,169 @@ func NewStorkFetcher(storkMessage string, storkTickers []string) *storkFetcher {
 	return feed
 }
 
+// tickerTags returns fresh tags of the ticker, since Tags.With adds to the fetcher tags in place
+// and the fetcher is shared by all Stork feeds.
+func (f *storkFetcher) tickerTags(ticker string) metrics.Tags {
+	return metrics.Tags{
+		"provider": "storkFetcher",
+		"ticker":   ticker,
+	}
+}
+
 func (f *storkFetcher) AssetPair(ticker string) *oracletypes.AssetPair {
 	f.mu.RLock()
 	defer f.mu.RUnlock()
 
+	// report how fresh the cached pair is, so quiet assets are visible even if the connection is up
+	if lastUpdate, ok := f.lastUpdates[ticker]; ok {
+		metrics.CustomReport(func(s metrics.Statter, tagSpec []string) {
+			s.Gauge("feed_provider.stork.last_update_age", time.Since(lastUpdate).Seconds(), tagSpec, 1)
+		}, f.tickerTags(ticker))
+	}
+
 	return f.latestPairs[ticker]
 }
 
-func (f *storkFetcher) Start(_ context.Context, conn *websocket.Conn) error {
+func (f *storkFetcher) Start(_ context.Context, conn *websocket.Conn) (err error) {
+	f.mu.Lock()
+	if f.closed {
+		f.mu.Unlock()
+		conn.Close()
+		return ErrFetcherClosed
+	}
 	f.conn = conn
+	f.mu.Unlock()
 
 	defer f.reset()
 
-	err := f.subscribe()
+	// Close interrupts a running fetcher by closing its connection, which is reported as closed
+	// rather than as a read failure, so the fetcher is not reconnected
+	defer func() {
+		if err != nil && f.isClosed() {
+			err = ErrFetcherClosed
+		}
+	}()
+
+	err = f.subscribe()
 	if err != nil {
 		return err
 	}
 
+	if f.readTimeout > 0 {
+		stopPingFn := f.startPinging()
+		defer stopPingFn()
+	}
+
 	return f.startReadingMessages()
 }
 
-// subscribe sends the initial subscription message to the WebSocket server.
+// startPinging keeps sending pings to the server, so an idle but healthy connection
+// keeps extending its read deadline with pongs. Returns a func that stops pinging.
+func (f *storkFetcher) startPinging() (stopFn func()) {
+	f.conn.SetPongHandler(func(string) error {
+		return f.conn.SetReadDeadline(time.Now().Add(f.readTimeout))
+	})
+
+	done := make(chan struct{})
+	go func() {
+		t := time.NewTicker(f.readTimeout / 2)
+		defer t.Stop()
+
+		for {
+			select {
+			case <-done:
+				return
+			case <-t.C:
+				err := f.conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(f.readTimeout/2))
+				if err != nil {
+					f.logger.Debugln("error writing ping message:", err)
+				}
+			}
+		}
+	}()
+
+	return func() {
+		close(done)
+	}
+}
+
+// subscribe sends the initial subscription messages to the WebSocket server,
+// one per distinct message template of the tickers.
 func (f *storkFetcher) subscribe() error {
 	if len(f.tickers) == 0 {
 		f.logger.Errorf("no tickers to subscribe to")
 		return errors.New("no tickers to subscribe to")
 	}
 
-	f.logger.Debugln("subscribing to tickers:", f.tickers)
-	f.logger.Debugln(fmt.Sprintf(f.message, strings.Join(f.tickers, "\",\"")))
-	err := f.conn.WriteMessage(websocket.TextMessage, []byte(fmt.Sprintf(f.message, strings.Join(f.tickers, "\",\""))))
-	if err != nil {
-		f.logger.Warningln("error writing subscription message:", err)
-		return err
+	for _, group := range f.subscriptionGroups() {
+		msg := fmt.Sprintf(group.message, strings.Join(group.tickers, "\",\""))
+
+		f.logger.Debugln("subscribing to tickers:", group.tickers)
+		f.logger.Debugln(msg)
+		err := f.conn.WriteMessage(websocket.TextMessage, []byte(msg))
+		if err != nil {
+			f.logger.Warningln("error writing subscription message:", err)
+			return err
+		}
 	}
 
 	return nil
 }
 
+type subscriptionGroup struct {
+	message string
+	tickers []string
+}
+
+// subscriptionGroups groups the tickers by their subscribe message template, tickers without
+// an override use the default one. Groups are sorted by template, to subscribe in a stable order.
+func (f *storkFetcher) subscriptionGroups() []subscriptionGroup {
+	tickersByMessage := make(map[string][]string)
+	for _, ticker := range f.tickers {
+		message := f.message
+		if override, ok := f.messages[ticker]; ok && len(override) > 0 {
+			message = override
+		}
+
+		tickersByMessage[message] = append(tickersByMessage[message], ticker)
+	}
+
+	groups := make([]subscriptionGroup, 0, len(tickersByMessage))
+	for message, tickers := range tickersByMessage {
+		groups = append(groups, subscriptionGroup{
+			message: message,
+			tickers: tickers,
+		})
+	}
+
+	sort.Slice(groups, func(i, j int) bool {
+		return groups[i].message < groups[j].message
+	})
+
+	return groups
+}
+
+// Close closes the current websocket connection, which stops reading messages, clears the update times
+// and prevents the fetcher from being started again.
+func (f *storkFetcher) Close() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.closed = true
+	if f.conn != nil {
+		f.conn.Close()
+	}
+	f.latestPairs = make(map[string]*oracletypes.AssetPair)
+	f.lastUpdates = make(map[string]time.Time)
+}
+
+func (f *storkFetcher) isClosed() bool {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	return f.closed
+}
+
 func (f *storkFetcher) reset() {
 	f.mu.Lock()
 	defer f.mu.Unlock()
@@ -120,10 +287,19 @@ func (f *storkFetcher) reset() {
 }
 
 func (f *storkFetcher) startReadingMessages() error {
+	// consecutive invalid messages, a single bad frame doesn't tear down the connection of all Stork feeds
+	var invalidMessages int
+
 	for {
 		var err error
 		var messageRead []byte
 		now := time.Now()
+		if f.readTimeout > 0 {
+			if err = f.conn.SetReadDeadline(now.Add(f.readTimeout)); err != nil {
+				return err
+			}
+		}
+
 		// Read message from the WebSocket
 		_, messageRead, err = f.conn.ReadMessage()
 		if err != nil {
@@ -147,12 +323,24 @@ func (f *storkFetcher) startReadingMessages() error {
 
 		switch msgResp.Type {
 		case messageTypeInvalid.String():
-			// Report the invalid message and return
 			metrics.ReportFuncError(f.svcTags)
-			return ErrInvalidMessage
+			metrics.CustomReport(func(s metrics.Statter, tagSpec []string) {
+				s.Count("feed_provider.stork.invalid_message.size", 1, tagSpec, 1)
+			}, f.svcTags)
+
+			invalidMessages++
+			if invalidMessages > f.maxInvalid {
+				f.logger.WithField("trace_id", msgResp.TraceID).Warningf("received %d invalid messages in a row, reconnecting: %s", invalidMessages, string(msgResp.Data))
+				return ErrInvalidMessage
+			}
+
+			f.logger.WithField("trace_id", msgResp.TraceID).Warningln("received invalid message, ignoring it:", string(msgResp.Data))
 		case messageTypeSubscribe.String():
+			invalidMessages = 0
 			f.logger.Infof("subscribed to tickers: %s", strings.Join(f.tickers, ","))
 		case messageTypeOraclePrices.String():
+			invalidMessages = 0
+
 			var data oracleData
 			if err = json.Unmarshal(msgResp.Data, &data); err != nil {
 				f.logger.Warningln("error unmarshalling oracle data:", err)
@@ -170,6 +358,9 @@ func (f *storkFetcher) startReadingMessages() error {
 			for _, assetId := range assetIds {
 				asset := data[assetId]
 				if len(asset.SignedPrices) == 0 {
+					metrics.CustomReport(func(s metrics.Statter, tagSpec []string) {
+						s.Count("feed_provider.stork.asset_error.size", 1, tagSpec, 1)
+					}, f.tickerTags(assetId))
 					log.Warningln("no signed prices found for asset:", assetId)
 					continue
 				}
@@ -181,10 +372,12 @@ func (f *storkFetcher) startReadingMessages() error {
 			}
 
 			// Safely update the latestPairs with a write lock
+			updatedAt := time.Now()
 			f.mu.Lock()
 			for key, value := range newPairs {
 				var v = value
 				f.latestPairs[key] = v
+				f.lastUpdates[key] = updatedAt
 			}
 			f.mu.Unlock()
 
@@ -0,0 +1,105 @@
+package oracle
+
+import (
+	"context"
+	"time"
+
+	log "github.com/InjectiveLabs/suplog"
+	"github.com/pkg/errors"
+	"github.com/shopspring/decimal"
+
+	oracletypes "github.com/InjectiveLabs/sdk-go/chain/oracle/types"
+)
+
+// Submit relays a single price of the ticker once and returns the Tx hash. If price is nil,
+// it's pulled from the feed, waiting until ctx is done for feeds that don't have a price yet
+// (e.g. Stork feeds waiting for a websocket update). Stork prices must be pulled, since they are signed.
+func (s *oracleSvc) Submit(ctx context.Context, ticker string, price *decimal.Decimal) (txHash string, err error) {
+	pricePuller, ok := s.pricePullers[ticker]
+	if !ok {
+		return "", errors.Errorf("no feed config loaded for ticker %s", ticker)
+	}
+
+	var priceData *PriceData
+	if price != nil {
+		if pricePuller.OracleType() == oracletypes.OracleType_Stork {
+			return "", errors.New("price of a Stork feed can't be set manually, it must be pulled")
+		}
+
+		priceData = &PriceData{
+			Ticker:       Ticker(ticker),
+			ProviderName: pricePuller.ProviderName(),
+			Symbol:       pricePuller.Symbol(),
+			Price:        *price,
+			Timestamp:    time.Now(),
+			OracleType:   pricePuller.OracleType(),
+		}
+	} else if priceData, err = s.pullReadyPrice(ctx, pricePuller); err != nil {
+		return "", err
+	}
+
+	if priceData.OracleType == oracletypes.OracleType_Stork {
+		if priceData.AssetPair == nil {
+			return "", errors.New("got nil asset pair for stork oracle")
+		}
+	} else if !priceData.Price.IsPositive() {
+		return "", errors.New("got negative or zero price")
+	}
+
+	if !s.withinPriceBounds(priceData) {
+		return "", errors.New("price is out of the configured bounds")
+	}
+
+	msgs := s.composeMsgs([]*PriceData{priceData})
+	if len(msgs) == 0 {
+		return "", errors.New("price composed no messages")
+	}
+
+	s.logger.WithFields(log.Fields{
+		"ticker":      ticker,
+		"oracle_type": priceData.OracleType.String(),
+		"price":       priceData.Price.String(),
+	}).Infoln("submitting price")
+
+	debugLogMsgs(s.logger, msgs)
+
+	txResp, err := s.cosmosClient.SyncBroadcastMsg(msgs...)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to broadcast Tx")
+	}
+
+	if txResp.TxResponse == nil {
+		return "", errors.New("got empty Tx response")
+	}
+
+	s.auditBroadcast([]*PriceData{priceData}, txResp.TxResponse.TxHash, txResp.TxResponse.Height, txResp.TxResponse.Code, txResp.TxResponse.RawLog)
+
+	if txResp.TxResponse.Code != 0 {
+		return txResp.TxResponse.TxHash, errors.Errorf("set price Tx error: %s", txResp.TxResponse.RawLog)
+	}
+
+	s.recordSubmittedPrices([]*PriceData{priceData}, time.Now())
+	s.reportSubmittedHeight(txResp.TxResponse.Height)
+	return txResp.TxResponse.TxHash, nil
+}
+
+// pullReadyPrice pulls the price once, pulling again until ctx is done if the feed has no price yet.
+func (s *oracleSvc) pullReadyPrice(ctx context.Context, pricePuller PricePuller) (*PriceData, error) {
+	t := time.NewTicker(500 * time.Millisecond)
+	defer t.Stop()
+
+	for {
+		priceData, err := s.pullPrice(ctx, pricePuller)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to pull price")
+		} else if priceData != nil {
+			return priceData, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, errors.Wrap(ctx.Err(), "no price received")
+		case <-t.C:
+		}
+	}
+}
@@ -0,0 +1,112 @@
+package oracle
+
+import (
+	"github.com/InjectiveLabs/metrics"
+	oracletypes "github.com/InjectiveLabs/sdk-go/chain/oracle/types"
+	log "github.com/InjectiveLabs/suplog"
+	"github.com/pkg/errors"
+	"github.com/shopspring/decimal"
+)
+
+// PriceTransform names a transform applied to every pulled price before it's batched, e.g. to apply
+// an operator policy uniformly across all feeds instead of editing the pipeline of every feed.
+type PriceTransform string
+
+const (
+	// PriceTransformIdentity leaves prices as pulled.
+	PriceTransformIdentity PriceTransform = "identity"
+
+	// PriceTransformHaircut lowers prices by a fraction, e.g. 0.001 submits 99.9% of the pulled price.
+	PriceTransformHaircut PriceTransform = "haircut"
+
+	// PriceTransformClampToBand clamps prices to a band of a fraction around the last price of the ticker
+	// included on chain, e.g. 0.05 lets a price move at most 5% per submission. Prices of tickers without
+	// a submission yet are left as pulled.
+	PriceTransformClampToBand PriceTransform = "clamp-to-band"
+)
+
+// PriceTransformConfig selects the transform of pulled prices.
+type PriceTransformConfig struct {
+	// Name of the transform, identity if empty.
+	Name PriceTransform
+
+	// Fraction is the haircut, or the half-width of the clamp band.
+	Fraction float64
+}
+
+type priceTransform struct {
+	name     PriceTransform
+	fraction decimal.Decimal
+}
+
+func newPriceTransform(cfg PriceTransformConfig) (*priceTransform, error) {
+	switch cfg.Name {
+	case "", PriceTransformIdentity:
+		return nil, nil
+	case PriceTransformHaircut, PriceTransformClampToBand:
+		if cfg.Fraction <= 0 || cfg.Fraction >= 1 {
+			return nil, errors.Errorf("%s price transform fraction must be between 0 and 1, got %v", cfg.Name, cfg.Fraction)
+		}
+	default:
+		return nil, errors.Errorf("unsupported price transform: %s (expected %s, %s or %s)", cfg.Name, PriceTransformIdentity, PriceTransformHaircut, PriceTransformClampToBand)
+	}
+
+	return &priceTransform{
+		name:     cfg.Name,
+		fraction: decimal.NewFromFloat(cfg.Fraction),
+	}, nil
+}
+
+// transformPrice applies the configured transform to a pulled price, returning a copy if the price has changed.
+// Stork prices are signed by the publishers, so they are never transformed.
+func (s *oracleSvc) transformPrice(priceData *PriceData) *PriceData {
+	if s.transform == nil || priceData.OracleType == oracletypes.OracleType_Stork {
+		return priceData
+	}
+
+	var price decimal.Decimal
+	switch s.transform.name {
+	case PriceTransformHaircut:
+		price = priceData.Price.Mul(decimal.NewFromInt(1).Sub(s.transform.fraction))
+	case PriceTransformClampToBand:
+		last, ok := s.lastSubmittedPrice(string(priceData.Ticker))
+		if !ok {
+			return priceData
+		}
+
+		band := last.Price.Mul(s.transform.fraction)
+		lower, upper := last.Price.Sub(band), last.Price.Add(band)
+		switch {
+		case priceData.Price.LessThan(lower):
+			price = lower
+		case priceData.Price.GreaterThan(upper):
+			price = upper
+		default:
+			return priceData
+		}
+	default:
+		return priceData
+	}
+
+	if price.Equal(priceData.Price) {
+		return priceData
+	}
+
+	s.logger.WithFields(log.Fields{
+		"ticker":    priceData.Ticker,
+		"transform": s.transform.name,
+		"pulled":    priceData.Price.String(),
+	}).Debugln("price transformed to", price.String())
+
+	metrics.CustomReport(func(s metrics.Statter, tagSpec []string) {
+		s.Count("price_oracle.price_transform.adjusted.size", 1, tagSpec, 1)
+	}, metrics.Tags{
+		"svc":       "price_oracle",
+		"ticker":    string(priceData.Ticker),
+		"transform": string(s.transform.name),
+	})
+
+	transformed := *priceData
+	transformed.Price = price
+	return &transformed
+}
@@ -0,0 +1,70 @@
+package oracle
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	oracletypes "github.com/InjectiveLabs/sdk-go/chain/oracle/types"
+	"github.com/shopspring/decimal"
+)
+
+func TestTransformPrice(t *testing.T) {
+	newSvc := func(cfg PriceTransformConfig) *oracleSvc {
+		svc, err := NewService(context.Background(), nil, nil, nil, map[string]*FeedConfig{}, nil, ServiceConfig{PriceTransform: cfg})
+		if err != nil {
+			t.Fatalf("failed to init service: %v", err)
+		}
+
+		return svc.(*oracleSvc)
+	}
+
+	priceData := func(price string) *PriceData {
+		return &PriceData{
+			Ticker:     "INJ/USDT",
+			Price:      decimal.RequireFromString(price),
+			OracleType: oracletypes.OracleType_PriceFeed,
+		}
+	}
+
+	if transformed := newSvc(PriceTransformConfig{}).transformPrice(priceData("25")); !transformed.Price.Equal(decimal.RequireFromString("25")) {
+		t.Errorf("expected identity transform by default, got %s", transformed.Price)
+	}
+
+	haircut := newSvc(PriceTransformConfig{Name: PriceTransformHaircut, Fraction: 0.01})
+	if transformed := haircut.transformPrice(priceData("25")); !transformed.Price.Equal(decimal.RequireFromString("24.75")) {
+		t.Errorf("expected 1%% haircut price 24.75, got %s", transformed.Price)
+	}
+
+	stork := priceData("25")
+	stork.OracleType = oracletypes.OracleType_Stork
+	if transformed := haircut.transformPrice(stork); transformed != stork {
+		t.Errorf("expected Stork price left as pulled, got %s", transformed.Price)
+	}
+
+	clamp := newSvc(PriceTransformConfig{Name: PriceTransformClampToBand, Fraction: 0.1})
+	if transformed := clamp.transformPrice(priceData("50")); !transformed.Price.Equal(decimal.RequireFromString("50")) {
+		t.Errorf("expected price without a submission left as pulled, got %s", transformed.Price)
+	}
+
+	clamp.recordSubmittedPrices([]*PriceData{priceData("20")}, time.Now())
+	for pulled, expected := range map[string]string{
+		"50": "22",
+		"10": "18",
+		"21": "21",
+	} {
+		if transformed := clamp.transformPrice(priceData(pulled)); !transformed.Price.Equal(decimal.RequireFromString(expected)) {
+			t.Errorf("expected pulled price %s clamped to %s, got %s", pulled, expected, transformed.Price)
+		}
+	}
+
+	for _, cfg := range []PriceTransformConfig{
+		{Name: "unknown"},
+		{Name: PriceTransformHaircut},
+		{Name: PriceTransformClampToBand, Fraction: 1},
+	} {
+		if _, err := NewService(context.Background(), nil, nil, nil, map[string]*FeedConfig{}, nil, ServiceConfig{PriceTransform: cfg}); err == nil {
+			t.Errorf("expected error for price transform %s with fraction %v", cfg.Name, cfg.Fraction)
+		}
+	}
+}
@@ -1,8 +1,10 @@
 package oracle
 
 import (
+	"math/rand"
 	"net/url"
 	"path"
+	"time"
 )
 
 func urlJoin(baseURL string, segments ...string) string {
@@ -20,3 +22,38 @@ func stripToken(reqURL *url.URL) *url.URL {
 	reqURL.RawQuery = v.Encode()
 	return reqURL
 }
+
+// withJitter randomly spreads the duration by up to ±fraction of it.
+func withJitter(d time.Duration, fraction float64) time.Duration {
+	if fraction <= 0 {
+		return d
+	}
+
+	return d + time.Duration(fraction*float64(d)*(2*rand.Float64()-1))
+}
+
+// startupJitter returns a random delay of up to fraction of the interval,
+// used to spread the first pulls of feeds started at the same time.
+func startupJitter(interval time.Duration, fraction float64) time.Duration {
+	if fraction <= 0 {
+		return 0
+	}
+
+	return time.Duration(fraction * float64(interval) * rand.Float64())
+}
+
+// initialPullDelay returns the delay of the first pull of a feed, that is the start delay plus a random spread
+// over the window, or a startup jitter of the interval if there's no window. The spread is capped at the interval,
+// so feeds with short intervals still pull their first price in time.
+func initialPullDelay(startDelay, window, interval time.Duration, fraction float64) time.Duration {
+	spread := startupJitter(interval, fraction)
+	if window > 0 {
+		spread = time.Duration(rand.Int63n(int64(window)))
+	}
+
+	if interval > 0 && spread > interval {
+		spread = interval
+	}
+
+	return startDelay + spread
+}
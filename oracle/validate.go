@@ -0,0 +1,141 @@
+package oracle
+
+import (
+	"fmt"
+	"reflect"
+	"time"
+
+	oracletypes "github.com/InjectiveLabs/sdk-go/chain/oracle/types"
+)
+
+// ValidationError is a problem of a feed config found by ValidateConfig.
+type ValidationError struct {
+	// Feed is the index of the feed in a file declaring many feeds, 0 for a single feed,
+	// or -1 if the whole file is invalid.
+	Feed   int    `json:"feed"`
+	Ticker string `json:"ticker,omitempty"`
+	// Field is the config field at fault, if known.
+	Field   string `json:"field,omitempty"`
+	Message string `json:"message"`
+}
+
+func (e ValidationError) Error() string {
+	prefix := "feed config"
+	if e.Feed >= 0 {
+		prefix = fmt.Sprintf("feed #%d", e.Feed)
+		if len(e.Ticker) > 0 {
+			prefix = fmt.Sprintf("%s (%s)", prefix, e.Ticker)
+		}
+	}
+
+	if len(e.Field) > 0 {
+		return fmt.Sprintf("%s: %s: %s", prefix, e.Field, e.Message)
+	}
+
+	return fmt.Sprintf("%s: %s", prefix, e.Message)
+}
+
+// ValidateConfig checks a feed config file in the given format, without initializing feeds or pulling
+// prices, so it's fast and makes no network calls. Unlike parsing, it reports all problems found,
+// of every feed of the file. Defaults are merged into every feed first, if not nil.
+func ValidateConfig(body []byte, format string, defaults *FeedConfig) []ValidationError {
+	fileError := func(err error) []ValidationError {
+		return []ValidationError{{Feed: -1, Message: err.Error()}}
+	}
+
+	var file struct {
+		Feeds []*FeedConfig `toml:"feeds" yaml:"feeds" json:"feeds"`
+	}
+
+	if err := unmarshalFeedConfig(body, format, &file); err != nil {
+		return fileError(err)
+	}
+
+	var topLevel FeedConfig
+	if err := unmarshalFeedConfig(body, format, &topLevel); err != nil {
+		return fileError(err)
+	}
+
+	feeds := file.Feeds
+	if len(feeds) == 0 {
+		feeds = []*FeedConfig{&topLevel}
+	} else if !reflect.DeepEqual(topLevel, FeedConfig{}) {
+		return []ValidationError{{
+			Feed:    -1,
+			Field:   "feeds",
+			Message: "feed config must declare either a single feed at the top level, or a list of feeds, not both",
+		}}
+	}
+
+	var errs []ValidationError
+	tickers := make(map[string]int, len(feeds))
+	for i, config := range feeds {
+		if prev, ok := tickers[config.Ticker]; ok && len(config.Ticker) > 0 {
+			errs = append(errs, ValidationError{
+				Feed:    i,
+				Ticker:  config.Ticker,
+				Field:   "ticker",
+				Message: fmt.Sprintf("duplicate ticker of feed #%d", prev),
+			})
+		}
+		tickers[config.Ticker] = i
+
+		if err := config.applyDefaults(defaults); err != nil {
+			errs = append(errs, ValidationError{Feed: i, Ticker: config.Ticker, Message: err.Error()})
+			continue
+		}
+
+		errs = append(errs, validateFeedFields(i, config)...)
+	}
+
+	return errs
+}
+
+// validateFeedFields checks required fields of a feed, then runs the checks of parsing.
+func validateFeedFields(i int, config *FeedConfig) (errs []ValidationError) {
+	fieldError := func(field, message string) {
+		errs = append(errs, ValidationError{Feed: i, Ticker: config.Ticker, Field: field, Message: message})
+	}
+
+	if len(config.Ticker) == 0 {
+		fieldError("ticker", "must be set")
+	}
+
+	if len(config.ProviderName) == 0 {
+		fieldError("provider", "must be set")
+	} else if !isNativeProvider(FeedProvider(config.ProviderName)) && len(config.ObservationSource) == 0 {
+		fieldError("observationSource", fmt.Sprintf("must be set for %s provider, which has no native implementation", config.ProviderName))
+	}
+
+	if len(config.PullInterval) > 0 {
+		if interval, err := time.ParseDuration(config.PullInterval); err != nil || interval < time.Second {
+			fieldError("pullInterval", fmt.Sprintf("expected a duration of at least 1s, got %s", config.PullInterval))
+		}
+	}
+
+	if len(config.OracleType) > 0 {
+		if _, ok := oracletypes.OracleType_value[config.OracleType]; !ok {
+			fieldError("oracleType", fmt.Sprintf("oracle type does not exist: %s", config.OracleType))
+		}
+	}
+
+	if err := validateFeedConfig(config); err != nil {
+		errs = append(errs, ValidationError{Feed: i, Ticker: config.Ticker, Message: err.Error()})
+	}
+
+	return errs
+}
+
+// isNativeProvider tells whether feeds of the provider are pulled by a native implementation,
+// rather than run as a dynamic feed pipeline.
+func isNativeProvider(provider FeedProvider) bool {
+	switch provider {
+	case FeedProviderBinance, FeedProviderGateio, FeedProviderKucoin, FeedProviderHTX, FeedProviderBitfinex,
+		FeedProviderDeribit, FeedProviderDIA, FeedProviderRedstone, FeedProviderAPI3, FeedProviderSwitchboard,
+		FeedProviderJupiter, FeedProviderWebsocket, FeedProviderFallback, FeedProviderConversion,
+		FeedProviderConstant, FeedProviderStork:
+		return true
+	default:
+		return false
+	}
+}
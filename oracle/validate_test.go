@@ -0,0 +1,54 @@
+package oracle
+
+import (
+	"testing"
+)
+
+func TestValidateConfig(t *testing.T) {
+	const feeds = `
+[[feeds]]
+provider = "custom"
+ticker = "INJ/USDT"
+pullInterval = "500ms"
+
+[[feeds]]
+provider = "binance"
+ticker = "INJ/USDT"
+
+[[feeds]]
+provider = "custom"
+ticker = "ATOM/USDT"
+observationSource = "result [type=memo value=1"
+`
+
+	expected := []ValidationError{
+		{Feed: 0, Ticker: "INJ/USDT", Field: "observationSource"},
+		{Feed: 0, Ticker: "INJ/USDT", Field: "pullInterval"},
+		{Feed: 1, Ticker: "INJ/USDT", Field: "ticker"},
+		{Feed: 2, Ticker: "ATOM/USDT"},
+	}
+
+	errs := ValidateConfig([]byte(feeds), FeedConfigFormatTOML, nil)
+	if len(errs) != len(expected) {
+		t.Fatalf("expected %d errors, got %v", len(expected), errs)
+	}
+
+	for i, err := range errs {
+		if err.Feed != expected[i].Feed || err.Ticker != expected[i].Ticker || err.Field != expected[i].Field {
+			t.Errorf("expected error %d of feed #%d field %q, got %v", i, expected[i].Feed, expected[i].Field, err)
+		}
+	}
+
+	errs = ValidateConfig([]byte(`provider = "binance"`), FeedConfigFormatTOML, &FeedConfig{PullInterval: "30s"})
+	if len(errs) != 1 || errs[0].Field != "ticker" {
+		t.Errorf("expected missing ticker error, got %v", errs)
+	}
+
+	if errs := ValidateConfig([]byte(`ticker = `), FeedConfigFormatTOML, nil); len(errs) != 1 || errs[0].Feed != -1 {
+		t.Errorf("expected file error, got %v", errs)
+	}
+
+	if errs := ValidateConfig([]byte("provider = \"binance\"\nticker = \"INJ/USDT\""), FeedConfigFormatTOML, nil); len(errs) != 0 {
+		t.Errorf("unexpected errors: %v", errs)
+	}
+}
@@ -0,0 +1,313 @@
+package pipeline
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"math/big"
+
+	"github.com/pkg/errors"
+)
+
+// maxCBORDepth limits nesting of decoded CBOR items, so a malicious payload can't exhaust the stack.
+const maxCBORDepth = 64
+
+const cborBreak = 0xff
+
+// cborDecoder decodes a CBOR (RFC 8949) payload into the same Go types as encoding/json produces for
+// containers, so the result can be traversed like parsed JSON. Integers decode to int64, or *big.Int if
+// they don't fit, floats to float64, byte strings to []byte, and bignums (tags 2 and 3) to *big.Int.
+// Other tags are dropped, leaving the tagged item.
+type cborDecoder struct {
+	data []byte
+	pos  int
+}
+
+// decodeCBOR decodes a single CBOR item, failing on trailing bytes.
+func decodeCBOR(data []byte) (interface{}, error) {
+	d := &cborDecoder{data: data}
+
+	value, err := d.decode(0)
+	if err != nil {
+		return nil, err
+	} else if d.pos != len(d.data) {
+		return nil, errors.Errorf("cbor: %d trailing bytes", len(d.data)-d.pos)
+	}
+
+	return value, nil
+}
+
+func (d *cborDecoder) readByte() (byte, error) {
+	if d.pos >= len(d.data) {
+		return 0, errors.New("cbor: unexpected end of data")
+	}
+
+	b := d.data[d.pos]
+	d.pos++
+	return b, nil
+}
+
+func (d *cborDecoder) readBytes(n uint64) ([]byte, error) {
+	if n > uint64(len(d.data)-d.pos) {
+		return nil, errors.New("cbor: unexpected end of data")
+	}
+
+	b := d.data[d.pos : d.pos+int(n)]
+	d.pos += int(n)
+	return b, nil
+}
+
+// readArgument reads the argument of an item head, returning indefinite as true for the
+// indefinite length marker.
+func (d *cborDecoder) readArgument(info byte) (arg uint64, indefinite bool, err error) {
+	switch {
+	case info < 24:
+		return uint64(info), false, nil
+	case info == 24:
+		b, err := d.readByte()
+		return uint64(b), false, err
+	case info == 25:
+		b, err := d.readBytes(2)
+		if err != nil {
+			return 0, false, err
+		}
+		return uint64(binary.BigEndian.Uint16(b)), false, nil
+	case info == 26:
+		b, err := d.readBytes(4)
+		if err != nil {
+			return 0, false, err
+		}
+		return uint64(binary.BigEndian.Uint32(b)), false, nil
+	case info == 27:
+		b, err := d.readBytes(8)
+		if err != nil {
+			return 0, false, err
+		}
+		return binary.BigEndian.Uint64(b), false, nil
+	case info == 31:
+		return 0, true, nil
+	default:
+		return 0, false, errors.Errorf("cbor: invalid additional info %d", info)
+	}
+}
+
+func (d *cborDecoder) atBreak() bool {
+	return d.pos < len(d.data) && d.data[d.pos] == cborBreak
+}
+
+func (d *cborDecoder) decode(depth int) (interface{}, error) {
+	if depth > maxCBORDepth {
+		return nil, errors.New("cbor: maximum nesting depth exceeded")
+	}
+
+	head, err := d.readByte()
+	if err != nil {
+		return nil, err
+	}
+
+	majorType, info := head>>5, head&0x1f
+
+	// floats and simple values use the additional info differently
+	if majorType == 7 {
+		return d.decodeSimple(info)
+	}
+
+	arg, indefinite, err := d.readArgument(info)
+	if err != nil {
+		return nil, err
+	} else if indefinite && (majorType < 2 || majorType == 6) {
+		return nil, errors.Errorf("cbor: indefinite length is invalid for major type %d", majorType)
+	}
+
+	switch majorType {
+	case 0:
+		if arg > math.MaxInt64 {
+			return new(big.Int).SetUint64(arg), nil
+		}
+		return int64(arg), nil
+
+	case 1:
+		if arg > math.MaxInt64 {
+			n := new(big.Int).SetUint64(arg)
+			return n.Neg(n).Sub(n, big.NewInt(1)), nil
+		}
+		return -1 - int64(arg), nil
+
+	case 2, 3:
+		var b []byte
+		if indefinite {
+			b, err = d.decodeChunks(majorType)
+		} else {
+			b, err = d.readBytes(arg)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if majorType == 3 {
+			return string(b), nil
+		}
+		return append([]byte(nil), b...), nil
+
+	case 4:
+		items := []interface{}{}
+		for i := uint64(0); indefinite || i < arg; i++ {
+			if indefinite && d.atBreak() {
+				d.pos++
+				break
+			}
+
+			item, err := d.decode(depth + 1)
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, item)
+		}
+		return items, nil
+
+	case 5:
+		items := make(map[string]interface{})
+		for i := uint64(0); indefinite || i < arg; i++ {
+			if indefinite && d.atBreak() {
+				d.pos++
+				break
+			}
+
+			key, err := d.decode(depth + 1)
+			if err != nil {
+				return nil, err
+			}
+
+			value, err := d.decode(depth + 1)
+			if err != nil {
+				return nil, err
+			}
+
+			if s, ok := key.(string); ok {
+				items[s] = value
+			} else {
+				items[fmt.Sprint(key)] = value
+			}
+		}
+		return items, nil
+
+	default: // 6, a tag
+		item, err := d.decode(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+
+		if arg == 2 || arg == 3 {
+			b, ok := item.([]byte)
+			if !ok {
+				return nil, errors.Errorf("cbor: bignum tag %d of %T", arg, item)
+			}
+
+			n := new(big.Int).SetBytes(b)
+			if arg == 3 {
+				n.Neg(n).Sub(n, big.NewInt(1))
+			}
+			return n, nil
+		}
+		return item, nil
+	}
+}
+
+// decodeChunks concatenates chunks of an indefinite length byte or text string.
+func (d *cborDecoder) decodeChunks(majorType byte) ([]byte, error) {
+	var b []byte
+	for {
+		if d.atBreak() {
+			d.pos++
+			return b, nil
+		}
+
+		head, err := d.readByte()
+		if err != nil {
+			return nil, err
+		} else if head>>5 != majorType {
+			return nil, errors.Errorf("cbor: chunk of major type %d in indefinite string of major type %d", head>>5, majorType)
+		}
+
+		n, indefinite, err := d.readArgument(head & 0x1f)
+		if err != nil {
+			return nil, err
+		} else if indefinite {
+			return nil, errors.New("cbor: nested indefinite string chunk")
+		}
+
+		chunk, err := d.readBytes(n)
+		if err != nil {
+			return nil, err
+		}
+		b = append(b, chunk...)
+	}
+}
+
+func (d *cborDecoder) decodeSimple(info byte) (interface{}, error) {
+	switch info {
+	case 20:
+		return false, nil
+	case 21:
+		return true, nil
+	case 22, 23:
+		return nil, nil
+	case 24:
+		// one byte simple values are unassigned
+		if _, err := d.readByte(); err != nil {
+			return nil, err
+		}
+		return nil, nil
+	case 25:
+		b, err := d.readBytes(2)
+		if err != nil {
+			return nil, err
+		}
+		return halfToFloat64(binary.BigEndian.Uint16(b)), nil
+	case 26:
+		b, err := d.readBytes(4)
+		if err != nil {
+			return nil, err
+		}
+		return float64(math.Float32frombits(binary.BigEndian.Uint32(b))), nil
+	case 27:
+		b, err := d.readBytes(8)
+		if err != nil {
+			return nil, err
+		}
+		return math.Float64frombits(binary.BigEndian.Uint64(b)), nil
+	case 31:
+		return nil, errors.New("cbor: unexpected break")
+	default:
+		if info < 20 {
+			// unassigned simple values
+			return nil, nil
+		}
+		return nil, errors.Errorf("cbor: invalid simple value %d", info)
+	}
+}
+
+// halfToFloat64 converts an IEEE 754 half-precision float, as specified in RFC 8949 appendix D.
+func halfToFloat64(half uint16) float64 {
+	exp := (half >> 10) & 0x1f
+	mant := float64(half & 0x3ff)
+
+	var val float64
+	switch exp {
+	case 0:
+		val = math.Ldexp(mant, -24)
+	case 31:
+		if mant == 0 {
+			val = math.Inf(1)
+		} else {
+			val = math.NaN()
+		}
+	default:
+		val = math.Ldexp(mant+1024, int(exp)-25)
+	}
+
+	if half&0x8000 != 0 {
+		return -val
+	}
+	return val
+}
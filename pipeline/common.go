@@ -282,12 +282,15 @@ func (t TaskType) String() string {
 const (
 	TaskTypeHTTP            TaskType = "http"
 	TaskTypeMean            TaskType = "mean"
+	TaskTypeWeightedMean    TaskType = "weightedmean"
 	TaskTypeMedian          TaskType = "median"
 	TaskTypeMode            TaskType = "mode"
 	TaskTypeSum             TaskType = "sum"
 	TaskTypeMultiply        TaskType = "multiply"
 	TaskTypeDivide          TaskType = "divide"
+	TaskTypeInverse         TaskType = "inverse"
 	TaskTypeJSONParse       TaskType = "jsonparse"
+	TaskTypeCBORParse       TaskType = "cborparse"
 	TaskTypeAny             TaskType = "any"
 	TaskTypeETHABIEncode    TaskType = "ethabiencode"
 	TaskTypeETHABIEncode2   TaskType = "ethabiencode2"
@@ -296,6 +299,15 @@ const (
 	TaskTypeMerge           TaskType = "merge"
 	TaskTypeLowercase       TaskType = "lowercase"
 	TaskTypeUppercase       TaskType = "uppercase"
+	TaskTypeIndex           TaskType = "index"
+	TaskTypeLength          TaskType = "length"
+	TaskTypeParseNumeric    TaskType = "parsenumeric"
+	TaskTypeTimestamp       TaskType = "timestamp"
+	TaskTypeHMAC            TaskType = "hmac"
+	TaskTypeOptional        TaskType = "optional"
+	TaskTypeRangeCheck      TaskType = "rangecheck"
+	TaskTypePrevious        TaskType = "previous"
+	TaskTypeTWAP            TaskType = "twap"
 
 	// Testing only.
 	TaskTypePanic TaskType = "panic"
@@ -326,7 +338,46 @@ func UnmarshalTaskFromMap(taskType TaskType, taskMap interface{}, ID int, dotID
 
 	taskType = TaskType(strings.ToLower(string(taskType)))
 
-	var task Task
+	if err = checkTaskTypeAllowed(taskType); err != nil {
+		return nil, err
+	}
+
+	task, err := newTask(taskType, ID, dotID)
+	if err != nil {
+		return nil, err
+	}
+
+	decoder, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
+		Result:           task,
+		WeaklyTypedInput: true,
+		DecodeHook: mapstructure.ComposeDecodeHookFunc(
+			mapstructure.StringToTimeDurationHookFunc(),
+			func(from reflect.Type, to reflect.Type, data interface{}) (interface{}, error) {
+				if from != stringType {
+					return data, nil
+				}
+				switch to {
+				case nullUint32Type:
+					i, err2 := strconv.ParseUint(data.(string), 10, 32)
+					return cnull.Uint32From(uint32(i)), err2
+				}
+				return data, nil
+			},
+		),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = decoder.Decode(taskMap)
+	if err != nil {
+		return nil, err
+	}
+	return task, nil
+}
+
+// newTask returns an empty task of the type, or an error if the type is unknown.
+func newTask(taskType TaskType, ID int, dotID string) (task Task, err error) {
 	switch taskType {
 	case TaskTypePanic:
 		task = &PanicTask{BaseTask: BaseTask{id: ID, dotID: dotID}}
@@ -334,6 +385,8 @@ func UnmarshalTaskFromMap(taskType TaskType, taskMap interface{}, ID int, dotID
 		task = &HTTPTask{BaseTask: BaseTask{id: ID, dotID: dotID}}
 	case TaskTypeMean:
 		task = &MeanTask{BaseTask: BaseTask{id: ID, dotID: dotID}}
+	case TaskTypeWeightedMean:
+		task = &WeightedMeanTask{BaseTask: BaseTask{id: ID, dotID: dotID}}
 	case TaskTypeMedian:
 		task = &MedianTask{BaseTask: BaseTask{id: ID, dotID: dotID}}
 	case TaskTypeMode:
@@ -344,12 +397,16 @@ func UnmarshalTaskFromMap(taskType TaskType, taskMap interface{}, ID int, dotID
 		task = &AnyTask{BaseTask: BaseTask{id: ID, dotID: dotID}}
 	case TaskTypeJSONParse:
 		task = &JSONParseTask{BaseTask: BaseTask{id: ID, dotID: dotID}}
+	case TaskTypeCBORParse:
+		task = &CBORParseTask{BaseTask: BaseTask{id: ID, dotID: dotID}}
 	case TaskTypeMemo:
 		task = &MemoTask{BaseTask: BaseTask{id: ID, dotID: dotID}}
 	case TaskTypeMultiply:
 		task = &MultiplyTask{BaseTask: BaseTask{id: ID, dotID: dotID}}
 	case TaskTypeDivide:
 		task = &DivideTask{BaseTask: BaseTask{id: ID, dotID: dotID}}
+	case TaskTypeInverse:
+		task = &InverseTask{BaseTask: BaseTask{id: ID, dotID: dotID}}
 	case TaskTypeETHABIEncode:
 		task = &ETHABIEncodeTask{BaseTask: BaseTask{id: ID, dotID: dotID}}
 	case TaskTypeETHABIEncode2:
@@ -366,36 +423,28 @@ func UnmarshalTaskFromMap(taskType TaskType, taskMap interface{}, ID int, dotID
 		task = &LowercaseTask{BaseTask: BaseTask{id: ID, dotID: dotID}}
 	case TaskTypeUppercase:
 		task = &UppercaseTask{BaseTask: BaseTask{id: ID, dotID: dotID}}
+	case TaskTypeIndex:
+		task = &IndexTask{BaseTask: BaseTask{id: ID, dotID: dotID}}
+	case TaskTypeLength:
+		task = &LengthTask{BaseTask: BaseTask{id: ID, dotID: dotID}}
+	case TaskTypeParseNumeric:
+		task = &ParseNumericTask{BaseTask: BaseTask{id: ID, dotID: dotID}}
+	case TaskTypeTimestamp:
+		task = &TimestampTask{BaseTask: BaseTask{id: ID, dotID: dotID}}
+	case TaskTypeHMAC:
+		task = &HMACTask{BaseTask: BaseTask{id: ID, dotID: dotID}}
+	case TaskTypeOptional:
+		task = &OptionalTask{BaseTask: BaseTask{id: ID, dotID: dotID}}
+	case TaskTypeRangeCheck:
+		task = &RangeCheckTask{BaseTask: BaseTask{id: ID, dotID: dotID}}
+	case TaskTypePrevious:
+		task = &PreviousTask{BaseTask: BaseTask{id: ID, dotID: dotID}}
+	case TaskTypeTWAP:
+		task = &TWAPTask{BaseTask: BaseTask{id: ID, dotID: dotID}}
 	default:
 		return nil, errors.Errorf(`unknown task type: "%v"`, taskType)
 	}
 
-	decoder, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
-		Result:           task,
-		WeaklyTypedInput: true,
-		DecodeHook: mapstructure.ComposeDecodeHookFunc(
-			mapstructure.StringToTimeDurationHookFunc(),
-			func(from reflect.Type, to reflect.Type, data interface{}) (interface{}, error) {
-				if from != stringType {
-					return data, nil
-				}
-				switch to {
-				case nullUint32Type:
-					i, err2 := strconv.ParseUint(data.(string), 10, 32)
-					return cnull.Uint32From(uint32(i)), err2
-				}
-				return data, nil
-			},
-		),
-	})
-	if err != nil {
-		return nil, err
-	}
-
-	err = decoder.Decode(taskMap)
-	if err != nil {
-		return nil, err
-	}
 	return task, nil
 }
 
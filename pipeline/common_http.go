@@ -7,6 +7,7 @@ import (
 	"io"
 	"net/http"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/pkg/errors"
@@ -21,12 +22,17 @@ func makeHTTPRequest(
 	url URLParam,
 	requestData MapParam,
 	headerMap MapParam,
+	signer *httpRequestSigner,
+	maxResponseBytes int64,
 ) ([]byte, int, http.Header, time.Duration, error) {
 
-	var bodyReader io.Reader
+	var (
+		bodyReader io.Reader
+		bodyBytes  []byte
+	)
 	if requestData != nil {
-		bodyBytes, err := json.Marshal(requestData)
-		if err != nil {
+		var err error
+		if bodyBytes, err = json.Marshal(requestData); err != nil {
 			return nil, 0, nil, 0, errors.Wrap(err, "failed to encode request body as JSON")
 		}
 		bodyReader = bytes.NewReader(bodyBytes)
@@ -36,8 +42,23 @@ func makeHTTPRequest(
 	if err != nil {
 		return nil, 0, nil, 0, errors.Wrap(err, "failed to create http.Request")
 	}
+
+	policy, client := httpPolicy()
+	if policy != nil {
+		if err := policy.checkURL(ctx, request.URL); err != nil {
+			return nil, 0, nil, 0, errors.Wrap(err, "http request rejected by the access policy")
+		}
+	}
 	request.Header.Set("Content-Type", "application/json")
 
+	for key, values := range httpDefaultHeaders() {
+		if strings.ToLower(key) == lowerContentTypeKey {
+			continue
+		}
+
+		request.Header[key] = values
+	}
+
 	for key, value := range headerMap {
 		if strings.ToLower(key) == lowerContentTypeKey {
 			// skip Content-Type override attempts
@@ -47,8 +68,16 @@ func makeHTTPRequest(
 		request.Header.Set(key, value.(string))
 	}
 
+	if signer != nil {
+		if err := signer.sign(request, bodyBytes); err != nil {
+			return nil, 0, nil, 0, errors.Wrap(err, "failed to sign http request")
+		}
+	}
+
 	httpRequest := HTTPRequest{
-		Request: request,
+		Request:          request,
+		Client:           client,
+		MaxResponseBytes: maxResponseBytes,
 		Logger: lggr.WithFields(log.Fields{
 			"svc":    "pipeline",
 			"action": "HTTPRequest",
@@ -74,6 +103,27 @@ func makeHTTPRequest(
 
 var lowerContentTypeKey = strings.ToLower("Content-Type")
 
+var (
+	defaultHTTPHeaders   http.Header
+	defaultHTTPHeadersMu sync.RWMutex
+)
+
+// SetHTTPDefaultHeaders sets headers sent with every request of the http task, e.g. a User-Agent
+// for providers that reject Go's default one. Headers of the task's headerMap take precedence.
+func SetHTTPDefaultHeaders(header http.Header) {
+	defaultHTTPHeadersMu.Lock()
+	defer defaultHTTPHeadersMu.Unlock()
+
+	defaultHTTPHeaders = header.Clone()
+}
+
+func httpDefaultHeaders() http.Header {
+	defaultHTTPHeadersMu.RLock()
+	defer defaultHTTPHeadersMu.RUnlock()
+
+	return defaultHTTPHeaders
+}
+
 type PossibleErrorResponses struct {
 	Error        string `json:"error"`
 	ErrorMessage string `json:"errorMessage"`
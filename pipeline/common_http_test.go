@@ -0,0 +1,124 @@
+package pipeline
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+
+	log "github.com/InjectiveLabs/suplog"
+)
+
+func TestHTTPDefaultHeaders(t *testing.T) {
+	headers := make(chan http.Header, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		headers <- r.Header
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer srv.Close()
+
+	u, err := url.Parse(srv.URL)
+	if err != nil {
+		t.Fatalf("failed to parse url: %v", err)
+	}
+
+	SetHTTPDefaultHeaders(http.Header{
+		"User-Agent":   []string{"injective-price-oracle/test"},
+		"Accept":       []string{"application/json"},
+		"Content-Type": []string{"text/plain"},
+	})
+	defer SetHTTPDefaultHeaders(nil)
+
+	headerMap := MapParam{"Accept": "*/*"}
+	if _, _, _, _, err := makeHTTPRequest(context.Background(), log.DefaultLogger, "GET", URLParam(*u), nil, headerMap, nil, 0); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	header := <-headers
+	for key, expected := range map[string]string{
+		"User-Agent":   "injective-price-oracle/test",
+		"Accept":       "*/*",
+		"Content-Type": "application/json",
+	} {
+		if value := header.Get(key); value != expected {
+			t.Errorf("expected %s header %q, got %q", key, expected, value)
+		}
+	}
+}
+
+type recordingHTTPMetricsReporter struct {
+	mu            sync.Mutex
+	responseSizes []int
+	tooLarge      []string
+}
+
+func (r *recordingHTTPMetricsReporter) RequestBodySize(string, string, int) {}
+
+func (r *recordingHTTPMetricsReporter) ResponseBodySize(_, _ string, size int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.responseSizes = append(r.responseSizes, size)
+}
+
+func (r *recordingHTTPMetricsReporter) ResponseTooLarge(job, dotID string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.tooLarge = append(r.tooLarge, job+"/"+dotID)
+}
+
+func TestHTTPTaskMaxResponseBytes(t *testing.T) {
+	const response = `{"price": "25.5", "padding": "0123456789"}`
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(response))
+	}))
+	defer srv.Close()
+
+	reporter := &recordingHTTPMetricsReporter{}
+	SetHTTPMetricsReporter(reporter)
+	defer SetHTTPMetricsReporter(nil)
+
+	for maxResponseBytes, wantErr := range map[string]bool{
+		"":         false,
+		"64":       false,
+		"16":       true,
+		"$(limit)": true,
+	} {
+		spec := Spec{
+			JobName:      "test_max_response_bytes",
+			DotDagSource: `req [type=http url="` + srv.URL + `" maxResponseBytes="` + maxResponseBytes + `"]`,
+		}
+
+		vars := NewVarsFrom(map[string]interface{}{"limit": 16})
+		_, trrs, err := NewRunner(log.DefaultLogger).ExecuteRun(context.Background(), spec, vars, log.DefaultLogger)
+		if err != nil {
+			t.Fatalf("failed to execute run: %v", err)
+		}
+
+		result := trrs.FinalResult(log.DefaultLogger)
+		if !wantErr {
+			if result.HasFatalErrors() {
+				t.Errorf("maxResponseBytes %q: unexpected fatal errors: %v", maxResponseBytes, result.FatalErrors)
+			}
+			continue
+		}
+
+		if !result.HasFatalErrors() {
+			t.Errorf("maxResponseBytes %q: expected an error for a response over the limit", maxResponseBytes)
+		} else if !errors.Is(result.FatalErrors[0], ErrResponseTooLarge) {
+			t.Errorf("maxResponseBytes %q: expected ErrResponseTooLarge, got %v", maxResponseBytes, result.FatalErrors[0])
+		}
+	}
+
+	if len(reporter.responseSizes) != 2 || reporter.responseSizes[0] != len(response) || reporter.responseSizes[1] != len(response) {
+		t.Errorf("expected 2 response sizes of %d bytes reported, got %v", len(response), reporter.responseSizes)
+	}
+
+	if len(reporter.tooLarge) != 2 || reporter.tooLarge[0] != "test_max_response_bytes/req" {
+		t.Errorf("expected 2 too large responses of the req task reported, got %v", reporter.tooLarge)
+	}
+}
@@ -246,5 +246,9 @@ func Parse(text string) (*Pipeline, error) {
 		ids[node.ID()] = id
 	}
 
+	if err := validateOptionalInputs(p); err != nil {
+		return nil, err
+	}
+
 	return p, nil
 }
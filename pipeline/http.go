@@ -7,18 +7,33 @@ import (
 	"time"
 
 	log "github.com/InjectiveLabs/suplog"
+	"github.com/pkg/errors"
 )
 
+// DefaultHTTPMaxResponseBytes caps response bodies read by the http task, unless it sets maxResponseBytes.
+const DefaultHTTPMaxResponseBytes int64 = 10 * 1024 * 1024
+
+var ErrResponseTooLarge = errors.New("http response body too large")
+
 // HTTPRequest holds the request and config struct for a http request
 type HTTPRequest struct {
 	Request *http.Request
 	Logger  log.Logger
+
+	// Client sends the request, a default client if nil.
+	Client *http.Client
+
+	// MaxResponseBytes caps the response body, DefaultHTTPMaxResponseBytes if not positive.
+	MaxResponseBytes int64
 }
 
 // SendRequest sends a HTTPRequest,
 // returns a body, status code, and error.
 func (h *HTTPRequest) SendRequest() (responseBody []byte, statusCode int, headers http.Header, err error) {
-	var client *http.Client = &http.Client{}
+	client := h.Client
+	if client == nil {
+		client = &http.Client{}
+	}
 	start := time.Now()
 
 	r, err := client.Do(h.Request)
@@ -33,9 +48,17 @@ func (h *HTTPRequest) SendRequest() (responseBody []byte, statusCode int, header
 	elapsed := time.Since(start)
 	h.Logger.Debugln(fmt.Sprintf("http adapter got %v in %s", statusCode, elapsed), "statusCode", statusCode, "timeElapsedSeconds", elapsed)
 
-	source := http.MaxBytesReader(nil, r.Body, 10*1024*1024)
+	maxResponseBytes := h.MaxResponseBytes
+	if maxResponseBytes <= 0 {
+		maxResponseBytes = DefaultHTTPMaxResponseBytes
+	}
+
+	source := http.MaxBytesReader(nil, r.Body, maxResponseBytes)
 	bytes, err := io.ReadAll(source)
-	if err != nil {
+	if maxBytesErr := (*http.MaxBytesError)(nil); errors.As(err, &maxBytesErr) {
+		h.Logger.Warningln("http adapter response body exceeds", maxResponseBytes, "bytes", "statusCode", statusCode)
+		return nil, statusCode, nil, errors.Wrapf(ErrResponseTooLarge, "limit is %d bytes", maxResponseBytes)
+	} else if err != nil {
 		h.Logger.Errorln("http adapter error reading body", "error", err)
 		return nil, statusCode, nil, err
 	}
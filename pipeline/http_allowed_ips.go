@@ -4,6 +4,10 @@ import (
 	"context"
 	"fmt"
 	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/pkg/errors"
@@ -54,28 +58,225 @@ func isRestrictedIP(ip net.IP) bool {
 	return false
 }
 
-var ErrDisallowedIP = errors.New("disallowed IP")
+var (
+	ErrDisallowedIP   = errors.New("disallowed IP")
+	ErrDisallowedHost = errors.New("disallowed host")
+)
+
+// HTTPAccessPolicy restricts the hosts and addresses the http task may connect to, e.g. to keep feed configs
+// from less trusted sources from reaching cloud metadata endpoints or internal services. Entries of Allow and
+// Deny are either hostnames, optionally with a leading wildcard (*.example.com), or IP addresses and CIDR ranges.
+type HTTPAccessPolicy struct {
+	// DenyPrivateIPs rejects loopback, private, link-local and multicast addresses, unless allowed by an IP range of Allow.
+	DenyPrivateIPs bool
+
+	// Allow, if set, lists the only hosts, or IP ranges, the http task may connect to.
+	Allow []string
+
+	// Deny lists hosts and IP ranges the http task may never connect to, taking precedence over Allow.
+	Deny []string
+}
+
+type httpAccessPolicy struct {
+	denyPrivateIPs bool
+	allowedHosts   []string
+	allowedIPs     []*net.IPNet
+	deniedHosts    []string
+	deniedIPs      []*net.IPNet
+}
+
+var (
+	activeHTTPPolicy   *httpAccessPolicy
+	activeHTTPClient   = &http.Client{}
+	activeHTTPPolicyMu sync.RWMutex
+)
+
+// SetHTTPAccessPolicy enforces the policy on all following requests of the http task. An empty policy allows all.
+func SetHTTPAccessPolicy(policy HTTPAccessPolicy) error {
+	compiled := &httpAccessPolicy{
+		denyPrivateIPs: policy.DenyPrivateIPs,
+	}
+
+	var err error
+	if compiled.allowedHosts, compiled.allowedIPs, err = parseHTTPAccessList(policy.Allow); err != nil {
+		return errors.Wrap(err, "allow")
+	} else if compiled.deniedHosts, compiled.deniedIPs, err = parseHTTPAccessList(policy.Deny); err != nil {
+		return errors.Wrap(err, "deny")
+	}
+
+	client := &http.Client{}
+	if compiled.empty() {
+		compiled = nil
+	} else {
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		transport.DialContext = compiled.dialContext
+		client.Transport = transport
+	}
+
+	activeHTTPPolicyMu.Lock()
+	defer activeHTTPPolicyMu.Unlock()
+
+	activeHTTPPolicy = compiled
+	activeHTTPClient = client
+	return nil
+}
+
+// httpPolicy returns the active policy, nil if requests are not restricted, and the client enforcing it.
+func httpPolicy() (*httpAccessPolicy, *http.Client) {
+	activeHTTPPolicyMu.RLock()
+	defer activeHTTPPolicyMu.RUnlock()
+
+	return activeHTTPPolicy, activeHTTPClient
+}
+
+func parseHTTPAccessList(entries []string) (hosts []string, ipNets []*net.IPNet, err error) {
+	for _, entry := range entries {
+		entry = strings.ToLower(strings.TrimSpace(entry))
+		if len(entry) == 0 {
+			continue
+		}
+
+		if strings.Contains(entry, "/") {
+			_, ipNet, err := net.ParseCIDR(entry)
+			if err != nil {
+				return nil, nil, errors.Wrapf(err, "invalid IP range %s", entry)
+			}
+			ipNets = append(ipNets, ipNet)
+		} else if ip := net.ParseIP(entry); ip != nil {
+			bits := 8 * net.IPv6len
+			if ip.To4() != nil {
+				ip, bits = ip.To4(), 8*net.IPv4len
+			}
+			ipNets = append(ipNets, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+		} else {
+			hosts = append(hosts, entry)
+		}
+	}
+
+	return hosts, ipNets, nil
+}
+
+func (p *httpAccessPolicy) empty() bool {
+	return !p.denyPrivateIPs && len(p.allowedHosts) == 0 && len(p.allowedIPs) == 0 &&
+		len(p.deniedHosts) == 0 && len(p.deniedIPs) == 0
+}
+
+// checkHost checks the hostname of a request, returning whether it's allowed by name. A host that isn't
+// allowed by name may still be allowed by the IP ranges of its addresses. IP literals are never allowed by name.
+func (p *httpAccessPolicy) checkHost(host string) (allowedByName bool, err error) {
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+	if net.ParseIP(host) != nil {
+		return false, nil
+	}
+
+	if matchesHost(p.deniedHosts, host) {
+		return false, errors.Wrapf(ErrDisallowedHost, "host %s is denied", host)
+	}
+
+	allowedByName = matchesHost(p.allowedHosts, host)
+	if !allowedByName && len(p.allowedHosts) > 0 && len(p.allowedIPs) == 0 {
+		return false, errors.Wrapf(ErrDisallowedHost, "host %s is not allowed", host)
+	}
+
+	return allowedByName, nil
+}
+
+// checkIP checks an address the http task is about to connect to.
+func (p *httpAccessPolicy) checkIP(ip net.IP, allowedByName bool) error {
+	if matchesIP(p.deniedIPs, ip) {
+		return errors.Wrapf(ErrDisallowedIP, "IP %s is denied", ip)
+	}
+
+	allowedByRange := matchesIP(p.allowedIPs, ip)
+	if !allowedByName && !allowedByRange && (len(p.allowedHosts) > 0 || len(p.allowedIPs) > 0) {
+		return errors.Wrapf(ErrDisallowedIP, "IP %s is not allowed", ip)
+	}
+
+	if !allowedByRange && p.denyPrivateIPs && isRestrictedIP(ip) {
+		return errors.Wrapf(ErrDisallowedIP, "IP %s is a local, private or multicast address", ip)
+	}
+
+	return nil
+}
+
+// checkURL checks the host of a request URL and every address it resolves to, before the request is made.
+func (p *httpAccessPolicy) checkURL(ctx context.Context, u *url.URL) error {
+	host := u.Hostname()
+	allowedByName, err := p.checkHost(host)
+	if err != nil {
+		return err
+	}
+
+	if ip := net.ParseIP(host); ip != nil {
+		return p.checkIP(ip, false)
+	}
+
+	addrs, err := net.DefaultResolver.LookupIPAddr(ctx, host)
+	if err != nil {
+		return errors.Wrapf(err, "failed to resolve host %s", host)
+	}
+
+	for _, addr := range addrs {
+		if err := p.checkIP(addr.IP, allowedByName); err != nil {
+			return errors.Wrapf(err, "host %s", host)
+		}
+	}
+
+	return nil
+}
+
+// dialContext checks the address of every connection once established, so redirects
+// and DNS changes after checkURL can't bypass the policy.
+func (p *httpAccessPolicy) dialContext(ctx context.Context, network, address string) (net.Conn, error) {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return nil, err
+	}
+
+	allowedByName, err := p.checkHost(host)
+	if err != nil {
+		return nil, err
+	}
 
-// restrictedDialContext wraps the Dialer such that after successful connection,
-// we check the IP.
-// If the resolved IP is restricted, close the connection and return an error.
-func restrictedDialContext(ctx context.Context, network, address string) (net.Conn, error) {
 	con, err := (&net.Dialer{
 		// Defaults from GoLang standard http package
 		// https://golang.org/pkg/net/http/#RoundTripper
 		Timeout:   30 * time.Second,
 		KeepAlive: 30 * time.Second,
-		DualStack: true,
 	}).DialContext(ctx, network, address)
-	if err == nil {
-		// If a connection could be established, ensure it's not local or private
-		a, _ := con.RemoteAddr().(*net.TCPAddr)
-
-		if isRestrictedIP(a.IP) {
-			return nil, multierr.Combine(
-				errors.Wrapf(ErrDisallowedIP, "disallowed IP %s. Connections to local/private and multicast networks are disabled by default for security reasons", a.IP.String()),
-				con.Close())
+	if err != nil {
+		return nil, err
+	}
+
+	if a, ok := con.RemoteAddr().(*net.TCPAddr); ok {
+		if err := p.checkIP(a.IP, allowedByName); err != nil {
+			return nil, multierr.Combine(err, con.Close())
+		}
+	}
+
+	return con, nil
+}
+
+func matchesHost(patterns []string, host string) bool {
+	for _, pattern := range patterns {
+		if suffix, ok := strings.CutPrefix(pattern, "*."); ok {
+			if strings.HasSuffix(host, "."+suffix) {
+				return true
+			}
+		} else if host == pattern {
+			return true
 		}
 	}
-	return con, err
+
+	return false
+}
+
+func matchesIP(ipNets []*net.IPNet, ip net.IP) bool {
+	for _, ipNet := range ipNets {
+		if ipNet.Contains(ip) {
+			return true
+		}
+	}
+
+	return false
 }
@@ -0,0 +1,117 @@
+package pipeline
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	log "github.com/InjectiveLabs/suplog"
+)
+
+func TestHTTPAccessPolicy(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/redirect" {
+			http.Redirect(w, r, "http://"+strings.Replace(r.Host, "localhost", "127.0.0.1", 1)+"/", http.StatusFound)
+			return
+		}
+
+		_, _ = w.Write([]byte(`{"price": 1}`))
+	}))
+	defer srv.Close()
+
+	// the server listens on 127.0.0.1, also reachable as localhost
+	ipURL := srv.URL
+	hostURL := strings.Replace(srv.URL, "127.0.0.1", "localhost", 1)
+
+	defer func() {
+		_ = SetHTTPAccessPolicy(HTTPAccessPolicy{})
+	}()
+
+	tests := []struct {
+		name   string
+		policy HTTPAccessPolicy
+		url    string
+		err    error
+	}{
+		{
+			name: "No policy",
+			url:  ipURL,
+		},
+		{
+			name:   "Private IP denied",
+			policy: HTTPAccessPolicy{DenyPrivateIPs: true},
+			url:    ipURL,
+			err:    ErrDisallowedIP,
+		},
+		{
+			name:   "Private IP of a host denied",
+			policy: HTTPAccessPolicy{DenyPrivateIPs: true},
+			url:    hostURL,
+			err:    ErrDisallowedIP,
+		},
+		{
+			name:   "Private IP allowed by range",
+			policy: HTTPAccessPolicy{DenyPrivateIPs: true, Allow: []string{"127.0.0.0/8"}},
+			url:    hostURL,
+		},
+		{
+			name:   "Host denied",
+			policy: HTTPAccessPolicy{Deny: []string{"localhost"}},
+			url:    hostURL,
+			err:    ErrDisallowedHost,
+		},
+		{
+			name:   "Host not allowed",
+			policy: HTTPAccessPolicy{Allow: []string{"*.example.com"}},
+			url:    hostURL,
+			err:    ErrDisallowedHost,
+		},
+		{
+			name:   "IP not allowed",
+			policy: HTTPAccessPolicy{Allow: []string{"*.example.com"}},
+			url:    ipURL,
+			err:    ErrDisallowedIP,
+		},
+		{
+			name:   "Host allowed",
+			policy: HTTPAccessPolicy{Allow: []string{"localhost"}},
+			url:    hostURL,
+		},
+		{
+			name:   "Redirect to an IP not allowed",
+			policy: HTTPAccessPolicy{Allow: []string{"localhost"}},
+			url:    hostURL + "/redirect",
+			err:    ErrDisallowedIP,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := SetHTTPAccessPolicy(tt.policy); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			u, err := url.Parse(tt.url)
+			if err != nil {
+				t.Fatalf("failed to parse url: %v", err)
+			}
+
+			_, _, _, _, err = makeHTTPRequest(context.Background(), log.DefaultLogger, "GET", URLParam(*u), nil, nil, nil, 0)
+			if tt.err != nil {
+				if !errors.Is(err, tt.err) {
+					t.Errorf("expected error %v, got %v", tt.err, err)
+				}
+			} else if err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+
+	if err := SetHTTPAccessPolicy(HTTPAccessPolicy{Deny: []string{"10.0.0.0/33"}}); err == nil {
+		t.Errorf("expected error for malformed IP range")
+	}
+}
@@ -0,0 +1,46 @@
+package pipeline
+
+import "sync"
+
+// HTTPMetricsReporter reports metrics of http task requests, so the service embedding the pipeline
+// emits them under its own names. Nothing is reported unless set with SetHTTPMetricsReporter.
+type HTTPMetricsReporter interface {
+	// RequestBodySize reports the size in bytes of a request body of the task dotID of the job.
+	RequestBodySize(job, dotID string, size int)
+
+	// ResponseBodySize reports the size in bytes of a response body of the task dotID of the job.
+	ResponseBodySize(job, dotID string, size int)
+
+	// ResponseTooLarge reports a response of the task dotID of the job over its maxResponseBytes.
+	ResponseTooLarge(job, dotID string)
+}
+
+var (
+	httpMetricsReporter   HTTPMetricsReporter = noopHTTPMetricsReporter{}
+	httpMetricsReporterMu sync.RWMutex
+)
+
+// SetHTTPMetricsReporter sets the reporter of http task metrics, nil disables reporting.
+func SetHTTPMetricsReporter(reporter HTTPMetricsReporter) {
+	httpMetricsReporterMu.Lock()
+	defer httpMetricsReporterMu.Unlock()
+
+	if reporter == nil {
+		reporter = noopHTTPMetricsReporter{}
+	}
+
+	httpMetricsReporter = reporter
+}
+
+func httpMetrics() HTTPMetricsReporter {
+	httpMetricsReporterMu.RLock()
+	defer httpMetricsReporterMu.RUnlock()
+
+	return httpMetricsReporter
+}
+
+type noopHTTPMetricsReporter struct{}
+
+func (noopHTTPMetricsReporter) RequestBodySize(string, string, int)  {}
+func (noopHTTPMetricsReporter) ResponseBodySize(string, string, int) {}
+func (noopHTTPMetricsReporter) ResponseTooLarge(string, string)      {}
@@ -0,0 +1,58 @@
+package pipeline
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"strconv"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	defaultSignatureHeader = "X-Signature"
+	defaultTimestampHeader = "X-Timestamp"
+	defaultNonceHeader     = "X-Nonce"
+)
+
+// httpRequestSigner signs requests of the http task for authenticated exchange APIs, recomputing
+// the timestamp, nonce and signature headers on every request.
+type httpRequestSigner struct {
+	secret    string
+	algorithm string
+	encoding  string
+
+	signatureHeader string
+	timestampHeader string
+	timestampUnit   string
+	nonceHeader     string
+}
+
+// sign sets the timestamp, nonce and signature headers of the request. The signature is the HMAC of
+// the canonical request: timestamp, nonce, method, path with the query and body, concatenated.
+func (s *httpRequestSigner) sign(request *http.Request, body []byte) error {
+	timestamp, err := unixTime(timeNow(), s.timestampUnit)
+	if err != nil {
+		return errors.Wrap(err, "timestampUnit")
+	}
+
+	nonce := make([]byte, 16)
+	if _, err := rand.Read(nonce); err != nil {
+		return errors.Wrap(err, "failed to generate nonce")
+	}
+
+	timestampValue := strconv.FormatInt(timestamp, 10)
+	nonceValue := hex.EncodeToString(nonce)
+
+	payload := timestampValue + nonceValue + request.Method + request.URL.RequestURI() + string(body)
+	signature, err := hmacSignature(s.secret, s.algorithm, s.encoding, []byte(payload))
+	if err != nil {
+		return err
+	}
+
+	request.Header.Set(s.timestampHeader, timestampValue)
+	request.Header.Set(s.nonceHeader, nonceValue)
+	request.Header.Set(s.signatureHeader, signature)
+
+	return nil
+}
@@ -0,0 +1,76 @@
+package pipeline
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	log "github.com/InjectiveLabs/suplog"
+)
+
+func TestHTTPTaskRequestSigning(t *testing.T) {
+	t.Setenv("TEST_HTTP_SIGN_SECRET", "secret")
+
+	now := time.Unix(1700000000, 0)
+	timeNow = func() time.Time { return now }
+	defer func() { timeNow = time.Now }()
+
+	requests := make(chan *http.Request, 2)
+	bodies := make(chan []byte, 2)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		requests <- r
+		bodies <- body
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer srv.Close()
+
+	spec := Spec{
+		DotDagSource: `
+			req [type=http method=POST url="` + srv.URL + `/api/v1/price?symbol=INJ" requestData=<{"a": 1}>
+				signSecretEnv="TEST_HTTP_SIGN_SECRET" signatureHeader="X-Api-Sign" timestampUnit="s"]
+		`,
+	}
+
+	var nonces []string
+	for i := 0; i < 2; i++ {
+		_, trrs, err := NewRunner(log.DefaultLogger).ExecuteRun(context.Background(), spec, NewVarsFrom(nil), log.DefaultLogger)
+		if err != nil {
+			t.Fatalf("failed to execute run: %v", err)
+		} else if finalResult := trrs.FinalResult(log.DefaultLogger); finalResult.HasFatalErrors() {
+			t.Fatalf("unexpected fatal errors: %v", finalResult.FatalErrors)
+		}
+
+		r, body := <-requests, <-bodies
+		timestamp, nonce := r.Header.Get("X-Timestamp"), r.Header.Get("X-Nonce")
+		if timestamp != "1700000000" {
+			t.Errorf("expected timestamp 1700000000, got %q", timestamp)
+		} else if len(nonce) != 32 {
+			t.Errorf("expected a 16 bytes hex nonce, got %q", nonce)
+		}
+
+		expected, err := hmacSignature("secret", "sha256", "hex", []byte(timestamp+nonce+"POST/api/v1/price?symbol=INJ"+string(body)))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		} else if signature := r.Header.Get("X-Api-Sign"); signature != expected {
+			t.Errorf("expected signature %s, got %s", expected, signature)
+		}
+
+		nonces = append(nonces, nonce)
+	}
+
+	if nonces[0] == nonces[1] {
+		t.Error("expected a new nonce for every request")
+	}
+
+	spec.DotDagSource = `req [type=http url="` + srv.URL + `" signSecretEnv="TEST_HTTP_SIGN_SECRET_MISSING"]`
+	_, trrs, err := NewRunner(log.DefaultLogger).ExecuteRun(context.Background(), spec, NewVarsFrom(nil), log.DefaultLogger)
+	if err != nil {
+		t.Fatalf("failed to execute run: %v", err)
+	} else if !trrs.FinalResult(log.DefaultLogger).HasFatalErrors() {
+		t.Error("expected an error when the secret env variable is not set")
+	}
+}
@@ -0,0 +1,94 @@
+package pipeline
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// runHistory keeps the output of the last completed run of every job, keyed by the job name, so a pipeline
+// can refer to the value it produced on its previous run. It also keeps samples of tasks accumulating a value
+// over a time window. It's best-effort in-memory state, reset on restart.
+type runHistory struct {
+	mu      sync.RWMutex
+	outputs map[string]runOutput
+	samples map[string][]runSample
+}
+
+type runOutput struct {
+	value      interface{}
+	finishedAt time.Time
+}
+
+type runSample struct {
+	value decimal.Decimal
+	at    time.Time
+}
+
+var defaultRunHistory = &runHistory{
+	outputs: make(map[string]runOutput),
+	samples: make(map[string][]runSample),
+}
+
+// record keeps the output of a completed run with a single final result, replacing the previous one of its job.
+func (h *runHistory) record(run *Run) {
+	jobName := run.PipelineSpec.JobName
+	if len(jobName) == 0 || run.State != RunStatusCompleted {
+		return
+	}
+
+	outputs, ok := run.Outputs.Val.([]interface{})
+	if !ok || len(outputs) != 1 {
+		return
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.outputs[jobName] = runOutput{
+		value:      outputs[0],
+		finishedAt: run.FinishedAt.Time,
+	}
+}
+
+// last returns the output of the last completed run of the job, if any.
+func (h *runHistory) last(jobName string) (runOutput, bool) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	output, ok := h.outputs[jobName]
+	return output, ok
+}
+
+// addSample appends a sample to the series of the key, returning the samples covering the window starting at
+// windowStart. Samples are dropped once the next one is not after windowStart, as they no longer cover the window.
+func (h *runHistory) addSample(key string, sample runSample, windowStart time.Time) []runSample {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	samples := append(h.samples[key], sample)
+
+	var expired int
+	for expired < len(samples)-1 && !samples[expired+1].at.After(windowStart) {
+		expired++
+	}
+
+	samples = append([]runSample(nil), samples[expired:]...)
+	h.samples[key] = samples
+
+	return append([]runSample(nil), samples...)
+}
+
+type jobNameCtxKey struct{}
+
+// withJobName passes the job name of the run to its tasks.
+func withJobName(ctx context.Context, jobName string) context.Context {
+	return context.WithValue(ctx, jobNameCtxKey{}, jobName)
+}
+
+func jobNameFrom(ctx context.Context) string {
+	jobName, _ := ctx.Value(jobNameCtxKey{}).(string)
+	return jobName
+}
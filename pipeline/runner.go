@@ -45,6 +45,19 @@ func (err ErrRunPanicked) Error() string {
 	return fmt.Sprintf("goroutine panicked when executing run: %v", err.v)
 }
 
+// ErrInvalidSpec is returned by runs of a spec that fails to parse, so it fails the same way on every run.
+type ErrInvalidSpec struct {
+	Err error
+}
+
+func (err ErrInvalidSpec) Error() string {
+	return fmt.Sprintf("invalid pipeline spec: %v", err.Err)
+}
+
+func (err ErrInvalidSpec) Unwrap() error {
+	return err.Err
+}
+
 func NewRun(spec Spec, vars Vars) Run {
 	return Run{
 		State:          RunStatusRunning,
@@ -67,7 +80,7 @@ func (r *runner) ExecuteRun(
 	pipeline, err := r.initializePipeline(&run)
 
 	if err != nil {
-		return run, nil, err
+		return run, nil, ErrInvalidSpec{err}
 	}
 
 	taskRunResults, err := r.run(ctx, pipeline, &run, vars, l)
@@ -79,6 +92,8 @@ func (r *runner) ExecuteRun(
 		return run, nil, errors.Wrapf(err, "unexpected async run for spec ID %v, tried executing via ExecuteAndInsertFinishedRun", spec.ID)
 	}
 
+	defaultRunHistory.record(&run)
+
 	return run, taskRunResults, nil
 }
 
@@ -220,7 +235,7 @@ func (r *runner) executeTaskRun(ctx context.Context, spec Spec, taskRun *memoryT
 		"attempt":  taskRun.attempts,
 	})
 
-	ctx, cancel := context.WithCancel(context.Background())
+	ctx, cancel := context.WithCancel(withJobName(context.Background(), spec.JobName))
 	defer cancel()
 
 	if taskTimeout, isSet := taskRun.task.TaskTimeout(); isSet && taskTimeout > 0 {
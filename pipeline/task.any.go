@@ -4,16 +4,35 @@ import (
 	"context"
 	"crypto/rand"
 	"math/big"
+	"sort"
 
 	"github.com/pkg/errors"
+	"github.com/shopspring/decimal"
 
 	log "github.com/InjectiveLabs/suplog"
 )
 
-// AnyTask picks a value at random from the set of non-errored inputs.
-// If there are zero non-errored inputs then it returns an error.
+// Strategies of picking the value of the AnyTask.
+const (
+	// AnyStrategyRandom picks a non-errored input at random.
+	AnyStrategyRandom = "random"
+	// AnyStrategyFirst picks the first non-errored input, in input index order.
+	AnyStrategyFirst = "first"
+	// AnyStrategyMedian picks the median of the non-errored inputs, which must be numeric.
+	AnyStrategyMedian = "median"
+	// AnyStrategyLast picks the last non-errored input, in input index order. Inputs carry no timestamps,
+	// so it's not the freshest value, unless inputs are indexed by freshness.
+	AnyStrategyLast = "last"
+)
+
+var ErrUnsupportedAnyStrategy = errors.New("unsupported any strategy")
+
+// AnyTask picks a value from the set of non-errored inputs, at random unless a strategy is set.
+// Random stays the default, as the task picked before strategies were added, so pipelines relying on it
+// to spread picks across sources are unchanged. If there are zero non-errored inputs then it returns an error.
 type AnyTask struct {
 	BaseTask `mapstructure:",squash"`
+	Strategy string `json:"strategy"`
 }
 
 var _ Task = (*AnyTask)(nil)
@@ -22,11 +41,17 @@ func (t *AnyTask) Type() TaskType {
 	return TaskTypeAny
 }
 
-func (t *AnyTask) Run(_ context.Context, _ log.Logger, _ Vars, inputs []Result) (result Result, runInfo RunInfo) {
+func (t *AnyTask) Run(_ context.Context, _ log.Logger, vars Vars, inputs []Result) (result Result, runInfo RunInfo) {
 	if len(inputs) == 0 {
 		return Result{Error: errors.Wrapf(ErrWrongInputCardinality, "AnyTask requires at least 1 input")}, runInfo
 	}
 
+	var strategy StringParam
+	err := errors.Wrap(ResolveParam(&strategy, From(VarExpr(t.Strategy, vars), NonemptyString(t.Strategy), AnyStrategyRandom)), "strategy")
+	if err != nil {
+		return Result{Error: err}, runInfo
+	}
+
 	var answers []interface{}
 
 	for _, input := range inputs {
@@ -41,12 +66,37 @@ func (t *AnyTask) Run(_ context.Context, _ log.Logger, _ Vars, inputs []Result)
 		return Result{Error: errors.Wrapf(ErrBadInput, "There were zero non-errored inputs")}, runInfo
 	}
 
-	nBig, err := rand.Int(rand.Reader, big.NewInt(int64(len(answers))))
-	if err != nil {
-		return Result{Error: errors.Wrapf(err, "Failed to generate random number for picking input")}, retryableRunInfo()
+	switch string(strategy) {
+	case AnyStrategyRandom:
+		nBig, err := rand.Int(rand.Reader, big.NewInt(int64(len(answers))))
+		if err != nil {
+			return Result{Error: errors.Wrapf(err, "Failed to generate random number for picking input")}, retryableRunInfo()
+		}
+		return Result{Value: answers[int(nBig.Int64())]}, runInfo
+	case AnyStrategyFirst:
+		return Result{Value: answers[0]}, runInfo
+	case AnyStrategyLast:
+		return Result{Value: answers[len(answers)-1]}, runInfo
+	case AnyStrategyMedian:
+		var values DecimalSliceParam
+		if err := values.UnmarshalPipelineParam(answers); err != nil {
+			return Result{Error: errors.Wrap(err, "median strategy requires numeric inputs")}, runInfo
+		}
+
+		return Result{Value: medianOf(values)}, runInfo
+	default:
+		return Result{Error: errors.Wrapf(ErrUnsupportedAnyStrategy, "strategy: %s", strategy)}, runInfo
 	}
-	i := int(nBig.Int64())
-	answer := answers[i]
+}
 
-	return Result{Value: answer}, runInfo
+// medianOf sorts the values in place and returns their median, the mean of the middle two for an even count.
+func medianOf(values []decimal.Decimal) decimal.Decimal {
+	sort.Slice(values, func(i, j int) bool {
+		return values[i].LessThan(values[j])
+	})
+	k := len(values) / 2
+	if len(values)%2 == 1 {
+		return values[k]
+	}
+	return values[k].Add(values[k-1]).Div(decimal.NewFromInt(2))
 }
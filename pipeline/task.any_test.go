@@ -0,0 +1,106 @@
+package pipeline
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	log "github.com/InjectiveLabs/suplog"
+	"github.com/shopspring/decimal"
+)
+
+func TestAnyTaskStrategy(t *testing.T) {
+	inputs := []Result{
+		{Value: "3"},
+		{Error: errors.New("source down")},
+		{Value: "1"},
+		{Value: "10"},
+		{Value: "2"},
+	}
+
+	tests := []struct {
+		name     string
+		strategy string
+		inputs   []Result
+		expected string
+		wantErr  bool
+	}{
+		{
+			name:     "First non-errored input",
+			strategy: "first",
+			inputs:   inputs,
+			expected: "3",
+		},
+		{
+			name:     "Last non-errored input",
+			strategy: "last",
+			inputs:   append(inputs, Result{Error: errors.New("source down")}),
+			expected: "2",
+		},
+		{
+			name:     "Median of non-errored inputs",
+			strategy: "median",
+			inputs:   inputs,
+			expected: "2.5",
+		},
+		{
+			name:     "Median of non-numeric inputs",
+			strategy: "median",
+			inputs:   []Result{{Value: "abc"}},
+			wantErr:  true,
+		},
+		{
+			name:     "Unsupported strategy",
+			strategy: "newest",
+			inputs:   inputs,
+			wantErr:  true,
+		},
+		{
+			name:     "No non-errored inputs",
+			strategy: "median",
+			inputs:   []Result{{Error: errors.New("source down")}},
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			task := AnyTask{
+				BaseTask: NewBaseTask(0, "any", nil, nil, 0),
+				Strategy: tt.strategy,
+			}
+
+			result, _ := task.Run(context.Background(), log.DefaultLogger, NewVarsFrom(nil), tt.inputs)
+			if tt.wantErr {
+				if result.Error == nil {
+					t.Fatalf("expected error, got %v", result.Value)
+				}
+				return
+			} else if result.Error != nil {
+				t.Fatalf("unexpected error: %v", result.Error)
+			}
+
+			switch value := result.Value.(type) {
+			case decimal.Decimal:
+				if !value.Equal(decimal.RequireFromString(tt.expected)) {
+					t.Errorf("expected %s, got %s", tt.expected, value)
+				}
+			default:
+				if value != tt.expected {
+					t.Errorf("expected %s, got %v", tt.expected, value)
+				}
+			}
+		})
+	}
+
+	// picks at random from non-errored inputs by default
+	task := AnyTask{BaseTask: NewBaseTask(0, "any", nil, nil, 0)}
+	for i := 0; i < 10; i++ {
+		result, _ := task.Run(context.Background(), log.DefaultLogger, NewVarsFrom(nil), inputs)
+		if result.Error != nil {
+			t.Fatalf("unexpected error: %v", result.Error)
+		} else if result.Value == nil {
+			t.Fatal("expected a non-errored input picked")
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package pipeline
+
+import (
+	"context"
+	"encoding/hex"
+
+	"github.com/pkg/errors"
+	"go.uber.org/multierr"
+
+	log "github.com/InjectiveLabs/suplog"
+)
+
+const (
+	CBORParseModeStandard = "standard"
+	// CBORParseModeDiet decodes a map encoded without its header, as Chainlink requests carry it
+	CBORParseModeDiet = "diet"
+)
+
+// CBORParseTask decodes a CBOR payload and resolves the path in it, with the same semantics as jsonparse.
+// The data is a byte input, or a 0x-prefixed hex string.
+//
+// Return types:
+//
+//	int64
+//	*big.Int
+//	float64
+//	string
+//	[]byte
+//	bool
+//	map[string]interface{}
+//	[]interface{}
+//	nil
+type CBORParseTask struct {
+	BaseTask `mapstructure:",squash"`
+	Path     string `json:"path"`
+	Data     string `json:"data"`
+	Mode     string `json:"mode"`
+	// Lax when disabled will return an error if the path does not exist
+	// Lax when enabled will return nil with no error if the path does not exist
+	Lax string
+}
+
+var _ Task = (*CBORParseTask)(nil)
+
+func (t *CBORParseTask) Type() TaskType {
+	return TaskTypeCBORParse
+}
+
+func (t *CBORParseTask) Run(_ context.Context, _ log.Logger, vars Vars, inputs []Result) (result Result, runInfo RunInfo) {
+	_, err := CheckInputs(inputs, 0, 1, 0)
+	if err != nil {
+		return Result{Error: errors.Wrap(err, "task inputs")}, runInfo
+	}
+
+	var (
+		path JSONPathParam
+		data BytesParam
+		mode StringParam
+		lax  BoolParam
+	)
+	err = multierr.Combine(
+		errors.Wrap(ResolveParam(&path, From(VarExpr(t.Path, vars), t.Path)), "path"),
+		errors.Wrap(ResolveParam(&data, From(VarExpr(t.Data, vars), Input(inputs, 0))), "data"),
+		errors.Wrap(ResolveParam(&mode, From(NonemptyString(t.Mode), CBORParseModeStandard)), "mode"),
+		errors.Wrap(ResolveParam(&lax, From(NonemptyString(t.Lax), false)), "lax"),
+	)
+	if err != nil {
+		return Result{Error: err}, runInfo
+	}
+
+	payload := []byte(data)
+	switch mode {
+	case CBORParseModeStandard:
+	case CBORParseModeDiet:
+		// wrap the map entries into an indefinite length map
+		payload = append(append([]byte{0xbf}, payload...), cborBreak)
+	default:
+		return Result{Error: errors.Wrapf(ErrBadInput, "unknown mode %q, expected %s or %s", mode, CBORParseModeStandard, CBORParseModeDiet)}, runInfo
+	}
+
+	decoded, err := decodeCBOR(payload)
+	if err != nil {
+		return Result{Error: errors.Wrap(ErrBadInput, err.Error())}, runInfo
+	}
+
+	decoded, err = resolveKeypath(decoded, path, bool(lax), "0x"+hex.EncodeToString(data))
+	if err != nil {
+		return Result{Error: err}, runInfo
+	}
+	return Result{Value: decoded}, runInfo
+}
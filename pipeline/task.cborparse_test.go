@@ -0,0 +1,153 @@
+package pipeline
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	log "github.com/InjectiveLabs/suplog"
+)
+
+func TestCBORParseTask(t *testing.T) {
+	// {"price": 64123, "symbol": "BTC", "data": [1.5, -10], "big": 2(h'010000000000000000')}
+	const entries = "657072696365" + "19fa7b" +
+		"6673796d626f6c" + "63425443" +
+		"6464617461" + "82f93e0029" +
+		"63626967" + "c249010000000000000000"
+
+	tests := []struct {
+		name     string
+		data     string
+		path     string
+		mode     string
+		lax      string
+		expected interface{}
+		wantErr  bool
+	}{
+		{
+			name:     "Integer in map",
+			data:     "0xa4" + entries,
+			path:     "price",
+			expected: int64(64123),
+		},
+		{
+			name:     "Text in map",
+			data:     "0xa4" + entries,
+			path:     "symbol",
+			expected: "BTC",
+		},
+		{
+			name:     "Half float in array",
+			data:     "0xa4" + entries,
+			path:     "data,0",
+			expected: 1.5,
+		},
+		{
+			name:     "Negative integer by negative index",
+			data:     "0xa4" + entries,
+			path:     "data,-1",
+			expected: int64(-10),
+		},
+		{
+			name:     "Bignum",
+			data:     "0xa4" + entries,
+			path:     "big",
+			expected: new(big.Int).Lsh(big.NewInt(1), 64),
+		},
+		{
+			name:     "Diet mode map",
+			data:     "0x" + entries,
+			path:     "symbol",
+			mode:     CBORParseModeDiet,
+			expected: "BTC",
+		},
+		{
+			name:     "Double at the top level",
+			data:     "0xfb3ff199999999999a",
+			expected: 1.1,
+		},
+		{
+			name:    "Invalid hex data",
+			data:    "0xa4zz",
+			wantErr: true,
+		},
+		{
+			name:     "Missing key in lax mode",
+			data:     "0xa4" + entries,
+			path:     "volume",
+			lax:      "true",
+			expected: nil,
+		},
+		{
+			name:    "Missing key",
+			data:    "0xa4" + entries,
+			path:    "volume",
+			wantErr: true,
+		},
+		{
+			name:    "Truncated payload",
+			data:    "0xa4" + entries[:20],
+			wantErr: true,
+		},
+		{
+			name:    "Trailing bytes",
+			data:    "0x0101",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			task := CBORParseTask{
+				BaseTask: NewBaseTask(0, "cbor", nil, nil, 0),
+				Path:     tt.path,
+				Mode:     tt.mode,
+				Lax:      tt.lax,
+			}
+
+			result, _ := task.Run(context.Background(), log.DefaultLogger, NewVarsFrom(nil), []Result{{Value: tt.data}})
+			if tt.wantErr {
+				if result.Error == nil {
+					t.Errorf("expected error, got %v", result.Value)
+				}
+				return
+			}
+
+			if result.Error != nil {
+				t.Fatalf("unexpected error: %v", result.Error)
+			}
+
+			switch expected := tt.expected.(type) {
+			case *big.Int:
+				if value, ok := result.Value.(*big.Int); !ok || value.Cmp(expected) != 0 {
+					t.Errorf("expected %v, got %v (%T)", expected, result.Value, result.Value)
+				}
+			default:
+				if result.Value != tt.expected {
+					t.Errorf("expected %v (%T), got %v (%T)", tt.expected, tt.expected, result.Value, result.Value)
+				}
+			}
+		})
+	}
+}
+
+func TestDecodeCBORIndefiniteLength(t *testing.T) {
+	// ["hi!", {"a": true}] with an indefinite length array, text and map
+	decoded, err := decodeCBOR([]byte{0x9f, 0x7f, 0x62, 'h', 'i', 0x61, '!', 0xff, 0xbf, 0x61, 'a', 0xf5, 0xff, 0xff})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	items, ok := decoded.([]interface{})
+	if !ok || len(items) != 2 {
+		t.Fatalf("expected array of 2 items, got %v", decoded)
+	}
+
+	if items[0] != "hi!" {
+		t.Errorf("expected hi!, got %v", items[0])
+	}
+
+	if m, ok := items[1].(map[string]interface{}); !ok || m["a"] != true {
+		t.Errorf("expected {a: true}, got %v", items[1])
+	}
+}
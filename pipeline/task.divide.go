@@ -17,6 +17,9 @@ type DivideTask struct {
 	Input     string `json:"input"`
 	Divisor   string `json:"divisor"`
 	Precision string `json:"precision"`
+	// AllowZero when enabled makes division by zero yield Default instead of an error
+	AllowZero string `json:"allowZero"`
+	Default   string `json:"default"`
 }
 
 var _ Task = (*DivideTask)(nil)
@@ -35,16 +38,32 @@ func (t *DivideTask) Run(_ context.Context, _ log.Logger, vars Vars, inputs []Re
 		a              DecimalParam
 		b              DecimalParam
 		maybePrecision MaybeInt32Param
+		allowZero      BoolParam
 	)
 	err = multierr.Combine(
 		errors.Wrap(ResolveParam(&a, From(VarExpr(t.Input, vars), Input(inputs, 0))), "input"),
 		errors.Wrap(ResolveParam(&b, From(VarExpr(t.Divisor, vars), NonemptyString(t.Divisor))), "divisor"),
 		errors.Wrap(ResolveParam(&maybePrecision, From(VarExpr(t.Precision, vars), t.Precision)), "precision"),
+		errors.Wrap(ResolveParam(&allowZero, From(NonemptyString(t.AllowZero), false)), "allowZero"),
 	)
 	if err != nil {
 		return Result{Error: err}, runInfo
 	}
 
+	if b.Decimal().IsZero() {
+		if !bool(allowZero) {
+			return Result{Error: errors.Wrap(ErrBadInput, "divisor is zero")}, runInfo
+		}
+
+		var defaultValue DecimalParam
+		err = errors.Wrap(ResolveParam(&defaultValue, From(VarExpr(t.Default, vars), NonemptyString(t.Default), 0)), "default")
+		if err != nil {
+			return Result{Error: err}, runInfo
+		}
+
+		return Result{Value: defaultValue.Decimal()}, runInfo
+	}
+
 	if precision, isSet := maybePrecision.Int32(); isSet {
 		return Result{Value: a.Decimal().DivRound(b.Decimal(), precision)}, runInfo
 	}
@@ -0,0 +1,77 @@
+package pipeline
+
+import (
+	"context"
+	"testing"
+
+	log "github.com/InjectiveLabs/suplog"
+	"github.com/shopspring/decimal"
+)
+
+func TestDivideTask(t *testing.T) {
+	tests := []struct {
+		name         string
+		input        string
+		divisor      string
+		allowZero    string
+		defaultValue string
+		expected     string
+		wantErr      bool
+	}{
+		{
+			name:     "Divide by non-zero divisor",
+			input:    "10",
+			divisor:  "4",
+			expected: "2.5",
+		},
+		{
+			name:    "Reject zero divisor",
+			input:   "10",
+			divisor: "0",
+			wantErr: true,
+		},
+		{
+			name:      "Zero divisor yields zero when allowed",
+			input:     "10",
+			divisor:   "0",
+			allowZero: "true",
+			expected:  "0",
+		},
+		{
+			name:         "Zero divisor yields configured default when allowed",
+			input:        "10",
+			divisor:      "0.000",
+			allowZero:    "true",
+			defaultValue: "1.5",
+			expected:     "1.5",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			task := DivideTask{
+				BaseTask:  NewBaseTask(0, "divide", nil, nil, 0),
+				Divisor:   tt.divisor,
+				AllowZero: tt.allowZero,
+				Default:   tt.defaultValue,
+			}
+
+			result, _ := task.Run(context.Background(), log.DefaultLogger, NewVarsFrom(nil), []Result{{Value: tt.input}})
+			if tt.wantErr {
+				if result.Error == nil {
+					t.Errorf("DivideTask(%s / %s) expected error, got %v", tt.input, tt.divisor, result.Value)
+				}
+				return
+			}
+
+			if result.Error != nil {
+				t.Fatalf("DivideTask(%s / %s) unexpected error: %v", tt.input, tt.divisor, result.Error)
+			}
+
+			expected := decimal.RequireFromString(tt.expected)
+			if value := result.Value.(decimal.Decimal); !value.Equal(expected) {
+				t.Errorf("DivideTask(%s / %s) = %s; want %s", tt.input, tt.divisor, value, expected)
+			}
+		})
+	}
+}
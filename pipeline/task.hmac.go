@@ -0,0 +1,101 @@
+package pipeline
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/hex"
+	"hash"
+	"os"
+	"strings"
+
+	"github.com/pkg/errors"
+	"go.uber.org/multierr"
+
+	log "github.com/InjectiveLabs/suplog"
+)
+
+// HMACTask signs the input string with a secret key, as required by authenticated
+// exchange APIs. The secret is read from the environment variable named by secretEnv,
+// so it never has to be committed into the feed config.
+//
+// Return types:
+//
+//	string
+type HMACTask struct {
+	BaseTask  `mapstructure:",squash"`
+	Input     string `json:"input"`
+	SecretEnv string `json:"secretEnv"`
+	Algorithm string `json:"algorithm"`
+	Encoding  string `json:"encoding"`
+}
+
+var _ Task = (*HMACTask)(nil)
+
+func (t *HMACTask) Type() TaskType {
+	return TaskTypeHMAC
+}
+
+func (t *HMACTask) Run(_ context.Context, _ log.Logger, vars Vars, inputs []Result) (result Result, runInfo RunInfo) {
+	_, err := CheckInputs(inputs, 0, 1, 0)
+	if err != nil {
+		return Result{Error: errors.Wrap(err, "task inputs")}, runInfo
+	}
+
+	var (
+		input     StringParam
+		secretEnv StringParam
+		algorithm StringParam
+		encoding  StringParam
+	)
+	err = multierr.Combine(
+		errors.Wrap(ResolveParam(&input, From(VarExpr(t.Input, vars), Input(inputs, 0))), "input"),
+		errors.Wrap(ResolveParam(&secretEnv, From(NonemptyString(t.SecretEnv))), "secretEnv"),
+		errors.Wrap(ResolveParam(&algorithm, From(NonemptyString(t.Algorithm), "sha256")), "algorithm"),
+		errors.Wrap(ResolveParam(&encoding, From(NonemptyString(t.Encoding), "hex")), "encoding"),
+	)
+	if err != nil {
+		return Result{Error: err}, runInfo
+	}
+
+	secret, ok := os.LookupEnv(string(secretEnv))
+	if !ok || len(secret) == 0 {
+		return Result{Error: errors.Wrapf(ErrParameterEmpty, "secret env variable %s is not set", secretEnv)}, runInfo
+	}
+
+	signature, err := hmacSignature(secret, string(algorithm), string(encoding), []byte(input))
+	if err != nil {
+		return Result{Error: err}, runInfo
+	}
+
+	return Result{Value: signature}, runInfo
+}
+
+// hmacSignature signs the payload with the secret, algorithm is "sha256" or "sha512",
+// the signature is encoded as "hex" or "base64".
+func hmacSignature(secret, algorithm, encoding string, payload []byte) (string, error) {
+	var hashFn func() hash.Hash
+	switch strings.ToLower(algorithm) {
+	case "sha256":
+		hashFn = sha256.New
+	case "sha512":
+		hashFn = sha512.New
+	default:
+		return "", errors.Wrapf(ErrBadInput, "unsupported algorithm: %s", algorithm)
+	}
+
+	mac := hmac.New(hashFn, []byte(secret))
+	_, _ = mac.Write(payload)
+	signature := mac.Sum(nil)
+
+	switch strings.ToLower(encoding) {
+	case "hex":
+		return hex.EncodeToString(signature), nil
+	case "base64":
+		return base64.StdEncoding.EncodeToString(signature), nil
+	default:
+		return "", errors.Wrapf(ErrBadInput, "unsupported encoding: %s", encoding)
+	}
+}
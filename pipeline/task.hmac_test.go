@@ -0,0 +1,97 @@
+package pipeline
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	log "github.com/InjectiveLabs/suplog"
+)
+
+func TestHMACTask(t *testing.T) {
+	t.Setenv("TEST_HMAC_SECRET", "key")
+
+	const message = "The quick brown fox jumps over the lazy dog"
+
+	tests := []struct {
+		name      string
+		secretEnv string
+		algorithm string
+		encoding  string
+		expected  string
+		wantErr   error
+	}{
+		{
+			name:      "Defaults to sha256 and hex",
+			secretEnv: "TEST_HMAC_SECRET",
+			expected:  "f7bc83f430538424b13298e6aa6fb143ef4d59a14946175997479dbc2d1a3cd8",
+		},
+		{
+			name:      "sha256 base64",
+			secretEnv: "TEST_HMAC_SECRET",
+			algorithm: "sha256",
+			encoding:  "base64",
+			expected:  "97yD9DBThCSxMpjmqm+xQ+9NWaFJRhdZl0edvC0aPNg=",
+		},
+		{
+			name:      "sha512 hex",
+			secretEnv: "TEST_HMAC_SECRET",
+			algorithm: "sha512",
+			encoding:  "hex",
+			expected:  "b42af09057bac1e2d41708e48a902e09b5ff7f12ab428a4fe86653c73dd248fb82f948a549f7b791a5b41915ee4d1ec3935357e4e2317250d0372afa2ebeeb3a",
+		},
+		{
+			name:      "sha512 base64, case insensitive",
+			secretEnv: "TEST_HMAC_SECRET",
+			algorithm: "SHA512",
+			encoding:  "Base64",
+			expected:  "tCrwkFe6weLUFwjkipAuCbX/fxKrQopP6GZTxz3SSPuC+UilSfe3kaW0GRXuTR7Dk1NX5OIxclDQNyr6Lr7rOg==",
+		},
+		{
+			name:      "Unknown algorithm",
+			secretEnv: "TEST_HMAC_SECRET",
+			algorithm: "md5",
+			wantErr:   ErrBadInput,
+		},
+		{
+			name:      "Unknown encoding",
+			secretEnv: "TEST_HMAC_SECRET",
+			encoding:  "base32",
+			wantErr:   ErrBadInput,
+		},
+		{
+			name:      "Secret env variable not set",
+			secretEnv: "TEST_HMAC_SECRET_MISSING",
+			wantErr:   ErrParameterEmpty,
+		},
+		{
+			name:    "Secret env variable not configured",
+			wantErr: ErrParameterEmpty,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			task := HMACTask{
+				BaseTask:  NewBaseTask(0, "hmac", nil, nil, 0),
+				SecretEnv: tt.secretEnv,
+				Algorithm: tt.algorithm,
+				Encoding:  tt.encoding,
+			}
+
+			result, _ := task.Run(context.Background(), log.DefaultLogger, NewVarsFrom(nil), []Result{{Value: message}})
+			if tt.wantErr != nil {
+				if !errors.Is(result.Error, tt.wantErr) {
+					t.Errorf("expected error %v, got %v (%v)", tt.wantErr, result.Error, result.Value)
+				}
+				return
+			}
+
+			if result.Error != nil {
+				t.Fatalf("unexpected error: %v", result.Error)
+			} else if result.Value != tt.expected {
+				t.Errorf("expected signature %s, got %v", tt.expected, result.Value)
+			}
+		})
+	}
+}
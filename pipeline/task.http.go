@@ -3,6 +3,8 @@ package pipeline
 import (
 	"context"
 	"encoding/json"
+	"math"
+	"os"
 
 	"go.uber.org/multierr"
 
@@ -10,6 +12,13 @@ import (
 	"github.com/pkg/errors"
 )
 
+// HTTPTask sends a request with an optional JSON body. Requests are signed for authenticated exchange APIs
+// if signSecretEnv names an env variable holding the secret: timestamp and nonce headers are set, and the
+// HMAC of the canonical request (see httpRequestSigner) is set as the signature header, on every request.
+//
+// Request and response body sizes are reported by the HTTPMetricsReporter, by the dotID. Responses larger than
+// maxResponseBytes, DefaultHTTPMaxResponseBytes if not set, are errors.
+//
 // Return types:
 //
 //	string
@@ -19,6 +28,16 @@ type HTTPTask struct {
 	URL         string
 	RequestData string `json:"requestData"`
 	HeaderMap   string `json:"headerMap"`
+
+	SignSecretEnv   string `json:"signSecretEnv"`
+	SignAlgorithm   string `json:"signAlgorithm"`
+	SignEncoding    string `json:"signEncoding"`
+	SignatureHeader string `json:"signatureHeader"`
+	TimestampHeader string `json:"timestampHeader"`
+	TimestampUnit   string `json:"timestampUnit"`
+	NonceHeader     string `json:"nonceHeader"`
+
+	MaxResponseBytes string `json:"maxResponseBytes"`
 }
 
 var _ Task = (*HTTPTask)(nil)
@@ -34,21 +53,28 @@ func (t *HTTPTask) Run(ctx context.Context, lggr log.Logger, vars Vars, inputs [
 	}
 
 	var (
-		method      StringParam
-		url         URLParam
-		requestData MapParam
-		headerMap   MapParam
+		method           StringParam
+		url              URLParam
+		requestData      MapParam
+		headerMap        MapParam
+		maxResponseBytes MaybeUint64Param
 	)
 	err = multierr.Combine(
 		errors.Wrap(ResolveParam(&method, From(NonemptyString(t.Method), "GET")), "method"),
 		errors.Wrap(ResolveParam(&url, From(VarExpr(t.URL, vars), NonemptyString(t.URL))), "url"),
 		errors.Wrap(ResolveParam(&requestData, From(VarExpr(t.RequestData, vars), JSONWithVarExprs(t.RequestData, vars, false), nil)), "requestData"),
 		errors.Wrap(ResolveParam(&headerMap, From(VarExpr(t.HeaderMap, vars), JSONWithVarExprs(t.HeaderMap, vars, false), nil)), "headerMap"),
+		errors.Wrap(ResolveParam(&maxResponseBytes, From(VarExpr(t.MaxResponseBytes, vars), t.MaxResponseBytes)), "maxResponseBytes"),
 	)
 	if err != nil {
 		return Result{Error: err}, runInfo
 	}
 
+	signer, err := t.requestSigner()
+	if err != nil {
+		return Result{Error: err}, runInfo
+	}
+
 	requestDataJSON, err := json.Marshal(requestData)
 	if err != nil {
 		return Result{Error: err}, runInfo
@@ -66,14 +92,30 @@ func (t *HTTPTask) Run(ctx context.Context, lggr log.Logger, vars Vars, inputs [
 		"method", method,
 	)
 
+	limit, _ := maxResponseBytes.Uint64()
+	if limit > math.MaxInt64 {
+		return Result{Error: errors.Wrap(ErrBadInput, "maxResponseBytes overflows int64")}, runInfo
+	}
+
+	var requestBytes int
+	if requestData != nil {
+		requestBytes = len(requestDataJSON)
+	}
+	httpMetrics().RequestBodySize(jobNameFrom(ctx), t.DotID(), requestBytes)
+
 	requestCtx, cancel := httpRequestCtx(ctx, t)
 	defer cancel()
 
-	responseBytes, statusCode, _, elapsed, err := makeHTTPRequest(requestCtx, lggr, method, url, requestData, headerMap)
+	responseBytes, statusCode, _, elapsed, err := makeHTTPRequest(requestCtx, lggr, method, url, requestData, headerMap, signer, int64(limit))
+	if errors.Is(err, ErrResponseTooLarge) {
+		httpMetrics().ResponseTooLarge(jobNameFrom(ctx), t.DotID())
+	}
 	if err != nil {
 		return Result{Error: err}, RunInfo{IsRetryable: isRetryableHTTPError(statusCode, err)}
 	}
 
+	httpMetrics().ResponseBodySize(jobNameFrom(ctx), t.DotID(), len(responseBytes))
+
 	_ = elapsed
 
 	lggr.Debugln("HTTP task got response",
@@ -88,3 +130,45 @@ func (t *HTTPTask) Run(ctx context.Context, lggr log.Logger, vars Vars, inputs [
 	// value instead.
 	return Result{Value: string(responseBytes)}, runInfo
 }
+
+// requestSigner returns the signer of requests, or nil if signing is not configured.
+func (t *HTTPTask) requestSigner() (*httpRequestSigner, error) {
+	if len(t.SignSecretEnv) == 0 {
+		return nil, nil
+	}
+
+	var (
+		algorithm       StringParam
+		encoding        StringParam
+		signatureHeader StringParam
+		timestampHeader StringParam
+		timestampUnit   StringParam
+		nonceHeader     StringParam
+	)
+	err := multierr.Combine(
+		errors.Wrap(ResolveParam(&algorithm, From(NonemptyString(t.SignAlgorithm), "sha256")), "signAlgorithm"),
+		errors.Wrap(ResolveParam(&encoding, From(NonemptyString(t.SignEncoding), "hex")), "signEncoding"),
+		errors.Wrap(ResolveParam(&signatureHeader, From(NonemptyString(t.SignatureHeader), defaultSignatureHeader)), "signatureHeader"),
+		errors.Wrap(ResolveParam(&timestampHeader, From(NonemptyString(t.TimestampHeader), defaultTimestampHeader)), "timestampHeader"),
+		errors.Wrap(ResolveParam(&timestampUnit, From(NonemptyString(t.TimestampUnit), "ms")), "timestampUnit"),
+		errors.Wrap(ResolveParam(&nonceHeader, From(NonemptyString(t.NonceHeader), defaultNonceHeader)), "nonceHeader"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	secret, ok := os.LookupEnv(t.SignSecretEnv)
+	if !ok || len(secret) == 0 {
+		return nil, errors.Wrapf(ErrParameterEmpty, "secret env variable %s is not set", t.SignSecretEnv)
+	}
+
+	return &httpRequestSigner{
+		secret:          secret,
+		algorithm:       string(algorithm),
+		encoding:        string(encoding),
+		signatureHeader: string(signatureHeader),
+		timestampHeader: string(timestampHeader),
+		timestampUnit:   string(timestampUnit),
+		nonceHeader:     string(nonceHeader),
+	}, nil
+}
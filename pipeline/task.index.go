@@ -0,0 +1,69 @@
+package pipeline
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"go.uber.org/multierr"
+
+	log "github.com/InjectiveLabs/suplog"
+)
+
+// IndexTask picks a single element from an array input. Negative indices
+// are counted from the end of the array, so index=-1 yields the last element.
+//
+// Return types:
+//
+//	any type that the array contains
+type IndexTask struct {
+	BaseTask `mapstructure:",squash"`
+	Input    string `json:"input"`
+	Index    string `json:"index"`
+}
+
+var _ Task = (*IndexTask)(nil)
+
+func (t *IndexTask) Type() TaskType {
+	return TaskTypeIndex
+}
+
+func (t *IndexTask) Run(_ context.Context, _ log.Logger, vars Vars, inputs []Result) (result Result, runInfo RunInfo) {
+	_, err := CheckInputs(inputs, 0, 1, 0)
+	if err != nil {
+		return Result{Error: errors.Wrap(err, "task inputs")}, runInfo
+	}
+
+	var (
+		input      SliceParam
+		maybeIndex MaybeInt32Param
+	)
+	inputErr := ResolveParam(&input, From(VarExpr(t.Input, vars), Input(inputs, 0)))
+	if inputErr != nil && !errors.Is(inputErr, ErrBadInput) {
+		// e.g. a string input that isn't a JSON array
+		inputErr = errors.Wrapf(ErrBadInput, "expected array: %v", inputErr)
+	}
+
+	err = multierr.Combine(
+		errors.Wrap(inputErr, "input"),
+		errors.Wrap(ResolveParam(&maybeIndex, From(VarExpr(t.Index, vars), NonemptyString(t.Index))), "index"),
+	)
+	if err != nil {
+		return Result{Error: err}, runInfo
+	}
+
+	index, isSet := maybeIndex.Int32()
+	if !isSet {
+		return Result{Error: errors.Wrap(ErrParameterEmpty, "index")}, runInfo
+	}
+
+	i := int(index)
+	if i < 0 {
+		i = len(input) + i
+	}
+
+	if i < 0 || i >= len(input) {
+		return Result{Error: errors.Wrapf(ErrBadInput, "index %d out of range (length %d)", index, len(input))}, runInfo
+	}
+
+	return Result{Value: input[i]}, runInfo
+}
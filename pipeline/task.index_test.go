@@ -0,0 +1,110 @@
+package pipeline
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	log "github.com/InjectiveLabs/suplog"
+)
+
+func TestIndexTask(t *testing.T) {
+	array := []interface{}{"a", "b", "c"}
+
+	tests := []struct {
+		name     string
+		input    interface{}
+		index    string
+		expected interface{}
+		wantErr  error
+	}{
+		{
+			name:     "First element",
+			input:    array,
+			index:    "0",
+			expected: "a",
+		},
+		{
+			name:     "Last element",
+			input:    array,
+			index:    "2",
+			expected: "c",
+		},
+		{
+			name:     "Negative index counts from the end",
+			input:    array,
+			index:    "-1",
+			expected: "c",
+		},
+		{
+			name:     "Negative index of the first element",
+			input:    array,
+			index:    "-3",
+			expected: "a",
+		},
+		{
+			name:     "JSON array input",
+			input:    `[10, 20, 30]`,
+			index:    "1",
+			expected: float64(20),
+		},
+		{
+			name:    "Index out of range",
+			input:   array,
+			index:   "3",
+			wantErr: ErrBadInput,
+		},
+		{
+			name:    "Negative index out of range",
+			input:   array,
+			index:   "-4",
+			wantErr: ErrBadInput,
+		},
+		{
+			name:    "Empty array",
+			input:   []interface{}{},
+			index:   "0",
+			wantErr: ErrBadInput,
+		},
+		{
+			name:    "Map input",
+			input:   map[string]interface{}{"a": 1},
+			index:   "0",
+			wantErr: ErrBadInput,
+		},
+		{
+			name:    "Non-array string input",
+			input:   "abc",
+			index:   "0",
+			wantErr: ErrBadInput,
+		},
+		{
+			name:    "Missing index",
+			input:   array,
+			wantErr: ErrParameterEmpty,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			task := IndexTask{
+				BaseTask: NewBaseTask(0, "index", nil, nil, 0),
+				Index:    tt.index,
+			}
+
+			result, _ := task.Run(context.Background(), log.DefaultLogger, NewVarsFrom(nil), []Result{{Value: tt.input}})
+			if tt.wantErr != nil {
+				if !errors.Is(result.Error, tt.wantErr) {
+					t.Errorf("IndexTask(%v, %s) expected error %v, got %v (%v)", tt.input, tt.index, tt.wantErr, result.Error, result.Value)
+				}
+				return
+			}
+
+			if result.Error != nil {
+				t.Fatalf("IndexTask(%v, %s) unexpected error: %v", tt.input, tt.index, result.Error)
+			} else if result.Value != tt.expected {
+				t.Errorf("IndexTask(%v, %s) = %v; want %v", tt.input, tt.index, result.Value, tt.expected)
+			}
+		})
+	}
+}
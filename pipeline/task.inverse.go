@@ -0,0 +1,74 @@
+package pipeline
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"github.com/shopspring/decimal"
+	"go.uber.org/multierr"
+
+	log "github.com/InjectiveLabs/suplog"
+)
+
+// InverseTask returns 1 / input, for feeds quoted the opposite way (e.g. USD/BTC instead of BTC/USD).
+//
+// Return types:
+//
+//	*decimal.Decimal
+type InverseTask struct {
+	BaseTask  `mapstructure:",squash"`
+	Input     string `json:"input"`
+	Precision string `json:"precision"`
+	// AllowZero when enabled makes a zero input yield Default instead of an error
+	AllowZero string `json:"allowZero"`
+	Default   string `json:"default"`
+}
+
+var _ Task = (*InverseTask)(nil)
+
+func (t *InverseTask) Type() TaskType {
+	return TaskTypeInverse
+}
+
+func (t *InverseTask) Run(_ context.Context, _ log.Logger, vars Vars, inputs []Result) (result Result, runInfo RunInfo) {
+	_, err := CheckInputs(inputs, -1, -1, 0)
+	if err != nil {
+		return Result{Error: errors.Wrap(err, "task inputs")}, runInfo
+	}
+
+	var (
+		a              DecimalParam
+		maybePrecision MaybeInt32Param
+		allowZero      BoolParam
+	)
+	err = multierr.Combine(
+		errors.Wrap(ResolveParam(&a, From(VarExpr(t.Input, vars), Input(inputs, 0))), "input"),
+		errors.Wrap(ResolveParam(&maybePrecision, From(VarExpr(t.Precision, vars), t.Precision)), "precision"),
+		errors.Wrap(ResolveParam(&allowZero, From(NonemptyString(t.AllowZero), false)), "allowZero"),
+	)
+	if err != nil {
+		return Result{Error: err}, runInfo
+	}
+
+	if a.Decimal().IsZero() {
+		if !bool(allowZero) {
+			return Result{Error: errors.Wrap(ErrBadInput, "input is zero")}, runInfo
+		}
+
+		var defaultValue DecimalParam
+		err = errors.Wrap(ResolveParam(&defaultValue, From(VarExpr(t.Default, vars), NonemptyString(t.Default), 0)), "default")
+		if err != nil {
+			return Result{Error: err}, runInfo
+		}
+
+		return Result{Value: defaultValue.Decimal()}, runInfo
+	}
+
+	one := decimal.NewFromInt(1)
+	if precision, isSet := maybePrecision.Int32(); isSet {
+		return Result{Value: one.DivRound(a.Decimal(), precision)}, runInfo
+	}
+	// Note that decimal library defaults to rounding to 16 precision
+	// https://github.com/shopspring/decimal/blob/2568a29459476f824f35433dfbef158d6ad8618c/decimal.go#L44
+	return Result{Value: one.Div(a.Decimal())}, runInfo
+}
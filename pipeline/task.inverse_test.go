@@ -0,0 +1,78 @@
+package pipeline
+
+import (
+	"context"
+	"testing"
+
+	log "github.com/InjectiveLabs/suplog"
+	"github.com/shopspring/decimal"
+)
+
+func TestInverseTask(t *testing.T) {
+	tests := []struct {
+		name         string
+		input        string
+		precision    string
+		allowZero    string
+		defaultValue string
+		expected     string
+		wantErr      bool
+	}{
+		{
+			name:     "Inverse of non-zero input",
+			input:    "4",
+			expected: "0.25",
+		},
+		{
+			name:      "Inverse rounded to precision",
+			input:     "3",
+			precision: "4",
+			expected:  "0.3333",
+		},
+		{
+			name:     "Inverse of negative input",
+			input:    "-0.5",
+			expected: "-2",
+		},
+		{
+			name:    "Reject zero input",
+			input:   "0",
+			wantErr: true,
+		},
+		{
+			name:         "Zero input yields configured default when allowed",
+			input:        "0.00",
+			allowZero:    "true",
+			defaultValue: "1.5",
+			expected:     "1.5",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			task := InverseTask{
+				BaseTask:  NewBaseTask(0, "inverse", nil, nil, 0),
+				Precision: tt.precision,
+				AllowZero: tt.allowZero,
+				Default:   tt.defaultValue,
+			}
+
+			result, _ := task.Run(context.Background(), log.DefaultLogger, NewVarsFrom(nil), []Result{{Value: tt.input}})
+			if tt.wantErr {
+				if result.Error == nil {
+					t.Errorf("InverseTask(%s) expected error, got %v", tt.input, result.Value)
+				}
+				return
+			}
+
+			if result.Error != nil {
+				t.Fatalf("InverseTask(%s) unexpected error: %v", tt.input, result.Error)
+			}
+
+			expected := decimal.RequireFromString(tt.expected)
+			if value := result.Value.(decimal.Decimal); !value.Equal(expected) {
+				t.Errorf("InverseTask(%s) = %s; want %s", tt.input, value, expected)
+			}
+		})
+	}
+}
@@ -61,28 +61,39 @@ func (t *JSONParseTask) Run(_ context.Context, _ log.Logger, vars Vars, inputs [
 		return Result{Error: err}, runInfo
 	}
 
+	decoded, err = resolveKeypath(decoded, path, bool(lax), string(data))
+	if err != nil {
+		return Result{Error: err}, runInfo
+	}
+	return Result{Value: decoded}, runInfo
+}
+
+// resolveKeypath walks the decoded JSON-like value along the path, resolving map keys and array indexes.
+// If lax is enabled, a missing key or index resolves to nil instead of an error. The data is only used
+// in error messages.
+func resolveKeypath(decoded interface{}, path []string, lax bool, data string) (interface{}, error) {
 	for _, part := range path {
 		switch d := decoded.(type) {
 		case map[string]interface{}:
 			var exists bool
 			decoded, exists = d[part]
-			if !exists && bool(lax) {
+			if !exists && lax {
 				decoded = nil
 				break
 			} else if !exists {
-				return Result{Error: errors.Wrapf(ErrKeypathNotFound, `could not resolve path ["%v"] in %s`, strings.Join(path, `","`), data)}, runInfo
+				return nil, errors.Wrapf(ErrKeypathNotFound, `could not resolve path ["%v"] in %s`, strings.Join(path, `","`), data)
 			}
 
 		case []interface{}:
 			bigindex, ok := big.NewInt(0).SetString(part, 10)
 			if !ok {
-				return Result{Error: errors.Wrapf(ErrKeypathNotFound, "JSONParse task error: %v is not a valid array index", part)}, runInfo
+				return nil, errors.Wrapf(ErrKeypathNotFound, "JSONParse task error: %v is not a valid array index", part)
 			} else if !bigindex.IsInt64() {
-				if bool(lax) {
+				if lax {
 					decoded = nil
 					break
 				}
-				return Result{Error: errors.Wrapf(ErrKeypathNotFound, `could not resolve path ["%v"] in %s`, strings.Join(path, `","`), data)}, runInfo
+				return nil, errors.Wrapf(ErrKeypathNotFound, `could not resolve path ["%v"] in %s`, strings.Join(path, `","`), data)
 			}
 			index := int(bigindex.Int64())
 			if index < 0 {
@@ -90,17 +101,17 @@ func (t *JSONParseTask) Run(_ context.Context, _ log.Logger, vars Vars, inputs [
 			}
 
 			exists := index >= 0 && index < len(d)
-			if !exists && bool(lax) {
+			if !exists && lax {
 				decoded = nil
 				break
 			} else if !exists {
-				return Result{Error: errors.Wrapf(ErrKeypathNotFound, `could not resolve path ["%v"] in %s`, strings.Join(path, `","`), data)}, runInfo
+				return nil, errors.Wrapf(ErrKeypathNotFound, `could not resolve path ["%v"] in %s`, strings.Join(path, `","`), data)
 			}
 			decoded = d[index]
 
 		default:
-			return Result{Error: errors.Wrapf(ErrKeypathNotFound, `could not resolve path ["%v"] in %s`, strings.Join(path, `","`), data)}, runInfo
+			return nil, errors.Wrapf(ErrKeypathNotFound, `could not resolve path ["%v"] in %s`, strings.Join(path, `","`), data)
 		}
 	}
-	return Result{Value: decoded}, runInfo
+	return decoded, nil
 }
@@ -0,0 +1,54 @@
+package pipeline
+
+import (
+	"context"
+	"unicode/utf8"
+
+	"github.com/pkg/errors"
+	"github.com/shopspring/decimal"
+	"go.uber.org/multierr"
+
+	log "github.com/InjectiveLabs/suplog"
+)
+
+// LengthTask returns the number of elements in an array input,
+// or the number of characters in a string input.
+//
+// Return types:
+//
+//	*decimal.Decimal
+type LengthTask struct {
+	BaseTask `mapstructure:",squash"`
+	Input    string `json:"input"`
+}
+
+var _ Task = (*LengthTask)(nil)
+
+func (t *LengthTask) Type() TaskType {
+	return TaskTypeLength
+}
+
+func (t *LengthTask) Run(_ context.Context, _ log.Logger, vars Vars, inputs []Result) (result Result, runInfo RunInfo) {
+	_, err := CheckInputs(inputs, 0, 1, 0)
+	if err != nil {
+		return Result{Error: errors.Wrap(err, "task inputs")}, runInfo
+	}
+
+	var input ObjectParam
+
+	err = multierr.Combine(
+		errors.Wrap(ResolveParam(&input, From(VarExpr(t.Input, vars), Input(inputs, 0))), "input"),
+	)
+	if err != nil {
+		return Result{Error: err}, runInfo
+	}
+
+	switch input.Type {
+	case SliceType:
+		return Result{Value: decimal.NewFromInt(int64(len(input.SliceValue)))}, runInfo
+	case StringType:
+		return Result{Value: decimal.NewFromInt(int64(utf8.RuneCountInString(string(input.StringValue))))}, runInfo
+	default:
+		return Result{Error: errors.Wrapf(ErrBadInput, "expected array or string, got %s", input.String())}, runInfo
+	}
+}
@@ -0,0 +1,82 @@
+package pipeline
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	log "github.com/InjectiveLabs/suplog"
+	"github.com/shopspring/decimal"
+)
+
+func TestLengthTask(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    interface{}
+		expected int64
+		wantErr  bool
+	}{
+		{
+			name:     "String",
+			input:    "hello",
+			expected: 5,
+		},
+		{
+			name:     "String counts characters, not bytes",
+			input:    "héllo€",
+			expected: 6,
+		},
+		{
+			name:     "Empty string",
+			input:    "",
+			expected: 0,
+		},
+		{
+			name:     "Array",
+			input:    []interface{}{"25.1", "25.2", "25.3"},
+			expected: 3,
+		},
+		{
+			name:     "Empty array",
+			input:    []interface{}{},
+			expected: 0,
+		},
+		{
+			name:    "Map",
+			input:   map[string]interface{}{"a": 1, "b": 2},
+			wantErr: true,
+		},
+		{
+			name:    "Number",
+			input:   decimal.NewFromInt(42),
+			wantErr: true,
+		},
+		{
+			name:    "Boolean",
+			input:   true,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			task := LengthTask{
+				BaseTask: NewBaseTask(0, "length", nil, nil, 0),
+			}
+
+			result, _ := task.Run(context.Background(), log.DefaultLogger, NewVarsFrom(nil), []Result{{Value: tt.input}})
+			if tt.wantErr {
+				if !errors.Is(result.Error, ErrBadInput) {
+					t.Errorf("LengthTask(%v) expected ErrBadInput, got %v (%v)", tt.input, result.Error, result.Value)
+				}
+				return
+			}
+
+			if result.Error != nil {
+				t.Fatalf("LengthTask(%v) unexpected error: %v", tt.input, result.Error)
+			} else if value := result.Value.(decimal.Decimal); !value.Equal(decimal.NewFromInt(tt.expected)) {
+				t.Errorf("LengthTask(%v) = %s; want %d", tt.input, value, tt.expected)
+			}
+		})
+	}
+}
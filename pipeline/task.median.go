@@ -2,10 +2,8 @@ package pipeline
 
 import (
 	"context"
-	"sort"
 
 	"github.com/pkg/errors"
-	"github.com/shopspring/decimal"
 	"go.uber.org/multierr"
 
 	log "github.com/InjectiveLabs/suplog"
@@ -60,13 +58,5 @@ func (t *MedianTask) Run(_ context.Context, _ log.Logger, vars Vars, inputs []Re
 		return Result{Error: err}, runInfo
 	}
 
-	sort.Slice(decimalValues, func(i, j int) bool {
-		return decimalValues[i].LessThan(decimalValues[j])
-	})
-	k := len(decimalValues) / 2
-	if len(decimalValues)%2 == 1 {
-		return Result{Value: decimalValues[k]}, runInfo
-	}
-	median := decimalValues[k].Add(decimalValues[k-1]).Div(decimal.NewFromInt(2))
-	return Result{Value: median}, runInfo
+	return Result{Value: medianOf(decimalValues)}, runInfo
 }
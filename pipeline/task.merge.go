@@ -2,6 +2,7 @@ package pipeline
 
 import (
 	"context"
+	"reflect"
 
 	"github.com/pkg/errors"
 	"go.uber.org/multierr"
@@ -9,13 +10,29 @@ import (
 	log "github.com/InjectiveLabs/suplog"
 )
 
+// ErrMergeConflict is returned by the merge task when both sides set the same key to different values
+// and conflicts are configured to fail the task.
+var ErrMergeConflict = errors.New("merge conflict")
+
+// Conflict resolutions of the merge task.
+const (
+	MergeConflictLastWins = "last-wins"
+	MergeConflictError    = "error"
+)
+
+// MergeTask merges the right map into the left one. By default nested maps on the right replace the left ones
+// as a whole, with Deep enabled they're merged key by key. OnConflict decides what happens with keys set
+// on both sides to different values: "last-wins" (default) takes the right value, "error" fails the task.
+//
 // Return types:
 //
 //	map[string]interface{}
 type MergeTask struct {
-	BaseTask `mapstructure:",squash"`
-	Left     string `json:"left"`
-	Right    string `json:"right"`
+	BaseTask   `mapstructure:",squash"`
+	Left       string `json:"left"`
+	Right      string `json:"right"`
+	Deep       string `json:"deep"`
+	OnConflict string `json:"onConflict"`
 }
 
 var _ Task = (*MergeTask)(nil)
@@ -31,22 +48,95 @@ func (t *MergeTask) Run(_ context.Context, _ log.Logger, vars Vars, inputs []Res
 	}
 
 	var (
-		lMap MapParam
-		rMap MapParam
+		lMap       MapParam
+		rMap       MapParam
+		deep       BoolParam
+		onConflict StringParam
 	)
 	err = multierr.Combine(
 		errors.Wrap(ResolveParam(&lMap, From(VarExpr(t.Left, vars), NonemptyString(t.Left), Input(inputs, 0))), "left-side"),
 		errors.Wrap(ResolveParam(&rMap, From(VarExpr(t.Right, vars), NonemptyString(t.Right))), "right-side"),
+		errors.Wrap(ResolveParam(&deep, From(NonemptyString(t.Deep), false)), "deep"),
+		errors.Wrap(ResolveParam(&onConflict, From(NonemptyString(t.OnConflict), MergeConflictLastWins)), "onConflict"),
 	)
 	if err != nil {
 		return Result{Error: err}, runInfo
 	}
 
-	// clobber lMap with rMap values
-	// "nil" values on the right will clobber
-	for key, value := range rMap {
-		lMap[key] = value
+	switch onConflict {
+	case MergeConflictLastWins, MergeConflictError:
+	default:
+		return Result{Error: errors.Errorf("onConflict must be either %s or %s, got %s", MergeConflictLastWins, MergeConflictError, onConflict)}, runInfo
+	}
+
+	if !bool(deep) && onConflict == MergeConflictLastWins {
+		// clobber lMap with rMap values
+		// "nil" values on the right will clobber
+		for key, value := range rMap {
+			lMap[key] = value
+		}
+
+		return Result{Value: lMap.Map()}, runInfo
 	}
 
-	return Result{Value: lMap.Map()}, runInfo
+	merged, err := mergeMaps(lMap.Map(), rMap.Map(), bool(deep), onConflict == MergeConflictError, "")
+	if err != nil {
+		return Result{Error: err}, runInfo
+	}
+
+	return Result{Value: merged}, runInfo
+}
+
+// mergeMaps returns a new map with the right map merged into the left one, leaving both intact.
+// Nested maps are merged recursively if deep is set. Keys set on both sides to different values are
+// taken from the right, or fail the merge if failOnConflict is set. The path prefixes conflicting keys in errors.
+func mergeMaps(left, right map[string]interface{}, deep, failOnConflict bool, path string) (map[string]interface{}, error) {
+	merged := make(map[string]interface{}, len(left)+len(right))
+	for key, value := range left {
+		merged[key] = value
+	}
+
+	for key, rValue := range right {
+		lValue, ok := merged[key]
+		if !ok {
+			merged[key] = rValue
+			continue
+		}
+
+		keyPath := key
+		if len(path) > 0 {
+			keyPath = path + "." + key
+		}
+
+		lNested, lIsMap := asMergeableMap(lValue)
+		rNested, rIsMap := asMergeableMap(rValue)
+		if deep && lIsMap && rIsMap {
+			nested, err := mergeMaps(lNested, rNested, deep, failOnConflict, keyPath)
+			if err != nil {
+				return nil, err
+			}
+
+			merged[key] = nested
+			continue
+		}
+
+		if failOnConflict && !reflect.DeepEqual(lValue, rValue) {
+			return nil, errors.Wrapf(ErrMergeConflict, "key %s is set to %v and %v", keyPath, lValue, rValue)
+		}
+
+		merged[key] = rValue
+	}
+
+	return merged, nil
+}
+
+func asMergeableMap(value interface{}) (map[string]interface{}, bool) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		return v, true
+	case MapParam:
+		return v.Map(), true
+	default:
+		return nil, false
+	}
 }
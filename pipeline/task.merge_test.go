@@ -0,0 +1,110 @@
+package pipeline
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	log "github.com/InjectiveLabs/suplog"
+)
+
+func TestMergeTask(t *testing.T) {
+	const left = `{"auth":{"key":"a","scope":"read"},"symbol":"INJ","limit":10}`
+
+	tests := []struct {
+		name       string
+		right      string
+		deep       string
+		onConflict string
+		expected   map[string]interface{}
+		err        error
+	}{
+		{
+			name:  "Shallow merge replaces nested maps",
+			right: `{"auth":{"scope":"write"},"limit":20}`,
+			expected: map[string]interface{}{
+				"auth":   map[string]interface{}{"scope": "write"},
+				"symbol": "INJ",
+				"limit":  float64(20),
+			},
+		},
+		{
+			name:  "Deep merge combines nested maps",
+			right: `{"auth":{"scope":"write","nonce":1},"limit":20}`,
+			deep:  "true",
+			expected: map[string]interface{}{
+				"auth":   map[string]interface{}{"key": "a", "scope": "write", "nonce": float64(1)},
+				"symbol": "INJ",
+				"limit":  float64(20),
+			},
+		},
+		{
+			name:       "Deep merge fails on nested key conflict",
+			right:      `{"auth":{"scope":"write"}}`,
+			deep:       "true",
+			onConflict: MergeConflictError,
+			err:        ErrMergeConflict,
+		},
+		{
+			name:       "Deep merge allows equal values on both sides",
+			right:      `{"auth":{"scope":"read","nonce":1}}`,
+			deep:       "true",
+			onConflict: MergeConflictError,
+			expected: map[string]interface{}{
+				"auth":   map[string]interface{}{"key": "a", "scope": "read", "nonce": float64(1)},
+				"symbol": "INJ",
+				"limit":  float64(10),
+			},
+		},
+		{
+			name:       "Map replacing a value is a conflict",
+			right:      `{"symbol":{"base":"INJ"}}`,
+			deep:       "true",
+			onConflict: MergeConflictError,
+			err:        ErrMergeConflict,
+		},
+		{
+			name:       "Shallow merge fails on nested map conflict",
+			right:      `{"auth":{"key":"a","scope":"read","nonce":1}}`,
+			onConflict: MergeConflictError,
+			err:        ErrMergeConflict,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			task := MergeTask{
+				BaseTask:   NewBaseTask(0, "merge", nil, nil, 0),
+				Left:       left,
+				Right:      tt.right,
+				Deep:       tt.deep,
+				OnConflict: tt.onConflict,
+			}
+
+			result, _ := task.Run(context.Background(), log.DefaultLogger, NewVarsFrom(nil), nil)
+			if tt.err != nil {
+				if !errors.Is(result.Error, tt.err) {
+					t.Fatalf("expected error %v, got %v", tt.err, result.Error)
+				}
+				return
+			} else if result.Error != nil {
+				t.Fatalf("unexpected error: %v", result.Error)
+			}
+
+			if !reflect.DeepEqual(result.Value, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, result.Value)
+			}
+		})
+	}
+
+	task := MergeTask{
+		BaseTask:   NewBaseTask(0, "merge", nil, nil, 0),
+		Left:       left,
+		Right:      `{}`,
+		OnConflict: "first-wins",
+	}
+	if result, _ := task.Run(context.Background(), log.DefaultLogger, NewVarsFrom(nil), nil); result.Error == nil {
+		t.Errorf("expected error for unknown conflict resolution")
+	}
+}
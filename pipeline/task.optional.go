@@ -0,0 +1,77 @@
+package pipeline
+
+import (
+	"context"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	log "github.com/InjectiveLabs/suplog"
+)
+
+// OptionalTask passes its input through, substituting Default when the input task errored or timed out,
+// so a pipeline degrades gracefully when one of several sources is down. Inputs of an optional task must
+// not be marked as failEarly, since that cancels the whole run before the default can be substituted.
+//
+// Return types:
+//
+//	interface{} (the input value)
+//	*decimal.Decimal (the default)
+type OptionalTask struct {
+	BaseTask `mapstructure:",squash"`
+	Input    string `json:"input"`
+	Default  string `json:"default"`
+}
+
+var _ Task = (*OptionalTask)(nil)
+
+func (t *OptionalTask) Type() TaskType {
+	return TaskTypeOptional
+}
+
+func (t *OptionalTask) Run(_ context.Context, lggr log.Logger, vars Vars, inputs []Result) (result Result, runInfo RunInfo) {
+	var defaultValue DecimalParam
+	err := errors.Wrap(ResolveParam(&defaultValue, From(VarExpr(t.Default, vars), NonemptyString(t.Default))), "default")
+	if err != nil {
+		return Result{Error: err}, runInfo
+	}
+
+	var (
+		value    interface{}
+		inputErr error
+	)
+	if len(strings.TrimSpace(t.Input)) > 0 {
+		value, inputErr = VarExpr(t.Input, vars)()
+	} else {
+		if _, err := CheckInputs(inputs, 1, 1, 1); err != nil {
+			return Result{Error: errors.Wrap(err, "task inputs")}, runInfo
+		}
+
+		value, inputErr = inputs[0].Value, inputs[0].Error
+	}
+
+	if inputErr != nil {
+		lggr.WithError(inputErr).WithField("dot_id", t.DotID()).Debugln("optional input failed, using default")
+		return Result{Value: defaultValue.Decimal()}, runInfo
+	}
+
+	return Result{Value: value}, runInfo
+}
+
+// validateOptionalInputs rejects failEarly inputs of optional tasks, since a failure of such input
+// cancels the run before the optional task could substitute the default.
+func validateOptionalInputs(p *Pipeline) error {
+	for _, task := range p.Tasks {
+		if task.Type() != TaskTypeOptional {
+			continue
+		}
+
+		for _, input := range task.Inputs() {
+			if input.InputTask.Base().FailEarly {
+				return errors.Errorf("input %s of optional task %s cannot be marked as failEarly", input.InputTask.DotID(), task.DotID())
+			}
+		}
+	}
+
+	return nil
+}
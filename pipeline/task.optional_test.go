@@ -0,0 +1,98 @@
+package pipeline
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	log "github.com/InjectiveLabs/suplog"
+	"github.com/shopspring/decimal"
+)
+
+func TestOptionalTask(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    Result
+		expected string
+	}{
+		{
+			name:     "Input value is passed through",
+			input:    Result{Value: "42.1"},
+			expected: "42.1",
+		},
+		{
+			name:     "Errored input yields default",
+			input:    Result{Error: errors.New("source down")},
+			expected: "1.5",
+		},
+		{
+			name:     "Timed out input yields default",
+			input:    Result{Error: ErrTimeout},
+			expected: "1.5",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			task := OptionalTask{
+				BaseTask: NewBaseTask(0, "optional", nil, nil, 0),
+				Default:  "1.5",
+			}
+
+			result, _ := task.Run(context.Background(), log.DefaultLogger, NewVarsFrom(nil), []Result{tt.input})
+			if result.Error != nil {
+				t.Fatalf("unexpected error: %v", result.Error)
+			}
+
+			value, err := ToDecimal(result.Value)
+			if err != nil {
+				t.Fatalf("unexpected value %v: %v", result.Value, err)
+			} else if !value.Equal(decimal.RequireFromString(tt.expected)) {
+				t.Errorf("OptionalTask(%v) = %s; want %s", tt.input, value, tt.expected)
+			}
+		})
+	}
+}
+
+func TestOptionalTaskInPipeline(t *testing.T) {
+	spec := Spec{
+		DotDagSource: `
+			down  [type=fail msg="source down"]
+			alt   [type=optional default=10]
+			up    [type=memo value="20"]
+			mean  [type=mean]
+
+			down -> alt -> mean
+			up -> mean
+		`,
+	}
+
+	_, trrs, err := NewRunner(log.DefaultLogger).ExecuteRun(context.Background(), spec, NewVarsFrom(nil), log.DefaultLogger)
+	if err != nil {
+		t.Fatalf("failed to execute run: %v", err)
+	}
+
+	finalResult := trrs.FinalResult(log.DefaultLogger)
+	if finalResult.HasFatalErrors() {
+		t.Fatalf("unexpected fatal errors: %v", finalResult.FatalErrors)
+	}
+
+	value, err := ToDecimal(finalResult.Values[0])
+	if err != nil {
+		t.Fatalf("unexpected value %v: %v", finalResult.Values[0], err)
+	} else if !value.Equal(decimal.NewFromInt(15)) {
+		t.Errorf("expected 15, got %s", value)
+	}
+}
+
+func TestOptionalTaskRejectsFailEarlyInput(t *testing.T) {
+	_, err := Parse(`
+		down [type=fail msg="source down" failEarly=true]
+		alt  [type=optional default=10]
+
+		down -> alt
+	`)
+	if err == nil {
+		t.Fatal("expected error for failEarly input of an optional task")
+	}
+}
@@ -0,0 +1,92 @@
+package pipeline
+
+import (
+	"context"
+	"math/big"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/shopspring/decimal"
+	"go.uber.org/multierr"
+
+	log "github.com/InjectiveLabs/suplog"
+)
+
+// ParseNumericTask parses a numeric string into a decimal. The string may be
+// in base 10 (with an optional fractional part) or base 16 (integers only,
+// "0x" prefix is optional). When units is set, the parsed value is scaled down
+// by 10^units, which is handy for wei-style integer prices.
+//
+// Return types:
+//
+//	*decimal.Decimal
+type ParseNumericTask struct {
+	BaseTask `mapstructure:",squash"`
+	Input    string `json:"input"`
+	Radix    string `mapstructure:"base" json:"base"`
+	Units    string `json:"units"`
+}
+
+var _ Task = (*ParseNumericTask)(nil)
+
+func (t *ParseNumericTask) Type() TaskType {
+	return TaskTypeParseNumeric
+}
+
+func (t *ParseNumericTask) Run(_ context.Context, _ log.Logger, vars Vars, inputs []Result) (result Result, runInfo RunInfo) {
+	_, err := CheckInputs(inputs, 0, 1, 0)
+	if err != nil {
+		return Result{Error: errors.Wrap(err, "task inputs")}, runInfo
+	}
+
+	var (
+		input      StringParam
+		maybeBase  MaybeUint64Param
+		maybeUnits MaybeInt32Param
+	)
+	err = multierr.Combine(
+		errors.Wrap(ResolveParam(&input, From(VarExpr(t.Input, vars), Input(inputs, 0))), "input"),
+		errors.Wrap(ResolveParam(&maybeBase, From(VarExpr(t.Radix, vars), t.Radix)), "base"),
+		errors.Wrap(ResolveParam(&maybeUnits, From(VarExpr(t.Units, vars), t.Units)), "units"),
+	)
+	if err != nil {
+		return Result{Error: err}, runInfo
+	}
+
+	str := strings.TrimSpace(string(input))
+	hasHexPrefix := strings.HasPrefix(str, "0x") || strings.HasPrefix(str, "0X")
+
+	base := uint64(10)
+	if b, isSet := maybeBase.Uint64(); isSet {
+		base = b
+	} else if hasHexPrefix {
+		base = 16
+	}
+
+	var value decimal.Decimal
+	switch base {
+	case 10:
+		value, err = decimal.NewFromString(str)
+		if err != nil {
+			return Result{Error: errors.Wrapf(ErrBadInput, "failed to parse %q as base 10 number: %v", str, err)}, runInfo
+		}
+	case 16:
+		if hasHexPrefix {
+			str = str[2:]
+		}
+
+		n, ok := new(big.Int).SetString(str, 16)
+		if !ok {
+			return Result{Error: errors.Wrapf(ErrBadInput, "failed to parse %q as base 16 number", str)}, runInfo
+		}
+		value = decimal.NewFromBigInt(n, 0)
+	default:
+		return Result{Error: errors.Wrapf(ErrBadInput, "unsupported base %d (expected 10 or 16)", base)}, runInfo
+	}
+
+	if units, isSet := maybeUnits.Int32(); isSet {
+		value = value.Shift(-units)
+	}
+
+	return Result{Value: value}, runInfo
+}
@@ -0,0 +1,87 @@
+package pipeline
+
+import (
+	"context"
+	"testing"
+
+	log "github.com/InjectiveLabs/suplog"
+	"github.com/shopspring/decimal"
+)
+
+func TestParseNumericTask(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		base     string
+		units    string
+		expected string
+		wantErr  bool
+	}{
+		{
+			name:     "Parse hex string with prefix",
+			input:    "0x1a",
+			expected: "26",
+		},
+		{
+			name:     "Parse hex string with explicit base",
+			input:    "1a",
+			base:     "16",
+			expected: "26",
+		},
+		{
+			name:     "Parse decimal string",
+			input:    "1234.5678",
+			expected: "1234.5678",
+		},
+		{
+			name:     "Parse wei-style integer with 18 decimals",
+			input:    "1500250000000000000000",
+			units:    "18",
+			expected: "1500.25",
+		},
+		{
+			name:     "Parse hex string with 18 decimals",
+			input:    "0xde0b6b3a7640000",
+			units:    "18",
+			expected: "1",
+		},
+		{
+			name:    "Reject non-numeric string",
+			input:   "abc",
+			wantErr: true,
+		},
+		{
+			name:    "Reject unsupported base",
+			input:   "101",
+			base:    "2",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			task := ParseNumericTask{
+				BaseTask: NewBaseTask(0, "parse", nil, nil, 0),
+				Radix:    tt.base,
+				Units:    tt.units,
+			}
+
+			result, _ := task.Run(context.Background(), log.DefaultLogger, NewVarsFrom(nil), []Result{{Value: tt.input}})
+			if tt.wantErr {
+				if result.Error == nil {
+					t.Errorf("ParseNumericTask(%q) expected error, got %v", tt.input, result.Value)
+				}
+				return
+			}
+
+			if result.Error != nil {
+				t.Fatalf("ParseNumericTask(%q) unexpected error: %v", tt.input, result.Error)
+			}
+
+			expected := decimal.RequireFromString(tt.expected)
+			if value := result.Value.(decimal.Decimal); !value.Equal(expected) {
+				t.Errorf("ParseNumericTask(%q) = %s; want %s", tt.input, value, expected)
+			}
+		})
+	}
+}
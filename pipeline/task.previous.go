@@ -0,0 +1,77 @@
+package pipeline
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+
+	log "github.com/InjectiveLabs/suplog"
+)
+
+// ErrNoPreviousRun is returned by the previous task when the job has no previous run output to yield.
+var ErrNoPreviousRun = errors.New("no previous run output")
+
+// PreviousTask yields the output of the previous completed run of the same job, e.g. to compute the rate
+// of change of a price. The run history is kept in memory, so it's empty after a restart: the task yields
+// Default then, or fails if there's no default. Outputs older than MaxAge are treated as missing.
+//
+// Return types:
+//
+//	interface{} (the previous output)
+//	*decimal.Decimal (the default)
+type PreviousTask struct {
+	BaseTask `mapstructure:",squash"`
+	Default  string `json:"default"`
+	MaxAge   string `json:"maxAge"`
+}
+
+var _ Task = (*PreviousTask)(nil)
+
+func (t *PreviousTask) Type() TaskType {
+	return TaskTypePrevious
+}
+
+func (t *PreviousTask) Run(ctx context.Context, _ log.Logger, vars Vars, inputs []Result) (result Result, runInfo RunInfo) {
+	_, err := CheckInputs(inputs, -1, -1, 0)
+	if err != nil {
+		return Result{Error: errors.Wrap(err, "task inputs")}, runInfo
+	}
+
+	var maxAge StringParam
+	if err = errors.Wrap(ResolveParam(&maxAge, From(NonemptyString(t.MaxAge), "")), "maxAge"); err != nil {
+		return Result{Error: err}, runInfo
+	}
+
+	var maxAgeDuration time.Duration
+	if len(maxAge) > 0 {
+		if maxAgeDuration, err = time.ParseDuration(string(maxAge)); err != nil || maxAgeDuration <= 0 {
+			return Result{Error: errors.Errorf("maxAge must be a positive duration, got %s", maxAge)}, runInfo
+		}
+	}
+
+	jobName := jobNameFrom(ctx)
+	if len(jobName) == 0 {
+		return Result{Error: errors.New("previous task requires a job name of the run")}, runInfo
+	}
+
+	output, ok := defaultRunHistory.last(jobName)
+	if ok && maxAgeDuration > 0 && time.Since(output.finishedAt) > maxAgeDuration {
+		ok = false
+	}
+
+	if !ok {
+		if len(t.Default) == 0 {
+			return Result{Error: errors.Wrapf(ErrNoPreviousRun, "job %s", jobName)}, runInfo
+		}
+
+		var defaultValue DecimalParam
+		if err = errors.Wrap(ResolveParam(&defaultValue, From(VarExpr(t.Default, vars), NonemptyString(t.Default))), "default"); err != nil {
+			return Result{Error: err}, runInfo
+		}
+
+		return Result{Value: defaultValue.Decimal()}, runInfo
+	}
+
+	return Result{Value: output.value}, runInfo
+}
@@ -0,0 +1,70 @@
+package pipeline
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	log "github.com/InjectiveLabs/suplog"
+	"github.com/shopspring/decimal"
+)
+
+func TestPreviousTask(t *testing.T) {
+	runFinal := func(t *testing.T, jobName, source string) FinalResult {
+		spec := Spec{
+			JobName:      jobName,
+			DotDagSource: source,
+		}
+
+		_, trrs, err := NewRunner(log.DefaultLogger).ExecuteRun(context.Background(), spec, NewVarsFrom(nil), log.DefaultLogger)
+		if err != nil {
+			t.Fatalf("failed to execute run: %v", err)
+		}
+
+		return trrs.FinalResult(log.DefaultLogger)
+	}
+
+	const smoothed = `
+		prev [type=previous default=10]
+		cur  [type=memo value="20"]
+		mean [type=mean]
+
+		prev -> mean
+		cur -> mean
+	`
+
+	finalResult := runFinal(t, "test_previous_missing", `prev [type=previous]`)
+	if !finalResult.HasFatalErrors() || !errors.Is(finalResult.FatalErrors[0], ErrNoPreviousRun) {
+		t.Fatalf("expected no previous run error, got %v", finalResult.FatalErrors)
+	}
+
+	for _, expected := range []string{"15", "17.5"} {
+		finalResult = runFinal(t, "test_previous", smoothed)
+		if finalResult.HasFatalErrors() {
+			t.Fatalf("unexpected fatal errors: %v", finalResult.FatalErrors)
+		}
+
+		value, err := ToDecimal(finalResult.Values[0])
+		if err != nil {
+			t.Fatalf("unexpected value %v: %v", finalResult.Values[0], err)
+		} else if !value.Equal(decimal.RequireFromString(expected)) {
+			t.Errorf("expected %s, got %s", expected, value)
+		}
+	}
+
+	// outputs of other jobs and stale outputs are not used
+	for jobName, source := range map[string]string{
+		"test_previous_other": `prev [type=previous default=1]`,
+		"test_previous":       `prev [type=previous default=1 maxAge="1ns"]`,
+	} {
+		finalResult = runFinal(t, jobName, source)
+		if finalResult.HasFatalErrors() {
+			t.Fatalf("unexpected fatal errors: %v", finalResult.FatalErrors)
+		}
+
+		value, err := ToDecimal(finalResult.Values[0])
+		if err != nil || !value.Equal(decimal.NewFromInt(1)) {
+			t.Errorf("expected default value 1 for %s, got %v", source, finalResult.Values[0])
+		}
+	}
+}
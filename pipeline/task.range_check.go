@@ -0,0 +1,72 @@
+package pipeline
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"go.uber.org/multierr"
+
+	log "github.com/InjectiveLabs/suplog"
+)
+
+// ErrOutOfRange is returned by the rangecheck task for values out of its bounds.
+var ErrOutOfRange = errors.New("value out of range")
+
+// RangeCheckTask passes its input through if it's within the inclusive [min, max] range and errors otherwise,
+// so a feed rejects glitchy values in the pipeline. Either bound may be omitted, but not both.
+//
+// Return types:
+//
+//	decimal.Decimal
+type RangeCheckTask struct {
+	BaseTask `mapstructure:",squash"`
+	Input    string `json:"input"`
+	Min      string `json:"min"`
+	Max      string `json:"max"`
+}
+
+var _ Task = (*RangeCheckTask)(nil)
+
+func (t *RangeCheckTask) Type() TaskType {
+	return TaskTypeRangeCheck
+}
+
+func (t *RangeCheckTask) Run(_ context.Context, _ log.Logger, vars Vars, inputs []Result) (result Result, runInfo RunInfo) {
+	_, err := CheckInputs(inputs, -1, -1, 0)
+	if err != nil {
+		return Result{Error: errors.Wrap(err, "task inputs")}, runInfo
+	}
+
+	if len(t.Min) == 0 && len(t.Max) == 0 {
+		return Result{Error: errors.Wrap(ErrParameterEmpty, "min or max must be set")}, runInfo
+	}
+
+	var (
+		value    DecimalParam
+		min, max DecimalParam
+	)
+	err = errors.Wrap(ResolveParam(&value, From(VarExpr(t.Input, vars), Input(inputs, 0))), "input")
+	if len(t.Min) > 0 {
+		err = multierr.Append(err, errors.Wrap(ResolveParam(&min, From(VarExpr(t.Min, vars), t.Min)), "min"))
+	}
+	if len(t.Max) > 0 {
+		err = multierr.Append(err, errors.Wrap(ResolveParam(&max, From(VarExpr(t.Max, vars), t.Max)), "max"))
+	}
+	if err != nil {
+		return Result{Error: err}, runInfo
+	}
+
+	if len(t.Min) > 0 && len(t.Max) > 0 && min.Decimal().GreaterThan(max.Decimal()) {
+		return Result{Error: errors.Wrapf(ErrBadInput, "min %s is greater than max %s", min.Decimal(), max.Decimal())}, runInfo
+	}
+
+	if len(t.Min) > 0 && value.Decimal().LessThan(min.Decimal()) {
+		return Result{Error: errors.Wrapf(ErrOutOfRange, "%s is less than min %s", value.Decimal(), min.Decimal())}, runInfo
+	}
+
+	if len(t.Max) > 0 && value.Decimal().GreaterThan(max.Decimal()) {
+		return Result{Error: errors.Wrapf(ErrOutOfRange, "%s is greater than max %s", value.Decimal(), max.Decimal())}, runInfo
+	}
+
+	return Result{Value: value.Decimal()}, runInfo
+}
@@ -0,0 +1,114 @@
+package pipeline
+
+import (
+	"context"
+	"testing"
+
+	log "github.com/InjectiveLabs/suplog"
+	"github.com/pkg/errors"
+	"github.com/shopspring/decimal"
+)
+
+func TestRangeCheckTask(t *testing.T) {
+	tests := []struct {
+		name       string
+		input      string
+		min        string
+		max        string
+		outOfRange bool
+		wantErr    bool
+	}{
+		{
+			name:  "Within range",
+			input: "5.5",
+			min:   "1",
+			max:   "10",
+		},
+		{
+			name:  "Equal to min",
+			input: "1.000",
+			min:   "1",
+			max:   "10",
+		},
+		{
+			name:  "Equal to max",
+			input: "10",
+			min:   "1",
+			max:   "10",
+		},
+		{
+			name:       "Below min",
+			input:      "0.999",
+			min:        "1",
+			max:        "10",
+			outOfRange: true,
+		},
+		{
+			name:       "Above max",
+			input:      "10.001",
+			min:        "1",
+			max:        "10",
+			outOfRange: true,
+		},
+		{
+			name:  "Only min",
+			input: "1000000",
+			min:   "1",
+		},
+		{
+			name:  "Only max",
+			input: "-1",
+			max:   "0",
+		},
+		{
+			name:    "Neither min nor max",
+			input:   "5",
+			wantErr: true,
+		},
+		{
+			name:    "Min greater than max",
+			input:   "5",
+			min:     "10",
+			max:     "1",
+			wantErr: true,
+		},
+		{
+			name:    "Non-numeric input",
+			input:   "abc",
+			min:     "1",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			task := RangeCheckTask{
+				BaseTask: NewBaseTask(0, "rangecheck", nil, nil, 0),
+				Min:      tt.min,
+				Max:      tt.max,
+			}
+
+			result, _ := task.Run(context.Background(), log.DefaultLogger, NewVarsFrom(nil), []Result{{Value: tt.input}})
+			if tt.outOfRange {
+				if !errors.Is(result.Error, ErrOutOfRange) {
+					t.Errorf("RangeCheckTask(%s) expected out of range error, got %v (%v)", tt.input, result.Error, result.Value)
+				}
+				return
+			} else if tt.wantErr {
+				if result.Error == nil {
+					t.Errorf("RangeCheckTask(%s) expected error, got %v", tt.input, result.Value)
+				}
+				return
+			}
+
+			if result.Error != nil {
+				t.Fatalf("RangeCheckTask(%s) unexpected error: %v", tt.input, result.Error)
+			}
+
+			expected := decimal.RequireFromString(tt.input)
+			if value := result.Value.(decimal.Decimal); !value.Equal(expected) {
+				t.Errorf("RangeCheckTask(%s) = %s; want %s", tt.input, value, expected)
+			}
+		})
+	}
+}
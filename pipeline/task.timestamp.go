@@ -0,0 +1,61 @@
+package pipeline
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	log "github.com/InjectiveLabs/suplog"
+)
+
+// timeNow is the clock used by the timestamp task, overridden in tests.
+var timeNow = time.Now
+
+// TimestampTask returns the current unix time. Unit can be "s" (default), "ms", "us" or "ns".
+//
+// Return types:
+//
+//	int64
+type TimestampTask struct {
+	BaseTask `mapstructure:",squash"`
+	Unit     string `json:"unit"`
+}
+
+var _ Task = (*TimestampTask)(nil)
+
+func (t *TimestampTask) Type() TaskType {
+	return TaskTypeTimestamp
+}
+
+func (t *TimestampTask) Run(_ context.Context, _ log.Logger, vars Vars, _ []Result) (result Result, runInfo RunInfo) {
+	var unit StringParam
+	err := errors.Wrap(ResolveParam(&unit, From(VarExpr(t.Unit, vars), NonemptyString(t.Unit), "s")), "unit")
+	if err != nil {
+		return Result{Error: err}, runInfo
+	}
+
+	timestamp, err := unixTime(timeNow(), string(unit))
+	if err != nil {
+		return Result{Error: err}, runInfo
+	}
+
+	return Result{Value: timestamp}, runInfo
+}
+
+// unixTime returns the unix time of now in the unit, "s", "ms", "us" or "ns".
+func unixTime(now time.Time, unit string) (int64, error) {
+	switch strings.ToLower(unit) {
+	case "s", "sec", "seconds":
+		return now.Unix(), nil
+	case "ms", "millis", "milliseconds":
+		return now.UnixMilli(), nil
+	case "us", "micros", "microseconds":
+		return now.UnixMicro(), nil
+	case "ns", "nanos", "nanoseconds":
+		return now.UnixNano(), nil
+	default:
+		return 0, errors.Wrapf(ErrBadInput, "unsupported unit: %s", unit)
+	}
+}
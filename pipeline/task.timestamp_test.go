@@ -0,0 +1,67 @@
+package pipeline
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	log "github.com/InjectiveLabs/suplog"
+)
+
+func TestTimestampTask(t *testing.T) {
+	fixed := time.Unix(1738013700, 767706647)
+	timeNow = func() time.Time { return fixed }
+	defer func() { timeNow = time.Now }()
+
+	tests := []struct {
+		name     string
+		unit     string
+		expected int64
+		wantErr  bool
+	}{
+		{
+			name:     "Default unit is seconds",
+			expected: 1738013700,
+		},
+		{
+			name:     "Milliseconds",
+			unit:     "ms",
+			expected: 1738013700767,
+		},
+		{
+			name:     "Nanoseconds",
+			unit:     "ns",
+			expected: 1738013700767706647,
+		},
+		{
+			name:    "Unsupported unit",
+			unit:    "days",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			task := TimestampTask{
+				BaseTask: NewBaseTask(0, "now", nil, nil, 0),
+				Unit:     tt.unit,
+			}
+
+			result, _ := task.Run(context.Background(), log.DefaultLogger, NewVarsFrom(nil), nil)
+			if tt.wantErr {
+				if result.Error == nil {
+					t.Errorf("TimestampTask(%q) expected error, got %v", tt.unit, result.Value)
+				}
+				return
+			}
+
+			if result.Error != nil {
+				t.Fatalf("TimestampTask(%q) unexpected error: %v", tt.unit, result.Error)
+			}
+
+			if result.Value != tt.expected {
+				t.Errorf("TimestampTask(%q) = %v; want %d", tt.unit, result.Value, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,111 @@
+package pipeline
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/shopspring/decimal"
+	"go.uber.org/multierr"
+
+	log "github.com/InjectiveLabs/suplog"
+)
+
+// ErrInsufficientSamples is returned by the twap task when its window holds fewer samples than required.
+var ErrInsufficientSamples = errors.New("insufficient samples")
+
+// TWAPTask accumulates its input over a time window across runs of the same job, and yields the time-weighted
+// average of it, e.g. the average premium over a funding interval. Every sample is weighted by the time since
+// the previous one, so the input of the current run counts right away. Samples are kept in memory, so they are
+// lost on restart and the average is computed over samples since the restart only, until the window fills up again.
+//
+// Return types:
+//
+//	decimal.Decimal
+type TWAPTask struct {
+	BaseTask   `mapstructure:",squash"`
+	Input      string `json:"input"`
+	Window     string `json:"window"`
+	MinSamples string `json:"minSamples"`
+}
+
+var _ Task = (*TWAPTask)(nil)
+
+func (t *TWAPTask) Type() TaskType {
+	return TaskTypeTWAP
+}
+
+func (t *TWAPTask) Run(ctx context.Context, _ log.Logger, vars Vars, inputs []Result) (result Result, runInfo RunInfo) {
+	_, err := CheckInputs(inputs, 0, 1, 0)
+	if err != nil {
+		return Result{Error: errors.Wrap(err, "task inputs")}, runInfo
+	}
+
+	var (
+		input      DecimalParam
+		window     StringParam
+		minSamples Uint64Param
+	)
+	err = multierr.Combine(
+		errors.Wrap(ResolveParam(&input, From(VarExpr(t.Input, vars), NonemptyString(t.Input), Input(inputs, 0))), "input"),
+		errors.Wrap(ResolveParam(&window, From(NonemptyString(t.Window))), "window"),
+		errors.Wrap(ResolveParam(&minSamples, From(VarExpr(t.MinSamples, vars), NonemptyString(t.MinSamples), 1)), "minSamples"),
+	)
+	if err != nil {
+		return Result{Error: err}, runInfo
+	}
+
+	windowDuration, err := time.ParseDuration(string(window))
+	if err != nil || windowDuration <= 0 {
+		return Result{Error: errors.Errorf("window must be a positive duration, got %s", window)}, runInfo
+	}
+
+	jobName := jobNameFrom(ctx)
+	if len(jobName) == 0 {
+		return Result{Error: errors.New("twap task requires a job name of the run")}, runInfo
+	}
+
+	now := time.Now()
+	windowStart := now.Add(-windowDuration)
+	samples := defaultRunHistory.addSample(jobName+"/"+t.DotID(), runSample{
+		value: input.Decimal(),
+		at:    now,
+	}, windowStart)
+
+	if uint64(len(samples)) < uint64(minSamples) {
+		return Result{Error: errors.Wrapf(ErrInsufficientSamples, "%d of %d samples in the %s window", len(samples), minSamples, window)}, runInfo
+	}
+
+	return Result{Value: timeWeightedAverage(samples, windowStart)}, runInfo
+}
+
+// timeWeightedAverage returns the average of samples over the window since windowStart, holding the value of every
+// sample over the interval since the previous one, clipped to windowStart. The earliest sample has no previous one,
+// so it only starts the interval of the next one. If samples cover no time, e.g. on the first run, it returns
+// the value of the latest sample.
+func timeWeightedAverage(samples []runSample, windowStart time.Time) decimal.Decimal {
+	sum := decimal.Zero
+	totalWeight := decimal.Zero
+
+	for i := 1; i < len(samples); i++ {
+		from := samples[i-1].at
+		if from.Before(windowStart) {
+			from = windowStart
+		}
+
+		to := samples[i].at
+		if !to.After(from) {
+			continue
+		}
+
+		weight := decimal.NewFromInt(int64(to.Sub(from)))
+		sum = sum.Add(samples[i].value.Mul(weight))
+		totalWeight = totalWeight.Add(weight)
+	}
+
+	if totalWeight.IsZero() {
+		return samples[len(samples)-1].value
+	}
+
+	return sum.Div(totalWeight)
+}
@@ -0,0 +1,101 @@
+package pipeline
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	log "github.com/InjectiveLabs/suplog"
+	"github.com/shopspring/decimal"
+)
+
+func TestTWAPTask(t *testing.T) {
+	run := func(t *testing.T, jobName, source string) FinalResult {
+		spec := Spec{
+			JobName:      jobName,
+			DotDagSource: source,
+		}
+
+		_, trrs, err := NewRunner(log.DefaultLogger).ExecuteRun(context.Background(), spec, NewVarsFrom(nil), log.DefaultLogger)
+		if err != nil {
+			t.Fatalf("failed to execute run: %v", err)
+		}
+
+		return trrs.FinalResult(log.DefaultLogger)
+	}
+
+	finalResult := run(t, "test_twap_min_samples", `twap [type=twap input=1 window="1h" minSamples=2]`)
+	if !finalResult.HasFatalErrors() || !errors.Is(finalResult.FatalErrors[0], ErrInsufficientSamples) {
+		t.Fatalf("expected insufficient samples error, got %v", finalResult.FatalErrors)
+	}
+
+	finalResult = run(t, "test_twap_min_samples", `twap [type=twap input=3 window="1h" minSamples=2]`)
+	if finalResult.HasFatalErrors() {
+		t.Fatalf("unexpected fatal errors: %v", finalResult.FatalErrors)
+	}
+
+	// the first sample only starts the interval of the second one, so the latest input is the average
+	value, err := ToDecimal(finalResult.Values[0])
+	if err != nil {
+		t.Fatalf("unexpected value %v: %v", finalResult.Values[0], err)
+	} else if !value.Equal(decimal.NewFromInt(3)) {
+		t.Errorf("expected an average of 3, got %s", value)
+	}
+
+	finalResult = run(t, "test_twap_window", `twap [type=twap window="-1h"]`)
+	if !finalResult.HasFatalErrors() {
+		t.Error("expected an invalid window error")
+	}
+}
+
+func TestTimeWeightedAverage(t *testing.T) {
+	now := time.Now()
+	sample := func(value int64, ago time.Duration) runSample {
+		return runSample{value: decimal.NewFromInt(value), at: now.Add(-ago)}
+	}
+
+	for name, tc := range map[string]struct {
+		samples  []runSample
+		expected string
+	}{
+		"single sample": {
+			samples:  []runSample{sample(5, 0)},
+			expected: "5",
+		},
+		"weighted by time since the previous sample": {
+			samples:  []runSample{sample(10, 40*time.Minute), sample(20, 10*time.Minute), sample(100, 0)},
+			expected: "40",
+		},
+		"latest sample counts right away": {
+			samples:  []runSample{sample(10, 20*time.Minute), sample(10, 10*time.Minute), sample(40, 0)},
+			expected: "25",
+		},
+		"clipped to the window start": {
+			samples:  []runSample{sample(10, 2*time.Hour), sample(30, 30*time.Minute), sample(100, 0)},
+			expected: "65",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			average := timeWeightedAverage(tc.samples, now.Add(-time.Hour))
+			if !average.Equal(decimal.RequireFromString(tc.expected)) {
+				t.Errorf("expected %s, got %s", tc.expected, average)
+			}
+		})
+	}
+}
+
+func TestRunHistoryAddSample(t *testing.T) {
+	history := &runHistory{samples: make(map[string][]runSample)}
+	now := time.Now()
+
+	for _, ago := range []time.Duration{3 * time.Hour, 2 * time.Hour, 30 * time.Minute} {
+		history.addSample("job/twap", runSample{value: decimal.NewFromInt(1), at: now.Add(-ago)}, now.Add(-time.Hour))
+	}
+
+	// the sample taken 2h ago still covers the start of the window
+	samples := history.addSample("job/twap", runSample{value: decimal.NewFromInt(1), at: now}, now.Add(-time.Hour))
+	if len(samples) != 3 || !samples[0].at.Equal(now.Add(-2*time.Hour)) {
+		t.Errorf("expected 3 samples starting 2h ago, got %v", samples)
+	}
+}
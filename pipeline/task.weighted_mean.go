@@ -0,0 +1,109 @@
+package pipeline
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"github.com/shopspring/decimal"
+	"go.uber.org/multierr"
+
+	log "github.com/InjectiveLabs/suplog"
+)
+
+// WeightedMeanTask averages values weighted by a parallel array of weights, e.g. liquidity or confidence
+// of each source. A faulty value is dropped along with its weight.
+//
+// Return types:
+//
+//	*decimal.Decimal
+type WeightedMeanTask struct {
+	BaseTask      `mapstructure:",squash"`
+	Values        string `json:"values"`
+	Weights       string `json:"weights"`
+	AllowedFaults string `json:"allowedFaults"`
+	Precision     string `json:"precision"`
+}
+
+var _ Task = (*WeightedMeanTask)(nil)
+
+func (t *WeightedMeanTask) Type() TaskType {
+	return TaskTypeWeightedMean
+}
+
+func (t *WeightedMeanTask) Run(ctx context.Context, lggr log.Logger, vars Vars, inputs []Result) (result Result, runInfo RunInfo) {
+	var (
+		maybeAllowedFaults MaybeUint64Param
+		maybePrecision     MaybeInt32Param
+		valuesAndErrs      SliceParam
+		weights            DecimalSliceParam
+		allowedFaults      int
+	)
+	err := multierr.Combine(
+		errors.Wrap(ResolveParam(&maybeAllowedFaults, From(t.AllowedFaults)), "allowedFaults"),
+		errors.Wrap(ResolveParam(&maybePrecision, From(VarExpr(t.Precision, vars), t.Precision)), "precision"),
+		errors.Wrap(ResolveParam(&valuesAndErrs, From(VarExpr(t.Values, vars), JSONWithVarExprs(t.Values, vars, true), Inputs(inputs))), "values"),
+		errors.Wrap(ResolveParam(&weights, From(VarExpr(t.Weights, vars), JSONWithVarExprs(t.Weights, vars, false))), "weights"),
+	)
+	if err != nil {
+		return Result{Error: err}, runInfo
+	}
+
+	if len(valuesAndErrs) != len(weights) {
+		return Result{Error: errors.Wrapf(ErrBadInput, "got %d values and %d weights", len(valuesAndErrs), len(weights))}, runInfo
+	}
+
+	if allowed, isSet := maybeAllowedFaults.Uint64(); isSet {
+		allowedFaults = int(allowed)
+	} else {
+		allowedFaults = len(valuesAndErrs) - 1
+	}
+
+	var (
+		values        SliceParam
+		valuesWeights []decimal.Decimal
+		faults        int
+	)
+	for i, value := range valuesAndErrs {
+		if _, isErr := value.(error); isErr {
+			faults++
+			continue
+		}
+
+		values = append(values, value)
+		valuesWeights = append(valuesWeights, weights[i])
+	}
+
+	if faults > allowedFaults {
+		return Result{Error: errors.Wrapf(ErrTooManyErrors, "Number of faulty inputs %v to weighted mean task > number allowed faults %v", faults, allowedFaults)}, runInfo
+	} else if len(values) == 0 {
+		return Result{Error: errors.Wrap(ErrWrongInputCardinality, "values")}, runInfo
+	}
+
+	var decimalValues DecimalSliceParam
+	err = decimalValues.UnmarshalPipelineParam(values)
+	if err != nil {
+		return Result{Error: errors.Wrapf(ErrBadInput, "values: %v", err)}, runInfo
+	}
+
+	total := decimal.NewFromInt(0)
+	totalWeight := decimal.NewFromInt(0)
+	for i, val := range decimalValues {
+		if valuesWeights[i].IsNegative() {
+			return Result{Error: errors.Wrapf(ErrBadInput, "weight %d is negative", i)}, runInfo
+		}
+
+		total = total.Add(val.Mul(valuesWeights[i]))
+		totalWeight = totalWeight.Add(valuesWeights[i])
+	}
+
+	if totalWeight.IsZero() {
+		return Result{Error: errors.Wrap(ErrBadInput, "total weight is zero")}, runInfo
+	}
+
+	if precision, isSet := maybePrecision.Int32(); isSet {
+		return Result{Value: total.DivRound(totalWeight, precision)}, runInfo
+	}
+	// Note that decimal library defaults to rounding to 16 precision
+	// https://github.com/shopspring/decimal/blob/2568a29459476f824f35433dfbef158d6ad8618c/decimal.go#L44
+	return Result{Value: total.Div(totalWeight)}, runInfo
+}
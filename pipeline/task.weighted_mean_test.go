@@ -0,0 +1,94 @@
+package pipeline
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	log "github.com/InjectiveLabs/suplog"
+	"github.com/shopspring/decimal"
+)
+
+func TestWeightedMeanTask(t *testing.T) {
+	tests := []struct {
+		name          string
+		values        string
+		weights       string
+		allowedFaults string
+		inputs        []Result
+		expected      string
+		wantErr       error
+	}{
+		{
+			name:     "Weighted mean of values",
+			values:   `[100, 110]`,
+			weights:  `[3, 1]`,
+			expected: "102.5",
+		},
+		{
+			name:     "Weighted mean of inputs",
+			weights:  `["0.5", "0.5"]`,
+			inputs:   []Result{{Value: "10"}, {Value: "20"}},
+			expected: "15",
+		},
+		{
+			name:     "Faulty input is dropped with its weight",
+			weights:  `[1, 100, 3]`,
+			inputs:   []Result{{Value: "10"}, {Error: errors.New("source down")}, {Value: "20"}},
+			expected: "17.5",
+		},
+		{
+			name:          "Too many faulty inputs",
+			weights:       `[1, 1]`,
+			allowedFaults: "0",
+			inputs:        []Result{{Value: "10"}, {Error: errors.New("source down")}},
+			wantErr:       ErrTooManyErrors,
+		},
+		{
+			name:    "Reject unequal lengths",
+			values:  `[1, 2, 3]`,
+			weights: `[1, 2]`,
+			wantErr: ErrBadInput,
+		},
+		{
+			name:    "Reject zero total weight",
+			values:  `[1, 2]`,
+			weights: `[0, 0]`,
+			wantErr: ErrBadInput,
+		},
+		{
+			name:    "Reject negative weight",
+			values:  `[1, 2]`,
+			weights: `[2, -1]`,
+			wantErr: ErrBadInput,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			task := WeightedMeanTask{
+				BaseTask:      NewBaseTask(0, "weightedmean", nil, nil, 0),
+				Values:        tt.values,
+				Weights:       tt.weights,
+				AllowedFaults: tt.allowedFaults,
+			}
+
+			result, _ := task.Run(context.Background(), log.DefaultLogger, NewVarsFrom(nil), tt.inputs)
+			if tt.wantErr != nil {
+				if !errors.Is(result.Error, tt.wantErr) {
+					t.Errorf("WeightedMeanTask(%s, %s) expected error %v, got %v (%v)", tt.values, tt.weights, tt.wantErr, result.Error, result.Value)
+				}
+				return
+			}
+
+			if result.Error != nil {
+				t.Fatalf("WeightedMeanTask(%s, %s) unexpected error: %v", tt.values, tt.weights, result.Error)
+			}
+
+			expected := decimal.RequireFromString(tt.expected)
+			if value := result.Value.(decimal.Decimal); !value.Equal(expected) {
+				t.Errorf("WeightedMeanTask(%s, %s) = %s; want %s", tt.values, tt.weights, value, expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,57 @@
+package pipeline
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// ErrTaskTypeNotAllowed is returned when parsing a pipeline spec with a task type outside of the allow-list.
+var ErrTaskTypeNotAllowed = errors.New("task type not allowed")
+
+var (
+	allowedTaskTypes   map[TaskType]struct{}
+	allowedTaskTypesMu sync.RWMutex
+)
+
+// SetAllowedTaskTypes restricts the task types that pipeline specs may use, e.g. to keep feed configs
+// from less trusted sources from making arbitrary http requests. An empty list allows all task types.
+// Unknown task types are rejected, so a typo doesn't silently disallow a task.
+func SetAllowedTaskTypes(taskTypes []string) error {
+	var allowed map[TaskType]struct{}
+	if len(taskTypes) > 0 {
+		allowed = make(map[TaskType]struct{}, len(taskTypes))
+	}
+
+	for _, name := range taskTypes {
+		taskType := TaskType(strings.ToLower(strings.TrimSpace(name)))
+		if _, err := newTask(taskType, 0, ""); err != nil {
+			return err
+		}
+
+		allowed[taskType] = struct{}{}
+	}
+
+	allowedTaskTypesMu.Lock()
+	defer allowedTaskTypesMu.Unlock()
+
+	allowedTaskTypes = allowed
+	return nil
+}
+
+// checkTaskTypeAllowed fails if an allow-list is set and doesn't contain the task type.
+func checkTaskTypeAllowed(taskType TaskType) error {
+	allowedTaskTypesMu.RLock()
+	defer allowedTaskTypesMu.RUnlock()
+
+	if allowedTaskTypes == nil {
+		return nil
+	}
+
+	if _, ok := allowedTaskTypes[taskType]; !ok {
+		return errors.Wrapf(ErrTaskTypeNotAllowed, "%q", taskType)
+	}
+
+	return nil
+}
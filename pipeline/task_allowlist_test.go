@@ -0,0 +1,47 @@
+package pipeline
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestAllowedTaskTypes(t *testing.T) {
+	const source = `
+		fetch [type=http method=GET url="http://localhost"]
+		parse [type=jsonparse path="price"]
+
+		fetch -> parse
+	`
+
+	if err := SetAllowedTaskTypes([]string{"jsonparse", "Multiply"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer func() {
+		_ = SetAllowedTaskTypes(nil)
+	}()
+
+	if _, err := Parse(source); !errors.Is(err, ErrTaskTypeNotAllowed) {
+		t.Errorf("expected task type not allowed error, got %v", err)
+	}
+
+	if _, err := Parse(`
+		parse [type=jsonparse path="price" data="{}"]
+		scale [type=multiply times=10]
+
+		parse -> scale
+	`); err != nil {
+		t.Errorf("unexpected error for allowed task types: %v", err)
+	}
+
+	if err := SetAllowedTaskTypes([]string{"jsonparse", "bogus"}); err == nil {
+		t.Errorf("expected error for unknown task type")
+	}
+
+	if err := SetAllowedTaskTypes(nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, err := Parse(source); err != nil {
+		t.Errorf("unexpected error with all task types allowed: %v", err)
+	}
+}
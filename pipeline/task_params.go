@@ -491,7 +491,7 @@ func ToDecimal(input interface{}) (decimal.Decimal, error) {
 	case uint32:
 		return decimal.New(int64(v), 0), nil
 	case uint64:
-		return decimal.New(int64(v), 0), nil
+		return decimal.NewFromBigInt(new(big.Int).SetUint64(v), 0), nil
 	case float64:
 		return decimal.NewFromFloat(v), nil
 	case float32:
@@ -500,6 +500,10 @@ func ToDecimal(input interface{}) (decimal.Decimal, error) {
 		return decimal.NewFromBigInt(&v, 0), nil
 	case *big.Int:
 		return decimal.NewFromBigInt(v, 0), nil
+	case big.Float:
+		return bigFloatToDecimal(&v)
+	case *big.Float:
+		return bigFloatToDecimal(v)
 	case decimal.Decimal:
 		return v, nil
 	case *decimal.Decimal:
@@ -509,6 +513,20 @@ func ToDecimal(input interface{}) (decimal.Decimal, error) {
 	}
 }
 
+// bigFloatToDecimal converts the float exactly, without a round trip through float64.
+func bigFloatToDecimal(f *big.Float) (decimal.Decimal, error) {
+	if f.IsInf() {
+		return decimal.Decimal{}, errors.Errorf("infinite big.Float cannot be converted to decimal.Decimal")
+	}
+
+	if f.IsInt() {
+		n, _ := f.Int(nil)
+		return decimal.NewFromBigInt(n, 0), nil
+	}
+
+	return decimal.NewFromString(f.Text('f', -1))
+}
+
 type URLParam url.URL
 
 func (u *URLParam) UnmarshalPipelineParam(val interface{}) error {
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
+	"strings"
 	"time"
 
 	log "github.com/InjectiveLabs/suplog"
@@ -13,7 +14,44 @@ import (
 	"github.com/pkg/errors"
 )
 
-func ConnectWebSocket(ctx context.Context, websocketUrl, urlHeader string, maxRetries int) (conn *websocket.Conn, err error) {
+// ParseHeaders parses a list of "Key: Value" header pairs into http.Header.
+func ParseHeaders(pairs []string) (http.Header, error) {
+	header := http.Header{}
+	for _, pair := range pairs {
+		key, value, ok := strings.Cut(pair, ":")
+		key = strings.TrimSpace(key)
+		if !ok || len(key) == 0 {
+			return nil, errors.Errorf("invalid header %q, expected format is Key: Value", pair)
+		}
+
+		header.Add(key, strings.TrimSpace(value))
+	}
+
+	return header, nil
+}
+
+// DefaultWebSocketDialTimeout bounds a single websocket connection attempt, including the TLS
+// and websocket handshakes, when no dial timeout is configured.
+const DefaultWebSocketDialTimeout = 10 * time.Second
+
+// DefaultWebSocketReadLimit is the maximum size of a websocket message in bytes, when no read limit is configured.
+const DefaultWebSocketReadLimit int64 = 4 << 20
+
+// ConnectWebSocket dials the websocket, retrying up to maxRetries times. A non-empty urlHeader is sent
+// as Basic auth credentials, extraHeader is sent as is and takes precedence over the Basic auth.
+// enableCompression negotiates permessage-deflate with the server. Every attempt is bounded by dialTimeout
+// (DefaultWebSocketDialTimeout if not positive), so a black-holed endpoint doesn't stall the retries.
+// Messages of the connection larger than readLimit (DefaultWebSocketReadLimit if not positive) fail the read
+// and close the connection, so a misbehaving server can't exhaust the memory.
+func ConnectWebSocket(
+	ctx context.Context,
+	websocketUrl, urlHeader string,
+	extraHeader http.Header,
+	maxRetries int,
+	enableCompression bool,
+	dialTimeout time.Duration,
+	readLimit int64,
+) (conn *websocket.Conn, err error) {
 	u, err := url.Parse(websocketUrl)
 	if err != nil {
 		return &websocket.Conn{}, errors.Wrapf(err, "can not parse WS url %s: %v", websocketUrl, err)
@@ -24,11 +62,26 @@ func ConnectWebSocket(ctx context.Context, websocketUrl, urlHeader string, maxRe
 		header.Add("Authorization", "Basic "+base64.StdEncoding.EncodeToString([]byte(urlHeader)))
 	}
 
-	dialer := websocket.DefaultDialer
-	dialer.EnableCompression = true
+	for key, values := range extraHeader {
+		header[key] = values
+	}
+
+	// dedicated copy, so the global dialer shared with other packages is left intact
+	dialer := *websocket.DefaultDialer
+	dialer.EnableCompression = enableCompression
+
+	if dialTimeout <= 0 {
+		dialTimeout = DefaultWebSocketDialTimeout
+	}
+	dialer.HandshakeTimeout = dialTimeout
+
+	if readLimit <= 0 {
+		readLimit = DefaultWebSocketReadLimit
+	}
+
 	retries := 0
 	for {
-		conn, _, err = websocket.DefaultDialer.DialContext(ctx, u.String(), header)
+		conn, err = dialWebSocket(ctx, &dialer, u.String(), header, dialTimeout)
 		if ctx.Err() != nil {
 			return nil, ctx.Err()
 		} else if err != nil {
@@ -46,7 +99,23 @@ func ConnectWebSocket(ctx context.Context, websocketUrl, urlHeader string, maxRe
 			}
 		} else {
 			log.Infof("Connected to WebSocket server")
+			conn.SetReadLimit(readLimit)
 			return
 		}
 	}
 }
+
+// dialWebSocket makes a single connection attempt, cancelled after dialTimeout.
+func dialWebSocket(
+	ctx context.Context,
+	dialer *websocket.Dialer,
+	websocketUrl string,
+	header http.Header,
+	dialTimeout time.Duration,
+) (*websocket.Conn, error) {
+	ctx, cancelFn := context.WithTimeout(ctx, dialTimeout)
+	defer cancelFn()
+
+	conn, _, err := dialer.DialContext(ctx, websocketUrl, header)
+	return conn, err
+}
@@ -0,0 +1,114 @@
+package pipeline
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+func TestConnectWebSocketCompression(t *testing.T) {
+	extensions := make(chan string, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		extensions <- r.Header.Get("Sec-WebSocket-Extensions")
+
+		conn, err := (&websocket.Upgrader{}).Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		conn.Close()
+	}))
+	defer srv.Close()
+
+	wsURL := "ws" + strings.TrimPrefix(srv.URL, "http")
+
+	for _, enableCompression := range []bool{true, false} {
+		conn, err := ConnectWebSocket(context.Background(), wsURL, "", nil, 0, enableCompression, 0, 0)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		conn.Close()
+
+		if negotiated := strings.Contains(<-extensions, "permessage-deflate"); negotiated != enableCompression {
+			t.Errorf("expected compression negotiated %v, got %v", enableCompression, negotiated)
+		}
+	}
+
+	if websocket.DefaultDialer.EnableCompression {
+		t.Errorf("expected the global dialer to be left intact")
+	}
+}
+
+func TestConnectWebSocketDialTimeout(t *testing.T) {
+	// accepts connections but never completes the handshake
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	defer listener.Close()
+
+	go func() {
+		var conns []net.Conn
+		defer func() {
+			for _, conn := range conns {
+				conn.Close()
+			}
+		}()
+
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			conns = append(conns, conn)
+		}
+	}()
+
+	ts := time.Now()
+	_, err = ConnectWebSocket(context.Background(), "ws://"+listener.Addr().String(), "", nil, 0, false, 100*time.Millisecond, 0)
+	if err == nil {
+		t.Fatal("expected error for a hung handshake")
+	} else if elapsed := time.Since(ts); elapsed > 2*time.Second {
+		t.Errorf("expected the attempt to time out quickly, took %s", elapsed)
+	}
+}
+
+func TestConnectWebSocketReadLimit(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := (&websocket.Upgrader{}).Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		for _, size := range []int{16, 1024} {
+			if err := conn.WriteMessage(websocket.TextMessage, []byte(strings.Repeat("a", size))); err != nil {
+				return
+			}
+		}
+
+		// wait for the client to drop the connection
+		_, _, _ = conn.ReadMessage()
+	}))
+	defer srv.Close()
+
+	conn, err := ConnectWebSocket(context.Background(), "ws"+strings.TrimPrefix(srv.URL, "http"), "", nil, 0, false, 0, 64)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer conn.Close()
+
+	if _, message, err := conn.ReadMessage(); err != nil || len(message) != 16 {
+		t.Fatalf("expected a message within the read limit, got %d bytes, error %v", len(message), err)
+	}
+
+	if _, _, err := conn.ReadMessage(); !errors.Is(err, websocket.ErrReadLimit) {
+		t.Errorf("expected read limit error for a message over the limit, got %v", err)
+	}
+}